@@ -17,9 +17,13 @@ func init() {
 }
 
 func main() {
+	// The Window times double clicks with the Clock of the Scheduler.
+	c := clock.New()
+
 	// Create and configure the Scheduler.
 	scheduler.New(
 		glfw.NewWindow(
+			glfw.Clock(c),
 			glfw.Width(800),
 			glfw.Height(600),
 			glfw.Title("Todo"),
@@ -29,6 +33,6 @@ func main() {
 			nano.AddFont("Roboto Light", filepath.Join("third_party", "fonts", "Roboto", "Roboto-Light.ttf")),
 		),
 		ctrl.AppRenderer(model.NewSample()),
-		clock.New(),
+		c,
 	).Listen()
 }
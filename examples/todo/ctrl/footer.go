@@ -23,7 +23,7 @@ func Footer(appModel *model.App, styles *Styles) spec.ReadWriter {
 		opts.FontColor(0xccccccff),
 		opts.FontFace("Roboto"),
 		opts.FontSize(18),
-		opts.HAlign(spec.AlignCenter),
+		opts.Justify(spec.JustifySpaceBetween),
 		opts.Padding(5),
 
 		opts.Child(ctrl.Label(
@@ -31,28 +31,32 @@ func Footer(appModel *model.App, styles *Styles) spec.ReadWriter {
 			opts.Text(strconv.Itoa(len(appModel.CurrentItems()))+" items"),
 			styles.Button,
 		)),
-		opts.Child(ctrl.Button(
-			opts.Key(AllButton),
-			opts.Text("All"),
-			styles.Button,
-			styles.SelectedFilter(appModel.Showing() == model.AllItems),
-			opts.OnClick(events.EmptyHandler(appModel.ShowAllItems)),
-		)),
-		opts.Child(ctrl.Button(
-			opts.Key(ActiveButton),
-			opts.Text("Active"),
-			opts.IsDisabled(len(appModel.ActiveItems()) == 0),
-			styles.Button,
-			styles.SelectedFilter(appModel.Showing() == model.ActiveItems),
-			opts.OnClick(events.EmptyHandler(appModel.ShowActiveItems)),
-		)),
-		opts.Child(ctrl.Button(
-			opts.Key(CompletedButton),
-			opts.Text("Completed"),
-			opts.IsDisabled(len(appModel.CompletedItems()) == 0),
-			styles.Button,
-			styles.SelectedFilter(appModel.Showing() == model.CompletedItems),
-			opts.OnClick(events.EmptyHandler(appModel.ShowCompletedItems)),
+		opts.Child(ctrl.HBox(
+			opts.Key("Filters"),
+			opts.Gutter(10),
+			opts.Child(ctrl.Button(
+				opts.Key(AllButton),
+				opts.Text("All"),
+				styles.Button,
+				styles.SelectedFilter(appModel.Showing() == model.AllItems),
+				opts.OnClick(events.EmptyHandler(appModel.ShowAllItems)),
+			)),
+			opts.Child(ctrl.Button(
+				opts.Key(ActiveButton),
+				opts.Text("Active"),
+				opts.IsDisabled(len(appModel.ActiveItems()) == 0),
+				styles.Button,
+				styles.SelectedFilter(appModel.Showing() == model.ActiveItems),
+				opts.OnClick(events.EmptyHandler(appModel.ShowActiveItems)),
+			)),
+			opts.Child(ctrl.Button(
+				opts.Key(CompletedButton),
+				opts.Text("Completed"),
+				opts.IsDisabled(len(appModel.CompletedItems()) == 0),
+				styles.Button,
+				styles.SelectedFilter(appModel.Showing() == model.CompletedItems),
+				opts.OnClick(events.EmptyHandler(appModel.ShowCompletedItems)),
+			)),
 		)),
 		opts.Child(ctrl.Button(
 			opts.Key(ClearCompletedButton),
@@ -4,7 +4,10 @@ import (
 	"github.com/waybeams/assert"
 	"github.com/waybeams/waybeams/examples/todo/ctrl"
 	"github.com/waybeams/waybeams/examples/todo/model"
+	surface "github.com/waybeams/waybeams/pkg/env/fake"
 	"github.com/waybeams/waybeams/pkg/events"
+	"github.com/waybeams/waybeams/pkg/layout"
+	"github.com/waybeams/waybeams/pkg/opts"
 	"github.com/waybeams/waybeams/pkg/spec"
 	"testing"
 )
@@ -96,4 +99,14 @@ func TestFooterSpec(t *testing.T) {
 		btn = spec.FirstByKey(footer, ctrl.ClearCompletedButton)
 		assert.Equal(btn.State(), "disabled")
 	})
+
+	t.Run("Right aligns Clear Completed", func(t *testing.T) {
+		footer := ctrl.Footer(createModel(), ctrl.CreateStyles())
+		spec.Apply(footer, opts.Width(800))
+		layout.Layout(footer, surface.NewSurface())
+
+		btn := spec.FirstByKey(footer, ctrl.ClearCompletedButton)
+		assert.Equal(btn.X()+btn.Width(), 795)
+		assert.Equal(spec.FirstByKey(footer, "Item Count").X(), 5)
+	})
 }
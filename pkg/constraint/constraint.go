@@ -0,0 +1,229 @@
+// Package constraint is a Cassowary linear constraint solver.
+//
+// Variables are related to one another with linear equalities and
+// inequalities, each of which has a Strength. Required constraints must be
+// satisfied, while the Solver will satisfy as many of the weaker
+// constraints as it can, favoring stronger ones.
+//
+// The implementation follows the incremental simplex approach described by
+// Badros, Borning and Stuckey in "The Cassowary Linear Arithmetic Constraint
+// Solving Algorithm".
+package constraint
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Strength determines how the Solver chooses between constraints that cannot
+// all be satisfied.
+type Strength float64
+
+const (
+	Weak     Strength = 1
+	Medium   Strength = 1000
+	Strong   Strength = 1000000
+	Required Strength = 1001001000
+)
+
+// NewStrength creates a Strength from its strong, medium and weak parts,
+// each of which is clamped to the range [0, 1000]. No combination of weaker
+// parts will ever outweigh a stronger part.
+func NewStrength(strong, medium, weak float64) Strength {
+	var clamp = func(value float64) float64 {
+		if value < 0 {
+			return 0
+		}
+		if value > 1000 {
+			return 1000
+		}
+		return value
+	}
+	return Strength(clamp(strong)*float64(Strong) + clamp(medium)*float64(Medium) + clamp(weak)*float64(Weak))
+}
+
+func clipStrength(strength Strength) Strength {
+	if strength < 0 {
+		return 0
+	}
+	if strength > Required {
+		return Required
+	}
+	return strength
+}
+
+// Variable is a value that will be updated by a Solver.
+type Variable struct {
+	name  string
+	value float64
+}
+
+// Name returns the name that was provided to NewVariable.
+func (v *Variable) Name() string {
+	return v.name
+}
+
+// Value returns the value that was assigned by the most recent call to
+// Solver.UpdateVariables.
+func (v *Variable) Value() float64 {
+	return v.value
+}
+
+// NewVariable creates a Variable with the provided name, which is only used
+// for debugging.
+func NewVariable(name string) *Variable {
+	return &Variable{name: name}
+}
+
+// Term is a Variable multiplied by a coefficient.
+type Term struct {
+	Coefficient float64
+	Variable    *Variable
+}
+
+// Expression is a sum of Terms and a constant.
+type Expression struct {
+	Constant float64
+	Terms    []Term
+}
+
+// NewExpression creates an Expression from the provided constant and Terms.
+func NewExpression(constant float64, terms ...Term) Expression {
+	return Expression{Constant: constant, Terms: terms}
+}
+
+// VariableExpression creates an Expression that only contains the provided
+// Variable.
+func VariableExpression(variable *Variable) Expression {
+	return NewExpression(0, Term{Coefficient: 1, Variable: variable})
+}
+
+// Add returns the sum of this Expression and the provided Expression.
+func (e Expression) Add(other Expression) Expression {
+	terms := append(append([]Term{}, e.Terms...), other.Terms...)
+	return Expression{Constant: e.Constant + other.Constant, Terms: terms}
+}
+
+// Scale returns this Expression multiplied by the provided value.
+func (e Expression) Scale(value float64) Expression {
+	terms := make([]Term, len(e.Terms))
+	for index, term := range e.Terms {
+		terms[index] = Term{Coefficient: term.Coefficient * value, Variable: term.Variable}
+	}
+	return Expression{Constant: e.Constant * value, Terms: terms}
+}
+
+// Sub returns the difference of this Expression and the provided Expression.
+func (e Expression) Sub(other Expression) Expression {
+	return e.Add(other.Scale(-1))
+}
+
+// IsConstant returns true if the Expression has no Variables.
+func (e Expression) IsConstant() bool {
+	return len(e.reduce().Terms) == 0
+}
+
+// Value returns the value of the Expression using the current Variable
+// values.
+func (e Expression) Value() float64 {
+	result := e.Constant
+	for _, term := range e.Terms {
+		result += term.Coefficient * term.Variable.Value()
+	}
+	return result
+}
+
+// reduce combines Terms that refer to the same Variable.
+func (e Expression) reduce() Expression {
+	indexes := make(map[*Variable]int)
+	terms := []Term{}
+	for _, term := range e.Terms {
+		if index, ok := indexes[term.Variable]; ok {
+			terms[index].Coefficient += term.Coefficient
+			continue
+		}
+		indexes[term.Variable] = len(terms)
+		terms = append(terms, term)
+	}
+	result := Expression{Constant: e.Constant}
+	for _, term := range terms {
+		if !nearZero(term.Coefficient) {
+			result.Terms = append(result.Terms, term)
+		}
+	}
+	return result
+}
+
+func (e Expression) String() string {
+	parts := []string{}
+	for _, term := range e.Terms {
+		parts = append(parts, formatFloat(term.Coefficient)+" * "+term.Variable.Name())
+	}
+	parts = append(parts, formatFloat(e.Constant))
+	return strings.Join(parts, " + ")
+}
+
+// Operator is the relationship that a Constraint enforces between its
+// Expression and zero.
+type Operator int
+
+const (
+	LessThanOrEqual Operator = iota
+	GreaterThanOrEqual
+	Equal
+)
+
+func (o Operator) String() string {
+	switch o {
+	case LessThanOrEqual:
+		return "<="
+	case GreaterThanOrEqual:
+		return ">="
+	default:
+		return "=="
+	}
+}
+
+// Constraint is a linear relationship of the form "expression op 0".
+type Constraint struct {
+	expression Expression
+	operator   Operator
+	strength   Strength
+}
+
+// Expression returns the reduced Expression of the Constraint.
+func (c *Constraint) Expression() Expression {
+	return c.expression
+}
+
+// Operator returns the relationship that the Constraint enforces.
+func (c *Constraint) Operator() Operator {
+	return c.operator
+}
+
+// Strength returns the Strength of the Constraint.
+func (c *Constraint) Strength() Strength {
+	return c.strength
+}
+
+func (c *Constraint) String() string {
+	return c.expression.String() + " " + c.operator.String() + " 0 @ " + formatFloat(float64(c.strength))
+}
+
+// NewConstraint creates a Constraint that enforces "expression op 0".
+func NewConstraint(expression Expression, operator Operator, strength Strength) *Constraint {
+	return &Constraint{
+		expression: expression.reduce(),
+		operator:   operator,
+		strength:   clipStrength(strength),
+	}
+}
+
+// Relation creates a Constraint that enforces "lhs op rhs".
+func Relation(lhs Expression, operator Operator, rhs Expression, strength Strength) *Constraint {
+	return NewConstraint(lhs.Sub(rhs), operator, strength)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,232 @@
+package constraint
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Resolver returns the Expression for a name that appears in the source of
+// a constraint (e.g., "sibling.right").
+type Resolver func(name string) (Expression, error)
+
+var strengthsByName = map[string]Strength{
+	"required": Required,
+	"strong":   Strong,
+	"medium":   Medium,
+	"weak":     Weak,
+}
+
+// Parse creates a Constraint from source text like "left == sibling.right + 8"
+// or "width >= 120 @ medium".
+//
+// Each side of the relation (==, >= or <=) is a linear expression of numbers
+// and names, combined with +, -, *, / and parentheses. Names are converted
+// to Expressions by the provided Resolver. The optional strength after "@"
+// is one of required, strong, medium, weak or a number, and defaults to
+// required.
+func Parse(source string, resolve Resolver) (*Constraint, error) {
+	p := &parser{input: []rune(source), resolve: resolve}
+	return p.parse()
+}
+
+type parser struct {
+	input    []rune
+	position int
+	resolve  Resolver
+}
+
+func (p *parser) errorf(reason string) error {
+	return errors.New("invalid constraint: " + reason)
+}
+
+func (p *parser) skipWhitespace() {
+	for p.position < len(p.input) && unicode.IsSpace(p.input[p.position]) {
+		p.position++
+	}
+}
+
+func (p *parser) done() bool {
+	p.skipWhitespace()
+	return p.position >= len(p.input)
+}
+
+func (p *parser) peek() rune {
+	if p.done() {
+		return 0
+	}
+	return p.input[p.position]
+}
+
+func (p *parser) consume(token string) bool {
+	p.skipWhitespace()
+	if strings.HasPrefix(string(p.input[p.position:]), token) {
+		p.position += len([]rune(token))
+		return true
+	}
+	return false
+}
+
+func (p *parser) parse() (*Constraint, error) {
+	lhs, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	var operator Operator
+	switch {
+	case p.consume("=="):
+		operator = Equal
+	case p.consume(">="):
+		operator = GreaterThanOrEqual
+	case p.consume("<="):
+		operator = LessThanOrEqual
+	default:
+		return nil, p.errorf("expected ==, >= or <=")
+	}
+
+	rhs, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	strength := Required
+	if p.consume("@") {
+		strength, err = p.parseStrength()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !p.done() {
+		return nil, p.errorf("unexpected '" + string(p.peek()) + "'")
+	}
+	return Relation(lhs, operator, rhs, strength), nil
+}
+
+func (p *parser) parseStrength() (Strength, error) {
+	word := p.parseWord()
+	if strength, ok := strengthsByName[word]; ok {
+		return strength, nil
+	}
+	value, err := strconv.ParseFloat(word, 64)
+	if err != nil || value < 0 {
+		return 0, p.errorf("unknown strength \"" + word + "\"")
+	}
+	return Strength(value), nil
+}
+
+func (p *parser) parseExpression() (Expression, error) {
+	result := Expression{}
+	sign := 1.0
+	if p.consume("-") {
+		sign = -1.0
+	} else {
+		p.consume("+")
+	}
+
+	for {
+		term, err := p.parseTerm()
+		if err != nil {
+			return Expression{}, err
+		}
+		result = result.Add(term.Scale(sign))
+
+		if p.consume("+") {
+			sign = 1.0
+		} else if p.consume("-") {
+			sign = -1.0
+		} else {
+			return result, nil
+		}
+	}
+}
+
+func (p *parser) parseTerm() (Expression, error) {
+	result, err := p.parseFactor()
+	if err != nil {
+		return Expression{}, err
+	}
+
+	for {
+		if p.consume("*") {
+			factor, err := p.parseFactor()
+			if err != nil {
+				return Expression{}, err
+			}
+			switch {
+			case factor.IsConstant():
+				result = result.Scale(factor.Constant)
+			case result.IsConstant():
+				result = factor.Scale(result.Constant)
+			default:
+				return Expression{}, p.errorf("cannot multiply two variables")
+			}
+		} else if p.consume("/") {
+			factor, err := p.parseFactor()
+			if err != nil {
+				return Expression{}, err
+			}
+			if !factor.IsConstant() {
+				return Expression{}, p.errorf("cannot divide by a variable")
+			}
+			if nearZero(factor.Constant) {
+				return Expression{}, p.errorf("cannot divide by zero")
+			}
+			result = result.Scale(1 / factor.Constant)
+		} else {
+			return result, nil
+		}
+	}
+}
+
+func (p *parser) parseFactor() (Expression, error) {
+	if p.consume("(") {
+		result, err := p.parseExpression()
+		if err != nil {
+			return Expression{}, err
+		}
+		if !p.consume(")") {
+			return Expression{}, p.errorf("expected ')'")
+		}
+		return result, nil
+	}
+
+	word := p.parseWord()
+	if word == "" {
+		if p.done() {
+			return Expression{}, p.errorf("unexpected end")
+		}
+		return Expression{}, p.errorf("unexpected '" + string(p.peek()) + "'")
+	}
+
+	first := []rune(word)[0]
+	if unicode.IsDigit(first) || first == '.' {
+		value, err := strconv.ParseFloat(word, 64)
+		if err != nil {
+			return Expression{}, p.errorf("invalid number \"" + word + "\"")
+		}
+		return NewExpression(value), nil
+	}
+
+	result, err := p.resolve(word)
+	if err != nil {
+		return Expression{}, p.errorf(err.Error())
+	}
+	return result, nil
+}
+
+// parseWord reads a number or a name, which may contain dots.
+func (p *parser) parseWord() string {
+	p.skipWhitespace()
+	start := p.position
+	for p.position < len(p.input) {
+		char := p.input[p.position]
+		if !unicode.IsLetter(char) && !unicode.IsDigit(char) && char != '_' && char != '.' {
+			break
+		}
+		p.position++
+	}
+	return string(p.input[start:p.position])
+}
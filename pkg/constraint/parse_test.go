@@ -0,0 +1,105 @@
+package constraint_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/waybeams/assert"
+	"github.com/waybeams/waybeams/pkg/constraint"
+)
+
+func TestParse(t *testing.T) {
+	var createResolver = func() (constraint.Resolver, map[string]*constraint.Variable) {
+		variables := make(map[string]*constraint.Variable)
+		var resolveVariable = func(name string) constraint.Expression {
+			if variables[name] == nil {
+				variables[name] = constraint.NewVariable(name)
+			}
+			return constraint.VariableExpression(variables[name])
+		}
+		return func(name string) (constraint.Expression, error) {
+			if name == "unknown" {
+				return constraint.Expression{}, errors.New("unknown name \"" + name + "\"")
+			}
+			if name == "sibling.right" {
+				// Derived attributes resolve to expressions.
+				return resolveVariable("sibling.left").Add(constraint.NewExpression(50)), nil
+			}
+			return resolveVariable(name), nil
+		}, variables
+	}
+
+	var solve = func(sources ...string) map[string]*constraint.Variable {
+		resolve, variables := createResolver()
+		s := constraint.NewSolver()
+		for _, source := range sources {
+			c, err := constraint.Parse(source, resolve)
+			if err != nil {
+				panic(err)
+			}
+			if err := s.AddConstraint(c); err != nil {
+				panic(err)
+			}
+		}
+		s.UpdateVariables()
+		return variables
+	}
+
+	t.Run("Simple relation", func(t *testing.T) {
+		resolve, _ := createResolver()
+		c, err := constraint.Parse("width >= 120 @ medium", resolve)
+		assert.Nil(err)
+		assert.Equal(c.Operator(), constraint.GreaterThanOrEqual)
+		assert.Equal(c.Strength(), constraint.Medium)
+		assert.Equal(c.Expression().Constant, -120)
+	})
+
+	t.Run("Defaults to required", func(t *testing.T) {
+		resolve, _ := createResolver()
+		c, err := constraint.Parse("width == 10", resolve)
+		assert.Nil(err)
+		assert.Equal(c.Strength(), constraint.Required)
+	})
+
+	t.Run("Numeric strength", func(t *testing.T) {
+		resolve, _ := createResolver()
+		c, err := constraint.Parse("width <= 10 @ 250", resolve)
+		assert.Nil(err)
+		assert.Equal(c.Operator(), constraint.LessThanOrEqual)
+		assert.Equal(c.Strength(), 250)
+	})
+
+	t.Run("Resolves dotted names", func(t *testing.T) {
+		variables := solve("sibling.left == 10", "left == sibling.right + 8")
+		assert.Equal(variables["left"].Value(), 68)
+	})
+
+	t.Run("Arithmetic", func(t *testing.T) {
+		variables := solve("a == 10", "b == 2 * a - (a / 2) + 1.5", "c == -a * 3")
+		assert.Equal(variables["b"].Value(), 16.5)
+		assert.Equal(variables["c"].Value(), -30)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		resolve, _ := createResolver()
+		sources := map[string]string{
+			"width":                 "expected ==, >= or <=",
+			"width == ":             "unexpected end",
+			"width == 10 @ loud":    "unknown strength \"loud\"",
+			"width == a * b":        "cannot multiply two variables",
+			"width == 10 / a":       "cannot divide by a variable",
+			"width == 10 / 0":       "cannot divide by zero",
+			"width == (10":          "expected '\\)'",
+			"width == 10 10":        "unexpected '1'",
+			"width == unknown":      "unknown name \"unknown\"",
+			"width == 10 $":         "unexpected '\\$'",
+			"width == 1.2.3":        "invalid number \"1.2.3\"",
+			"width == 10 @ medium!": "unexpected '!'",
+		}
+		for source, message := range sources {
+			_, err := constraint.Parse(source, resolve)
+			assert.NotNil(err, source)
+			assert.Match("invalid constraint: "+message, err.Error())
+		}
+	})
+}
@@ -0,0 +1,646 @@
+package constraint
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+var (
+	ErrBadRequiredStrength     = errors.New("edit variables cannot have a Required strength")
+	ErrDuplicateConstraint     = errors.New("duplicate constraint")
+	ErrDuplicateEditVariable   = errors.New("duplicate edit variable")
+	ErrUnknownConstraint       = errors.New("unknown constraint")
+	ErrUnknownEditVariable     = errors.New("unknown edit variable")
+	ErrUnsatisfiableConstraint = errors.New("unsatisfiable constraint")
+
+	// errUnbounded and errDualOptimize indicate a bug in the Solver rather
+	// than a problem with the provided constraints.
+	errUnbounded    = errors.New("the objective is unbounded")
+	errDualOptimize = errors.New("dual optimize failed")
+)
+
+type symbolKind int
+
+const (
+	invalidSymbol symbolKind = iota
+	externalSymbol
+	slackSymbol
+	errorSymbol
+	dummySymbol
+)
+
+// symbol is a column in the simplex tableau. Symbols are ordered by id so
+// that the Solver makes the same choices every time it is run.
+type symbol struct {
+	id   int
+	kind symbolKind
+}
+
+func (s symbol) isValid() bool {
+	return s.kind != invalidSymbol
+}
+
+// isPivotable returns true for the symbols that may be chosen to enter or
+// leave the basis when adding or removing constraints.
+func (s symbol) isPivotable() bool {
+	return s.kind == slackSymbol || s.kind == errorSymbol
+}
+
+func sortSymbols(symbols []symbol) []symbol {
+	sort.Slice(symbols, func(i, j int) bool {
+		return symbols[i].id < symbols[j].id
+	})
+	return symbols
+}
+
+func nearZero(value float64) bool {
+	return math.Abs(value) < 1.0e-8
+}
+
+// row is a linear expression of symbols, stored as "constant + sum(cells)".
+type row struct {
+	cells    map[symbol]float64
+	constant float64
+}
+
+func newRow(constant float64) *row {
+	return &row{cells: make(map[symbol]float64), constant: constant}
+}
+
+func (r *row) copy() *row {
+	result := newRow(r.constant)
+	for s, coefficient := range r.cells {
+		result.cells[s] = coefficient
+	}
+	return result
+}
+
+func (r *row) symbols() []symbol {
+	symbols := make([]symbol, 0, len(r.cells))
+	for s := range r.cells {
+		symbols = append(symbols, s)
+	}
+	return sortSymbols(symbols)
+}
+
+func (r *row) add(value float64) float64 {
+	r.constant += value
+	return r.constant
+}
+
+func (r *row) insertSymbol(s symbol, coefficient float64) {
+	value := r.cells[s] + coefficient
+	if nearZero(value) {
+		delete(r.cells, s)
+		return
+	}
+	r.cells[s] = value
+}
+
+func (r *row) insertRow(other *row, coefficient float64) {
+	r.constant += other.constant * coefficient
+	for s, value := range other.cells {
+		r.insertSymbol(s, value*coefficient)
+	}
+}
+
+func (r *row) remove(s symbol) {
+	delete(r.cells, s)
+}
+
+func (r *row) reverseSign() {
+	r.constant = -r.constant
+	for s, value := range r.cells {
+		r.cells[s] = -value
+	}
+}
+
+// solveFor rearranges the row so that it is expressed in terms of the
+// provided symbol, which is removed from the row.
+func (r *row) solveFor(s symbol) {
+	coefficient := -1.0 / r.cells[s]
+	delete(r.cells, s)
+	r.constant *= coefficient
+	for other, value := range r.cells {
+		r.cells[other] = value * coefficient
+	}
+}
+
+// solveForPair solves the row, which is currently the value of lhs, for rhs.
+func (r *row) solveForPair(lhs, rhs symbol) {
+	r.insertSymbol(lhs, -1.0)
+	r.solveFor(rhs)
+}
+
+func (r *row) coefficientFor(s symbol) float64 {
+	return r.cells[s]
+}
+
+func (r *row) substitute(s symbol, other *row) {
+	if coefficient, ok := r.cells[s]; ok {
+		delete(r.cells, s)
+		r.insertRow(other, coefficient)
+	}
+}
+
+// tag holds the symbols that were introduced into the tableau for a
+// constraint, so that its effects can be removed later.
+type tag struct {
+	marker symbol
+	other  symbol
+}
+
+type editInfo struct {
+	constant   float64
+	constraint *Constraint
+	tag        tag
+}
+
+// Solver incrementally solves a system of Constraints.
+type Solver struct {
+	artificial     *row
+	constraints    map[*Constraint]tag
+	edits          map[*Variable]*editInfo
+	infeasibleRows []symbol
+	lastID         int
+	objective      *row
+	rows           map[symbol]*row
+	variables      map[*Variable]symbol
+}
+
+// AddConstraint adds the provided Constraint to the Solver. An error is
+// returned, and the Solver is left unchanged, if the Constraint has already
+// been added or if it is a Required Constraint that cannot be satisfied.
+func (s *Solver) AddConstraint(c *Constraint) error {
+	if _, ok := s.constraints[c]; ok {
+		return ErrDuplicateConstraint
+	}
+
+	r, t := s.createRow(c)
+	subject := s.chooseSubject(r, t)
+
+	if !subject.isValid() && allDummies(r) {
+		if !nearZero(r.constant) {
+			s.removeObjectiveEffects(c, t)
+			return ErrUnsatisfiableConstraint
+		}
+		subject = t.marker
+	}
+
+	if !subject.isValid() {
+		// Finding a feasible solution with an artificial variable pivots the
+		// existing rows, so we restore them if the row cannot be added.
+		rows, objective := s.copyRows(), s.objective.copy()
+		if !s.addWithArtificialVariable(r) {
+			s.rows, s.objective, s.infeasibleRows = rows, objective, nil
+			s.removeObjectiveEffects(c, t)
+			return ErrUnsatisfiableConstraint
+		}
+	} else {
+		r.solveFor(subject)
+		s.substitute(subject, r)
+		s.rows[subject] = r
+	}
+
+	s.constraints[c] = t
+	return s.optimize(s.objective)
+}
+
+// RemoveConstraint removes a Constraint that was previously added.
+func (s *Solver) RemoveConstraint(c *Constraint) error {
+	t, ok := s.constraints[c]
+	if !ok {
+		return ErrUnknownConstraint
+	}
+	delete(s.constraints, c)
+	s.removeObjectiveEffects(c, t)
+
+	if _, ok := s.rows[t.marker]; ok {
+		delete(s.rows, t.marker)
+	} else {
+		leaving := s.getMarkerLeavingSymbol(t.marker)
+		if !leaving.isValid() {
+			return errUnbounded
+		}
+		r := s.rows[leaving]
+		delete(s.rows, leaving)
+		r.solveForPair(leaving, t.marker)
+		s.substitute(t.marker, r)
+	}
+	return s.optimize(s.objective)
+}
+
+// HasConstraint returns true if the provided Constraint has been added.
+func (s *Solver) HasConstraint(c *Constraint) bool {
+	_, ok := s.constraints[c]
+	return ok
+}
+
+// AddEditVariable allows the value of the provided Variable to be suggested
+// with SuggestValue. Edit variables cannot be Required.
+func (s *Solver) AddEditVariable(v *Variable, strength Strength) error {
+	if _, ok := s.edits[v]; ok {
+		return ErrDuplicateEditVariable
+	}
+	strength = clipStrength(strength)
+	if strength == Required {
+		return ErrBadRequiredStrength
+	}
+
+	c := NewConstraint(VariableExpression(v), Equal, strength)
+	err := s.AddConstraint(c)
+	if err != nil {
+		return err
+	}
+	s.edits[v] = &editInfo{constraint: c, tag: s.constraints[c]}
+	return nil
+}
+
+// RemoveEditVariable removes an edit Variable that was previously added.
+func (s *Solver) RemoveEditVariable(v *Variable) error {
+	info, ok := s.edits[v]
+	if !ok {
+		return ErrUnknownEditVariable
+	}
+	delete(s.edits, v)
+	return s.RemoveConstraint(info.constraint)
+}
+
+// HasEditVariable returns true if the provided Variable has been added with
+// AddEditVariable.
+func (s *Solver) HasEditVariable(v *Variable) bool {
+	_, ok := s.edits[v]
+	return ok
+}
+
+// SuggestValue requests that the provided edit Variable have the provided
+// value. The request will be honored as closely as the other constraints,
+// and the strength of the edit Variable, allow.
+func (s *Solver) SuggestValue(v *Variable, value float64) error {
+	info, ok := s.edits[v]
+	if !ok {
+		return ErrUnknownEditVariable
+	}
+
+	delta := value - info.constant
+	info.constant = value
+
+	if r, ok := s.rows[info.tag.marker]; ok {
+		if r.add(-delta) < 0 {
+			s.infeasibleRows = append(s.infeasibleRows, info.tag.marker)
+		}
+		return s.dualOptimize()
+	}
+
+	if r, ok := s.rows[info.tag.other]; ok {
+		if r.add(delta) < 0 {
+			s.infeasibleRows = append(s.infeasibleRows, info.tag.other)
+		}
+		return s.dualOptimize()
+	}
+
+	for _, rowSymbol := range s.rowSymbols() {
+		r := s.rows[rowSymbol]
+		coefficient := r.coefficientFor(info.tag.marker)
+		if coefficient != 0 && r.add(delta*coefficient) < 0 && rowSymbol.kind != externalSymbol {
+			s.infeasibleRows = append(s.infeasibleRows, rowSymbol)
+		}
+	}
+	return s.dualOptimize()
+}
+
+// UpdateVariables assigns the solved value to every Variable that appears
+// in a Constraint.
+func (s *Solver) UpdateVariables() {
+	for v, variableSymbol := range s.variables {
+		if r, ok := s.rows[variableSymbol]; ok {
+			v.value = r.constant
+		} else {
+			v.value = 0
+		}
+	}
+}
+
+func (s *Solver) newSymbol(kind symbolKind) symbol {
+	s.lastID++
+	return symbol{id: s.lastID, kind: kind}
+}
+
+func (s *Solver) copyRows() map[symbol]*row {
+	result := make(map[symbol]*row, len(s.rows))
+	for rowSymbol, r := range s.rows {
+		result[rowSymbol] = r.copy()
+	}
+	return result
+}
+
+func (s *Solver) rowSymbols() []symbol {
+	symbols := make([]symbol, 0, len(s.rows))
+	for rowSymbol := range s.rows {
+		symbols = append(symbols, rowSymbol)
+	}
+	return sortSymbols(symbols)
+}
+
+func (s *Solver) variableSymbol(v *Variable) symbol {
+	if result, ok := s.variables[v]; ok {
+		return result
+	}
+	result := s.newSymbol(externalSymbol)
+	s.variables[v] = result
+	return result
+}
+
+// createRow converts a Constraint into a row, substituting any basic
+// variables, and adds the error variables for non-required constraints to
+// the objective.
+func (s *Solver) createRow(c *Constraint) (*row, tag) {
+	expression := c.expression
+	r := newRow(expression.Constant)
+	t := tag{}
+
+	for _, term := range expression.Terms {
+		if nearZero(term.Coefficient) {
+			continue
+		}
+		variableSymbol := s.variableSymbol(term.Variable)
+		if basic, ok := s.rows[variableSymbol]; ok {
+			r.insertRow(basic, term.Coefficient)
+		} else {
+			r.insertSymbol(variableSymbol, term.Coefficient)
+		}
+	}
+
+	switch c.operator {
+	case LessThanOrEqual, GreaterThanOrEqual:
+		coefficient := 1.0
+		if c.operator == GreaterThanOrEqual {
+			coefficient = -1.0
+		}
+		slack := s.newSymbol(slackSymbol)
+		t.marker = slack
+		r.insertSymbol(slack, coefficient)
+		if c.strength < Required {
+			errorVariable := s.newSymbol(errorSymbol)
+			t.other = errorVariable
+			r.insertSymbol(errorVariable, -coefficient)
+			s.objective.insertSymbol(errorVariable, float64(c.strength))
+		}
+	case Equal:
+		if c.strength < Required {
+			errorPlus := s.newSymbol(errorSymbol)
+			errorMinus := s.newSymbol(errorSymbol)
+			t.marker = errorPlus
+			t.other = errorMinus
+			r.insertSymbol(errorPlus, -1.0)
+			r.insertSymbol(errorMinus, 1.0)
+			s.objective.insertSymbol(errorPlus, float64(c.strength))
+			s.objective.insertSymbol(errorMinus, float64(c.strength))
+		} else {
+			dummy := s.newSymbol(dummySymbol)
+			t.marker = dummy
+			r.insertSymbol(dummy, 1.0)
+		}
+	}
+
+	if r.constant < 0 {
+		r.reverseSign()
+	}
+	return r, t
+}
+
+// chooseSubject returns the symbol that a new row should be solved for, or
+// an invalid symbol if an artificial variable is needed.
+func (s *Solver) chooseSubject(r *row, t tag) symbol {
+	for _, cell := range r.symbols() {
+		if cell.kind == externalSymbol {
+			return cell
+		}
+	}
+	if t.marker.isPivotable() && r.coefficientFor(t.marker) < 0 {
+		return t.marker
+	}
+	if t.other.isPivotable() && r.coefficientFor(t.other) < 0 {
+		return t.other
+	}
+	return symbol{}
+}
+
+func allDummies(r *row) bool {
+	for cell := range r.cells {
+		if cell.kind != dummySymbol {
+			return false
+		}
+	}
+	return true
+}
+
+// addWithArtificialVariable uses a temporary artificial variable to find an
+// initial feasible solution that includes the provided row. False is
+// returned if the row cannot be satisfied.
+func (s *Solver) addWithArtificialVariable(r *row) bool {
+	artificial := s.newSymbol(slackSymbol)
+	s.rows[artificial] = r.copy()
+	s.artificial = r.copy()
+
+	err := s.optimize(s.artificial)
+	success := err == nil && nearZero(s.artificial.constant)
+	s.artificial = nil
+
+	if basic, ok := s.rows[artificial]; ok {
+		delete(s.rows, artificial)
+		if len(basic.cells) == 0 {
+			return success
+		}
+		entering := anyPivotableSymbol(basic)
+		if !entering.isValid() {
+			return false
+		}
+		basic.solveForPair(artificial, entering)
+		s.substitute(entering, basic)
+		s.rows[entering] = basic
+	}
+
+	for _, other := range s.rows {
+		other.remove(artificial)
+	}
+	s.objective.remove(artificial)
+	return success
+}
+
+func anyPivotableSymbol(r *row) symbol {
+	for _, cell := range r.symbols() {
+		if cell.isPivotable() {
+			return cell
+		}
+	}
+	return symbol{}
+}
+
+func (s *Solver) substitute(subject symbol, r *row) {
+	for _, rowSymbol := range s.rowSymbols() {
+		other := s.rows[rowSymbol]
+		other.substitute(subject, r)
+		if rowSymbol.kind != externalSymbol && other.constant < 0 {
+			s.infeasibleRows = append(s.infeasibleRows, rowSymbol)
+		}
+	}
+	s.objective.substitute(subject, r)
+	if s.artificial != nil {
+		s.artificial.substitute(subject, r)
+	}
+}
+
+// optimize performs simplex iterations until the provided objective is
+// minimized.
+func (s *Solver) optimize(objective *row) error {
+	for {
+		entering := getEnteringSymbol(objective)
+		if !entering.isValid() {
+			return nil
+		}
+		leaving := s.getLeavingSymbol(entering)
+		if !leaving.isValid() {
+			return errUnbounded
+		}
+		r := s.rows[leaving]
+		delete(s.rows, leaving)
+		r.solveForPair(leaving, entering)
+		s.substitute(entering, r)
+		s.rows[entering] = r
+	}
+}
+
+// dualOptimize restores feasibility after edit variables have changed.
+func (s *Solver) dualOptimize() error {
+	for len(s.infeasibleRows) > 0 {
+		leaving := s.infeasibleRows[len(s.infeasibleRows)-1]
+		s.infeasibleRows = s.infeasibleRows[:len(s.infeasibleRows)-1]
+
+		r, ok := s.rows[leaving]
+		if !ok || nearZero(r.constant) || r.constant >= 0 {
+			continue
+		}
+		entering := s.getDualEnteringSymbol(r)
+		if !entering.isValid() {
+			return errDualOptimize
+		}
+		delete(s.rows, leaving)
+		r.solveForPair(leaving, entering)
+		s.substitute(entering, r)
+		s.rows[entering] = r
+	}
+	return nil
+}
+
+func getEnteringSymbol(objective *row) symbol {
+	for _, cell := range objective.symbols() {
+		if cell.kind != dummySymbol && objective.cells[cell] < 0 {
+			return cell
+		}
+	}
+	return symbol{}
+}
+
+func (s *Solver) getDualEnteringSymbol(r *row) symbol {
+	entering := symbol{}
+	ratio := math.MaxFloat64
+	for _, cell := range r.symbols() {
+		coefficient := r.cells[cell]
+		if coefficient > 0 && cell.kind != dummySymbol {
+			cellRatio := s.objective.coefficientFor(cell) / coefficient
+			if cellRatio < ratio {
+				ratio = cellRatio
+				entering = cell
+			}
+		}
+	}
+	return entering
+}
+
+func (s *Solver) getLeavingSymbol(entering symbol) symbol {
+	leaving := symbol{}
+	ratio := math.MaxFloat64
+	for _, rowSymbol := range s.rowSymbols() {
+		if rowSymbol.kind == externalSymbol {
+			continue
+		}
+		r := s.rows[rowSymbol]
+		coefficient := r.coefficientFor(entering)
+		if coefficient < 0 {
+			rowRatio := -r.constant / coefficient
+			if rowRatio < ratio {
+				ratio = rowRatio
+				leaving = rowSymbol
+			}
+		}
+	}
+	return leaving
+}
+
+// getMarkerLeavingSymbol finds the row that should leave the basis so that
+// a constraint marker can be removed.
+func (s *Solver) getMarkerLeavingSymbol(marker symbol) symbol {
+	firstRatio, secondRatio := math.MaxFloat64, math.MaxFloat64
+	first, second, third := symbol{}, symbol{}, symbol{}
+	for _, rowSymbol := range s.rowSymbols() {
+		r := s.rows[rowSymbol]
+		coefficient := r.coefficientFor(marker)
+		if coefficient == 0 {
+			continue
+		}
+		if rowSymbol.kind == externalSymbol {
+			third = rowSymbol
+		} else if coefficient < 0 {
+			ratio := -r.constant / coefficient
+			if ratio < firstRatio {
+				firstRatio = ratio
+				first = rowSymbol
+			}
+		} else {
+			ratio := r.constant / coefficient
+			if ratio < secondRatio {
+				secondRatio = ratio
+				second = rowSymbol
+			}
+		}
+	}
+	if first.isValid() {
+		return first
+	}
+	if second.isValid() {
+		return second
+	}
+	return third
+}
+
+// removeObjectiveEffects removes the error variables of a constraint from
+// the objective.
+func (s *Solver) removeObjectiveEffects(c *Constraint, t tag) {
+	if t.marker.kind == errorSymbol {
+		s.removeMarkerEffects(t.marker, c.strength)
+	}
+	if t.other.kind == errorSymbol {
+		s.removeMarkerEffects(t.other, c.strength)
+	}
+}
+
+func (s *Solver) removeMarkerEffects(marker symbol, strength Strength) {
+	if r, ok := s.rows[marker]; ok {
+		s.objective.insertRow(r, -float64(strength))
+	} else {
+		s.objective.insertSymbol(marker, -float64(strength))
+	}
+}
+
+// NewSolver creates an empty Solver.
+func NewSolver() *Solver {
+	return &Solver{
+		constraints: make(map[*Constraint]tag),
+		edits:       make(map[*Variable]*editInfo),
+		objective:   newRow(0),
+		rows:        make(map[symbol]*row),
+		variables:   make(map[*Variable]symbol),
+	}
+}
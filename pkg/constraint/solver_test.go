@@ -0,0 +1,140 @@
+package constraint_test
+
+import (
+	"testing"
+
+	"github.com/waybeams/assert"
+	"github.com/waybeams/waybeams/pkg/constraint"
+)
+
+func TestSolver(t *testing.T) {
+	var v = constraint.VariableExpression
+	var c = constraint.NewExpression
+
+	t.Run("Instantiable", func(t *testing.T) {
+		s := constraint.NewSolver()
+		assert.NotNil(s)
+	})
+
+	t.Run("Required equality", func(t *testing.T) {
+		left := constraint.NewVariable("left")
+		width := constraint.NewVariable("width")
+		s := constraint.NewSolver()
+
+		assert.Nil(s.AddConstraint(constraint.Relation(v(left), constraint.Equal, c(10), constraint.Required)))
+		assert.Nil(s.AddConstraint(constraint.Relation(v(width), constraint.Equal, c(100), constraint.Required)))
+		right := v(left).Add(v(width))
+		s.UpdateVariables()
+		assert.Equal(left.Value(), 10)
+		assert.Equal(right.Value(), 110)
+	})
+
+	t.Run("Stronger constraints win", func(t *testing.T) {
+		width := constraint.NewVariable("width")
+		s := constraint.NewSolver()
+
+		assert.Nil(s.AddConstraint(constraint.Relation(v(width), constraint.Equal, c(50), constraint.Weak)))
+		assert.Nil(s.AddConstraint(constraint.Relation(v(width), constraint.GreaterThanOrEqual, c(120), constraint.Medium)))
+		s.UpdateVariables()
+		assert.Equal(width.Value(), 120)
+
+		assert.Nil(s.AddConstraint(constraint.Relation(v(width), constraint.LessThanOrEqual, c(80), constraint.Strong)))
+		s.UpdateVariables()
+		assert.Equal(width.Value(), 80)
+	})
+
+	t.Run("Relates variables", func(t *testing.T) {
+		aLeft := constraint.NewVariable("a.left")
+		aWidth := constraint.NewVariable("a.width")
+		bLeft := constraint.NewVariable("b.left")
+		s := constraint.NewSolver()
+
+		assert.Nil(s.AddConstraint(constraint.Relation(v(aLeft), constraint.Equal, c(5), constraint.Required)))
+		assert.Nil(s.AddConstraint(constraint.Relation(v(aWidth), constraint.Equal, c(40), constraint.Required)))
+		// b.left == a.left + a.width + 8
+		assert.Nil(s.AddConstraint(constraint.Relation(v(bLeft), constraint.Equal, v(aLeft).Add(v(aWidth)).Add(c(8)), constraint.Required)))
+		s.UpdateVariables()
+		assert.Equal(bLeft.Value(), 53)
+	})
+
+	t.Run("Unsatisfiable required constraint", func(t *testing.T) {
+		width := constraint.NewVariable("width")
+		s := constraint.NewSolver()
+
+		assert.Nil(s.AddConstraint(constraint.Relation(v(width), constraint.GreaterThanOrEqual, c(100), constraint.Required)))
+		err := s.AddConstraint(constraint.Relation(v(width), constraint.LessThanOrEqual, c(50), constraint.Required))
+		assert.Equal(err, constraint.ErrUnsatisfiableConstraint)
+
+		// The Solver is unchanged by the failure.
+		s.UpdateVariables()
+		assert.Equal(width.Value(), 100)
+		assert.Nil(s.AddConstraint(constraint.Relation(v(width), constraint.LessThanOrEqual, c(150), constraint.Required)))
+	})
+
+	t.Run("Unsatisfiable constant constraint", func(t *testing.T) {
+		s := constraint.NewSolver()
+		err := s.AddConstraint(constraint.Relation(c(1), constraint.Equal, c(2), constraint.Required))
+		assert.Equal(err, constraint.ErrUnsatisfiableConstraint)
+	})
+
+	t.Run("Duplicate and unknown constraints", func(t *testing.T) {
+		width := constraint.NewVariable("width")
+		s := constraint.NewSolver()
+		cn := constraint.Relation(v(width), constraint.Equal, c(10), constraint.Required)
+
+		assert.Nil(s.AddConstraint(cn))
+		assert.True(s.HasConstraint(cn))
+		assert.Equal(s.AddConstraint(cn), constraint.ErrDuplicateConstraint)
+		assert.Nil(s.RemoveConstraint(cn))
+		assert.False(s.HasConstraint(cn))
+		assert.Equal(s.RemoveConstraint(cn), constraint.ErrUnknownConstraint)
+	})
+
+	t.Run("Remove constraint restores weaker values", func(t *testing.T) {
+		width := constraint.NewVariable("width")
+		s := constraint.NewSolver()
+		strong := constraint.Relation(v(width), constraint.Equal, c(200), constraint.Strong)
+
+		assert.Nil(s.AddConstraint(constraint.Relation(v(width), constraint.Equal, c(100), constraint.Weak)))
+		assert.Nil(s.AddConstraint(strong))
+		s.UpdateVariables()
+		assert.Equal(width.Value(), 200)
+
+		assert.Nil(s.RemoveConstraint(strong))
+		s.UpdateVariables()
+		assert.Equal(width.Value(), 100)
+	})
+
+	t.Run("Edit variables", func(t *testing.T) {
+		left := constraint.NewVariable("left")
+		right := constraint.NewVariable("right")
+		s := constraint.NewSolver()
+
+		assert.Nil(s.AddConstraint(constraint.Relation(v(right), constraint.Equal, v(left).Add(c(30)), constraint.Required)))
+		assert.Nil(s.AddConstraint(constraint.Relation(v(right), constraint.LessThanOrEqual, c(100), constraint.Required)))
+		assert.Equal(s.AddEditVariable(left, constraint.Required), constraint.ErrBadRequiredStrength)
+		assert.Nil(s.AddEditVariable(left, constraint.Strong))
+		assert.True(s.HasEditVariable(left))
+		assert.Equal(s.AddEditVariable(left, constraint.Strong), constraint.ErrDuplicateEditVariable)
+
+		assert.Nil(s.SuggestValue(left, 20))
+		s.UpdateVariables()
+		assert.Equal(left.Value(), 20)
+		assert.Equal(right.Value(), 50)
+
+		assert.Nil(s.SuggestValue(left, 90))
+		s.UpdateVariables()
+		assert.Equal(left.Value(), 70)
+		assert.Equal(right.Value(), 100)
+
+		assert.Nil(s.RemoveEditVariable(left))
+		assert.False(s.HasEditVariable(left))
+		assert.Equal(s.SuggestValue(left, 10), constraint.ErrUnknownEditVariable)
+	})
+
+	t.Run("NewStrength", func(t *testing.T) {
+		assert.Equal(constraint.NewStrength(1, 0, 0), constraint.Strong)
+		assert.Equal(constraint.NewStrength(0, 2, 3), 2003)
+		assert.Equal(constraint.NewStrength(2000, 2000, 2000), constraint.Required)
+	})
+}
@@ -36,3 +36,44 @@ func Spacer(options ...spec.Option) *spec.Spec {
 	spec.Apply(spacer, options...)
 	return spacer
 }
+
+// Row lays out children horizontally and wraps them onto new rows when they
+// exceed the available width.
+func Row(options ...spec.Option) *spec.Spec {
+	row := spec.New()
+	row.SetSpecName("Row")
+	row.SetLayoutType(spec.RowLayoutType)
+	spec.Apply(row, options...)
+	return row
+}
+
+// Grid places children in the cells of the columns and rows provided with
+// opts.GridColumns and opts.GridRows.
+func Grid(options ...spec.Option) *spec.Spec {
+	grid := spec.New()
+	grid.SetSpecName("Grid")
+	grid.SetLayoutType(spec.GridLayoutType)
+	spec.Apply(grid, options...)
+	return grid
+}
+
+// Canvas positions and sizes children according to the constraints they
+// declare with opts.Constraints.
+func Canvas(options ...spec.Option) *spec.Spec {
+	canvas := spec.New()
+	canvas.SetSpecName("Canvas")
+	canvas.SetLayoutType(spec.ConstraintLayoutType)
+	spec.Apply(canvas, options...)
+	return canvas
+}
+
+// DockPanel attaches children to its edges with opts.Dock, or pins them to
+// its edges with opts.AnchorLeft, opts.AnchorTop, opts.AnchorRight and
+// opts.AnchorBottom.
+func DockPanel(options ...spec.Option) *spec.Spec {
+	panel := spec.New()
+	panel.SetSpecName("DockPanel")
+	panel.SetLayoutType(spec.DockLayoutType)
+	spec.Apply(panel, options...)
+	return panel
+}
@@ -1,20 +1,120 @@
 package ctrl
 
 import (
+	"math"
+	"strings"
+
 	"github.com/waybeams/waybeams/pkg/spec"
 	"github.com/waybeams/waybeams/pkg/views"
 )
 
 type LabelSpec struct {
 	spec.Spec
+
+	lineHeight float64
+	lines      []string
+	wrapText   bool
+}
+
+// WrapText returns true if the Text will be broken into lines that fit the
+// width of this Label.
+func (l *LabelSpec) WrapText() bool {
+	return l.wrapText
+}
+
+func (l *LabelSpec) SetWrapText(wrap bool) {
+	l.wrapText = wrap
+	l.InvalidateLayoutFor("wrapText", wrap)
+}
+
+// TextLines returns the lines of Text that were found by the last
+// measurement.
+func (l *LabelSpec) TextLines() []string {
+	if len(l.lines) == 0 {
+		return []string{l.Text()}
+	}
+	return l.lines
+}
+
+// LineHeight returns the distance between the baselines of TextLines.
+func (l *LabelSpec) LineHeight() float64 {
+	return l.lineHeight
+}
+
+// RetainFrom keeps the lines of a previous render, which are only measured
+// again when the layout of this Label changes.
+func (l *LabelSpec) RetainFrom(previous spec.ReadWriter) {
+	label, ok := previous.(interface{ labelSpec() *LabelSpec })
+	if !ok {
+		return
+	}
+	p := label.labelSpec()
+	l.lineHeight = p.lineHeight
+	l.lines = p.lines
 }
 
+func (l *LabelSpec) labelSpec() *LabelSpec {
+	return l
+}
+
+// Measure reports the size of the Text on a single line. Labels that wrap
+// their Text report the width of their longest word instead, so that they
+// can be made narrower by their parent.
 func (l *LabelSpec) Measure(s spec.Surface) {
 	x, y, w, h := s.TextBounds(l.FontFace(), l.FontSize(), l.Text())
 	l.SetTextX(x)
 	l.SetTextY(y)
-	l.SetContentWidth(w)
 	l.SetContentHeight(h)
+	l.lineHeight = h
+	l.lines = nil
+
+	if l.wrapText {
+		w = 0
+		for _, word := range strings.Fields(l.Text()) {
+			w = math.Max(w, l.textWidth(s, word))
+		}
+	}
+	l.SetContentWidth(w)
+}
+
+// MeasureHeightForWidth breaks the Text into lines that fit the provided
+// width and returns the height of those lines.
+func (l *LabelSpec) MeasureHeightForWidth(s spec.Surface, width float64) float64 {
+	if !l.wrapText {
+		return l.ContentHeight()
+	}
+
+	l.lines = nil
+	for _, paragraph := range strings.Split(l.Text(), "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if line != "" && l.textWidth(s, candidate) > width {
+				l.lines = append(l.lines, line)
+				candidate = word
+			}
+			line = candidate
+		}
+		l.lines = append(l.lines, line)
+	}
+	return l.lineHeight * float64(len(l.lines))
+}
+
+func (l *LabelSpec) textWidth(s spec.Surface, text string) float64 {
+	_, _, w, _ := s.TextBounds(l.FontFace(), l.FontSize(), text)
+	return w
+}
+
+// WrapText Option that only works with LabelSpec instances (including
+// TextInputSpec). Wrapped text is broken into lines that fit the width of
+// the Label, which should be given a Width or FlexWidth.
+func WrapText(wrap bool) spec.Option {
+	return func(d spec.ReadWriter) {
+		d.(interface{ SetWrapText(bool) }).SetWrapText(wrap)
+	}
 }
 
 func Label(options ...spec.Option) *LabelSpec {
@@ -76,4 +76,55 @@ func TestLabel(t *testing.T) {
 		assert.Equal(args[1], 13)
 		assert.Equal(args[2], "a")
 	})
+	t.Run("Wrapped text is measured for its width", func(t *testing.T) {
+		root := ctrl.VBox(
+			opts.Width(80),
+			opts.Padding(10),
+			opts.Child(ctrl.Label(
+				opts.Key("label"),
+				opts.FontSize(10),
+				opts.FlexWidth(1),
+				opts.Text("one two three four"),
+				ctrl.WrapText(true),
+			)),
+			opts.Child(ctrl.Label(opts.Key("next"), opts.FontSize(10), opts.Text("next"))),
+		)
+
+		s := fake.NewSurface()
+		layout.Layout(root, s)
+
+		label := spec.FirstByKey(root, "label").(*ctrl.LabelSpec)
+		assert.Equal(label.Width(), 60)
+		assert.Equal(label.Height(), 20)
+		assert.Equal(len(label.TextLines()), 2)
+		assert.Equal(label.TextLines()[0], "one two three")
+		assert.Equal(label.TextLines()[1], "four")
+		assert.Equal(spec.FirstByKey(root, "next").Y(), 30)
+		assert.Equal(root.Height(), 50)
+
+		drawSurface := fake.NewSurface()
+		layout.Draw(label, drawSurface)
+		texts := []fake.Command{}
+		for _, cmd := range drawSurface.GetCommands() {
+			if cmd.Name == "Text" {
+				texts = append(texts, cmd)
+			}
+		}
+		assert.Equal(len(texts), 2)
+		assert.Equal(texts[0].Args[1], 20)
+		assert.Equal(texts[1].Args[1], 30)
+		assert.Equal(texts[1].Args[2], "four")
+	})
+
+	t.Run("Wrapped text reports its longest word", func(t *testing.T) {
+		label := ctrl.Label(
+			opts.FontSize(10),
+			opts.Text("one two three\nfour"),
+			ctrl.WrapText(true),
+		)
+		layout.Layout(label, fake.NewSurface())
+		assert.Equal(label.Width(), 21)
+		assert.Equal(label.Height(), 40)
+		assert.Equal(label.TextLines()[3], "four")
+	})
 }
@@ -0,0 +1,342 @@
+package ctrl
+
+import (
+	"math"
+
+	"github.com/waybeams/waybeams/pkg/events"
+	"github.com/waybeams/waybeams/pkg/opts"
+	"github.com/waybeams/waybeams/pkg/spec"
+	"github.com/waybeams/waybeams/pkg/views"
+)
+
+const HorizontalScrollBarKey = "ScrollView.HorizontalScrollBar"
+const VerticalScrollBarKey = "ScrollView.VerticalScrollBar"
+
+// ScrollBarSize is the thickness of the scroll bars of a ScrollView.
+var ScrollBarSize = 6.0
+
+// ScrollStep is the distance that a ScrollView moves for each unit of a
+// Scrolled event.
+var ScrollStep = 40.0
+
+// scrollDragThreshold is the distance that the pointer must move before the
+// content of a ScrollView is dragged, so that presses on its children are
+// not taken for drags.
+const scrollDragThreshold = 4.0
+
+// scrollBarZIndex keeps the scroll bars above the content.
+const scrollBarZIndex = math.MaxInt32
+
+// scrollDrag is a drag of the content or of a scroll bar of a ScrollView.
+type scrollDrag struct {
+	isActive bool
+	isMoving bool
+	pointerX float64
+	pointerY float64
+	scaleX   float64
+	scaleY   float64
+	scrollX  float64
+	scrollY  float64
+}
+
+type ScrollViewSpec struct {
+	spec.Spec
+
+	drag         scrollDrag
+	isHorizontal bool
+	isVertical   bool
+	scrollHeight float64
+	scrollWidth  float64
+}
+
+// IsScrollable returns true if the content can be scrolled on the provided
+// axis. ScrollViews scroll vertically unless configured otherwise.
+func (s *ScrollViewSpec) IsScrollable(axis spec.LayoutAxis) bool {
+	if axis == spec.LayoutHorizontal {
+		return s.isHorizontal
+	}
+	return s.isVertical
+}
+
+func (s *ScrollViewSpec) SetIsScrollable(axis spec.LayoutAxis, value bool) {
+	if axis == spec.LayoutHorizontal {
+		s.isHorizontal = value
+		s.InvalidateLayoutFor("isHorizontal", value)
+	} else {
+		s.isVertical = value
+		s.InvalidateLayoutFor("isVertical", value)
+	}
+}
+
+// ScrollWidth returns the width of the content that was found by the last
+// layout.
+func (s *ScrollViewSpec) ScrollWidth() float64 {
+	return s.scrollWidth
+}
+
+// ScrollHeight returns the height of the content that was found by the last
+// layout.
+func (s *ScrollViewSpec) ScrollHeight() float64 {
+	return s.scrollHeight
+}
+
+// SetScrollSize is called by the Scroll layout with the size of the content
+// on each axis, and moves the scroll bars to match.
+func (s *ScrollViewSpec) SetScrollSize(axis spec.LayoutAxis, size float64) {
+	horizontal := s.scrollBar(HorizontalScrollBarKey)
+	vertical := s.scrollBar(VerticalScrollBarKey)
+	if axis == spec.LayoutHorizontal {
+		s.scrollWidth = size
+		position, length := scrollThumb(s.Width(), s.Width()-s.HorizontalPadding(), size, s.ScrollX())
+		if horizontal != nil {
+			horizontal.SetX(position)
+			horizontal.SetWidth(length)
+		}
+		if vertical != nil {
+			vertical.SetX(s.Width() - ScrollBarSize)
+			vertical.SetWidth(ScrollBarSize)
+		}
+	} else {
+		s.scrollHeight = size
+		position, length := scrollThumb(s.Height(), s.Height()-s.VerticalPadding(), size, s.ScrollY())
+		if vertical != nil {
+			vertical.SetY(position)
+			vertical.SetHeight(length)
+		}
+		if horizontal != nil {
+			horizontal.SetY(s.Height() - ScrollBarSize)
+			horizontal.SetHeight(ScrollBarSize)
+		}
+	}
+	s.showScrollBars()
+}
+
+// MaxScrollX returns the largest ScrollX that still shows content.
+func (s *ScrollViewSpec) MaxScrollX() float64 {
+	return math.Max(0, s.scrollWidth-(s.Width()-s.HorizontalPadding()))
+}
+
+// MaxScrollY returns the largest ScrollY that still shows content.
+func (s *ScrollViewSpec) MaxScrollY() float64 {
+	return math.Max(0, s.scrollHeight-(s.Height()-s.VerticalPadding()))
+}
+
+// ScrollTo moves the content to the provided distance from its start on each
+// scrollable axis, within MaxScrollX and MaxScrollY. It returns true if the
+// content was moved.
+func (s *ScrollViewSpec) ScrollTo(x, y float64) bool {
+	if s.isHorizontal {
+		x = math.Max(0, math.Min(x, s.MaxScrollX()))
+	} else {
+		x = s.ScrollX()
+	}
+	if s.isVertical {
+		y = math.Max(0, math.Min(y, s.MaxScrollY()))
+	} else {
+		y = s.ScrollY()
+	}
+	if x == s.ScrollX() && y == s.ScrollY() {
+		return false
+	}
+	s.SetScrollX(x)
+	s.SetScrollY(y)
+	s.InvalidateLayout()
+	s.Invalidate()
+	return true
+}
+
+// ScrollIntoView scrolls the least distance that shows the provided
+// descendant, or its start if it is larger than the ScrollView. The tree
+// must already be laid out.
+func (s *ScrollViewSpec) ScrollIntoView(r spec.Reader) {
+	originX, originY := spec.LocalToGlobal(s, 0, 0)
+	x, y := spec.LocalToGlobal(r, 0, 0)
+	x = s.ScrollX() + scrollDistance(x-originX, r.Width(), s.PaddingLeft(), s.Width()-s.PaddingRight())
+	y = s.ScrollY() + scrollDistance(y-originY, r.Height(), s.PaddingTop(), s.Height()-s.PaddingBottom())
+	s.ScrollTo(x, y)
+}
+
+// RetainFrom keeps the size of the content and any drag that is in progress.
+func (s *ScrollViewSpec) RetainFrom(previous spec.ReadWriter) {
+	view, ok := previous.(*ScrollViewSpec)
+	if !ok {
+		return
+	}
+	s.drag = view.drag
+	s.scrollHeight = view.scrollHeight
+	s.scrollWidth = view.scrollWidth
+	s.showScrollBars()
+}
+
+// showScrollBars shows the scroll bar of each axis that has more content
+// than fits.
+func (s *ScrollViewSpec) showScrollBars() {
+	if horizontal := s.scrollBar(HorizontalScrollBarKey); horizontal != nil {
+		horizontal.SetVisible(s.isHorizontal && s.MaxScrollX() > 0)
+	}
+	if vertical := s.scrollBar(VerticalScrollBarKey); vertical != nil {
+		vertical.SetVisible(s.isVertical && s.MaxScrollY() > 0)
+	}
+}
+
+func (s *ScrollViewSpec) scrollBar(key string) spec.ReadWriter {
+	for _, child := range s.Children() {
+		if child.Key() == key {
+			return child
+		}
+	}
+	return nil
+}
+
+// startDrag begins a drag of the scroll bar at the provided position, or of
+// the content if there is no scroll bar there.
+func (s *ScrollViewSpec) startDrag(x, y float64) {
+	s.drag = scrollDrag{
+		isActive: true,
+		pointerX: x,
+		pointerY: y,
+		scaleX:   -1,
+		scaleY:   -1,
+		scrollX:  s.ScrollX(),
+		scrollY:  s.ScrollY(),
+	}
+	if bar := s.scrollBar(HorizontalScrollBarKey); bar != nil && bar.Visible() && spec.ContainsCoordinate(bar, x, y) {
+		s.drag.isMoving = true
+		s.drag.scaleX = s.MaxScrollX() / math.Max(1, s.Width()-bar.Width())
+		s.drag.scaleY = 0
+	} else if bar := s.scrollBar(VerticalScrollBarKey); bar != nil && bar.Visible() && spec.ContainsCoordinate(bar, x, y) {
+		s.drag.isMoving = true
+		s.drag.scaleX = 0
+		s.drag.scaleY = s.MaxScrollY() / math.Max(1, s.Height()-bar.Height())
+	}
+}
+
+func (s *ScrollViewSpec) moveDrag(x, y float64) {
+	if !s.drag.isActive {
+		return
+	}
+	deltaX, deltaY := x-s.drag.pointerX, y-s.drag.pointerY
+	if !s.drag.isMoving {
+		if math.Abs(deltaX)+math.Abs(deltaY) < scrollDragThreshold {
+			return
+		}
+		s.drag.isMoving = true
+	}
+	s.ScrollTo(s.drag.scrollX+deltaX*s.drag.scaleX, s.drag.scrollY+deltaY*s.drag.scaleY)
+}
+
+// scrollThumb returns the position and length of a scroll bar thumb within
+// the provided track, in whole pixels.
+func scrollThumb(track, viewport, content, scroll float64) (position, length float64) {
+	if content <= viewport || track <= 0 {
+		return 0, track
+	}
+	length = math.Round(math.Min(track, math.Max(ScrollBarSize*2, track*viewport/content)))
+	return math.Round((track - length) * scroll / (content - viewport)), length
+}
+
+// scrollDistance returns how far to scroll on one axis so that an entry at
+// the provided position and size is shown between first and last.
+func scrollDistance(position, size, first, last float64) float64 {
+	if position < first {
+		return position - first
+	}
+	if position+size > last {
+		return math.Min(position+size-last, position-first)
+	}
+	return 0
+}
+
+// positioned is implemented by the Payload of pointer events.
+type positioned interface {
+	Position() (x, y float64)
+}
+
+// ScrollView is a control that clips its children and scrolls them with the
+// mouse wheel, by dragging the content or its scroll bars, and when one of
+// them is focused.
+func ScrollView(options ...spec.Option) *ScrollViewSpec {
+	view := &ScrollViewSpec{isVertical: true}
+
+	var scrolledHandler = func(e events.Event) {
+		payload, ok := e.Payload().(*events.ScrollPayload)
+		if !ok {
+			return
+		}
+		x := view.ScrollX() - payload.DeltaX*ScrollStep
+		y := view.ScrollY() - payload.DeltaY*ScrollStep
+		if view.ScrollTo(x, y) {
+			// Scrolled events that moved this view are not sent to the
+			// ScrollViews that contain it.
+			e.Cancel()
+		}
+	}
+
+	var pressedHandler = func(e events.Event) {
+		if payload, ok := e.Payload().(positioned); ok {
+			view.startDrag(payload.Position())
+		}
+	}
+
+	var movedHandler = func(e events.Event) {
+		if payload, ok := e.Payload().(positioned); ok {
+			view.moveDrag(payload.Position())
+		}
+	}
+
+	var focusedHandler = func(e events.Event) {
+		target, ok := e.Target().(spec.ReadWriter)
+		if ok && target != spec.ReadWriter(view) {
+			view.ScrollIntoView(target)
+		}
+	}
+
+	// Dragged children are moved instead of the content.
+	view.PushUnsub(view.On(events.DragStarted, func(e events.Event) {
+		view.drag = scrollDrag{}
+	}))
+	view.PushUnsub(view.On(events.Focused, focusedHandler))
+	view.PushUnsub(view.On(events.Moved, movedHandler))
+	view.PushUnsub(view.On(events.Pressed, pressedHandler))
+	view.PushUnsub(view.On(events.Released, func(e events.Event) {
+		view.drag = scrollDrag{}
+	}))
+	view.PushUnsub(view.On(events.Scrolled, scrolledHandler))
+	view.SetClipChildren(true)
+	view.SetHAlign(spec.AlignLeft)
+	view.SetIsFocusable(true)
+	view.SetLayoutType(spec.ScrollLayoutType)
+	view.SetSpecName("ScrollView")
+	// ScrollViews are focusable to receive pointer events, but Tab moves
+	// between their children.
+	view.SetTabIndex(-1)
+	view.SetVAlign(spec.AlignTop)
+
+	spec.Apply(view, options...)
+
+	for _, key := range []string{HorizontalScrollBarKey, VerticalScrollBarKey} {
+		opts.Child(Box(
+			opts.Key(key),
+			opts.BgColor(0x00000066),
+			opts.ExcludeFromLayout(true),
+			opts.View(views.RoundedRectView),
+			opts.Visible(false),
+			opts.ZIndex(scrollBarZIndex),
+		))(view)
+	}
+	return view
+}
+
+// ScrollHorizontal Option that only works with ScrollViewSpec instances.
+func ScrollHorizontal(value bool) spec.Option {
+	return func(d spec.ReadWriter) {
+		d.(*ScrollViewSpec).SetIsScrollable(spec.LayoutHorizontal, value)
+	}
+}
+
+// ScrollVertical Option that only works with ScrollViewSpec instances.
+func ScrollVertical(value bool) spec.Option {
+	return func(d spec.ReadWriter) {
+		d.(*ScrollViewSpec).SetIsScrollable(spec.LayoutVertical, value)
+	}
+}
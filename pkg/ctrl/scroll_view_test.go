@@ -0,0 +1,151 @@
+package ctrl_test
+
+import (
+	"testing"
+
+	"github.com/waybeams/assert"
+	"github.com/waybeams/waybeams/pkg/ctrl"
+	"github.com/waybeams/waybeams/pkg/env/fake"
+	"github.com/waybeams/waybeams/pkg/events"
+	"github.com/waybeams/waybeams/pkg/layout"
+	"github.com/waybeams/waybeams/pkg/opts"
+	"github.com/waybeams/waybeams/pkg/spec"
+)
+
+type pointerPayload struct {
+	x, y float64
+}
+
+func (p *pointerPayload) Position() (x, y float64) {
+	return p.x, p.y
+}
+
+func TestScrollView(t *testing.T) {
+	var renderView = func(options ...spec.Option) spec.ReadWriter {
+		defaults := []spec.Option{
+			opts.Key("view"),
+			opts.Width(100),
+			opts.Height(100),
+			opts.Child(ctrl.VBox(
+				opts.Key("content"),
+				opts.FlexWidth(1),
+				opts.Child(ctrl.Box(opts.Key("first"), opts.Height(150))),
+				opts.Child(ctrl.Box(opts.Key("second"), opts.IsFocusable(true), opts.Height(150))),
+			)),
+		}
+		return ctrl.ScrollView(append(defaults, options...)...)
+	}
+
+	var createView = func(options ...spec.Option) *ctrl.ScrollViewSpec {
+		view := renderView(options...).(*ctrl.ScrollViewSpec)
+		layout.Layout(view, fake.NewSurface())
+		return view
+	}
+
+	t.Run("Measures content on the scroll axis", func(t *testing.T) {
+		view := createView()
+		content := spec.FirstByKey(view, "content")
+		assert.Equal(view.Height(), 100)
+		assert.Equal(content.Width(), 100)
+		assert.Equal(content.Height(), 300)
+		assert.Equal(view.ScrollHeight(), 300)
+		assert.Equal(view.MaxScrollY(), 200)
+		assert.True(view.ClipChildren())
+	})
+
+	t.Run("Shows scroll bars for content that does not fit", func(t *testing.T) {
+		view := createView()
+		horizontal := spec.FirstByKey(view, ctrl.HorizontalScrollBarKey)
+		vertical := spec.FirstByKey(view, ctrl.VerticalScrollBarKey)
+		assert.False(horizontal.Visible())
+		assert.True(vertical.Visible())
+		assert.Equal(vertical.X(), 94)
+		assert.Equal(vertical.Y(), 0)
+		assert.Equal(vertical.Width(), 6)
+		assert.Equal(vertical.Height(), 33)
+	})
+
+	t.Run("Scrolls horizontally", func(t *testing.T) {
+		view := createView(ctrl.ScrollHorizontal(true), ctrl.ScrollVertical(false))
+		assert.True(view.IsScrollable(spec.LayoutHorizontal))
+		assert.False(view.IsScrollable(spec.LayoutVertical))
+		assert.Equal(view.MaxScrollX(), 0)
+		assert.Equal(view.Height(), 300)
+		assert.False(spec.FirstByKey(view, ctrl.VerticalScrollBarKey).Visible())
+	})
+
+	t.Run("Scrolls with the mouse wheel", func(t *testing.T) {
+		view := createView()
+		content := spec.FirstByKey(view, "content")
+		event := events.New(events.Scrolled, content, &events.ScrollPayload{DeltaY: -1})
+		content.Bubble(event)
+		assert.Equal(view.ScrollY(), 40)
+		assert.True(event.IsCancelled())
+		assert.True(view.IsLayoutDirty())
+
+		layout.Layout(view, fake.NewSurface())
+		assert.Equal(content.Y(), -40)
+		assert.Equal(spec.FirstByKey(view, ctrl.VerticalScrollBarKey).Y(), 13)
+	})
+
+	t.Run("Does not scroll beyond the content", func(t *testing.T) {
+		view := createView()
+		event := events.New(events.Scrolled, view, &events.ScrollPayload{DeltaY: 1})
+		view.Bubble(event)
+		assert.Equal(view.ScrollY(), 0)
+		assert.False(event.IsCancelled())
+
+		view.Bubble(events.New(events.Scrolled, view, &events.ScrollPayload{DeltaY: -10}))
+		assert.Equal(view.ScrollY(), 200)
+	})
+
+	t.Run("Drags the content", func(t *testing.T) {
+		view := createView()
+		view.Emit(events.New(events.Pressed, view, &pointerPayload{x: 50, y: 50}))
+		view.Emit(events.New(events.Moved, view, &pointerPayload{x: 50, y: 48}))
+		assert.Equal(view.ScrollY(), 0, "within threshold")
+		view.Emit(events.New(events.Moved, view, &pointerPayload{x: 50, y: 20}))
+		assert.Equal(view.ScrollY(), 30)
+		view.Emit(events.New(events.Released, view, &pointerPayload{x: 50, y: 20}))
+		view.Emit(events.New(events.Moved, view, &pointerPayload{x: 50, y: 0}))
+		assert.Equal(view.ScrollY(), 30)
+	})
+
+	t.Run("Drags the scroll bar", func(t *testing.T) {
+		view := createView()
+		view.Emit(events.New(events.Pressed, view, &pointerPayload{x: 97, y: 10}))
+		view.Emit(events.New(events.Moved, view, &pointerPayload{x: 97, y: 43.5}))
+		assert.Equal(view.ScrollY(), 100)
+	})
+
+	t.Run("Scrolls focused children into view", func(t *testing.T) {
+		view := createView()
+		second := spec.FirstByKey(view, "second")
+		second.Bubble(events.New(events.Focused, second, nil))
+		assert.Equal(view.ScrollY(), 150)
+
+		first := spec.FirstByKey(view, "first")
+		layout.Layout(view, fake.NewSurface())
+		first.Bubble(events.New(events.Focused, first, nil))
+		assert.Equal(view.ScrollY(), 0)
+	})
+
+	t.Run("Retains the scroll across renders", func(t *testing.T) {
+		r := spec.NewReconciler()
+		view := r.Reconcile(createView()).(*ctrl.ScrollViewSpec)
+		view.ScrollTo(0, 120)
+		view = r.Reconcile(renderView()).(*ctrl.ScrollViewSpec)
+		layout.Layout(view, fake.NewSurface())
+		assert.Equal(view.ScrollY(), 120)
+		assert.Equal(spec.FirstByKey(view, "content").Y(), -120)
+		assert.True(spec.FirstByKey(view, ctrl.VerticalScrollBarKey).Visible())
+	})
+
+	t.Run("Shows scroll bars when the layout is retained", func(t *testing.T) {
+		r := spec.NewReconciler()
+		layout.Layout(r.Reconcile(renderView()), fake.NewSurface())
+		view := r.Reconcile(renderView())
+		assert.False(view.IsLayoutDirty())
+		assert.True(spec.FirstByKey(view, ctrl.VerticalScrollBarKey).Visible())
+	})
+}
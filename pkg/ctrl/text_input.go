@@ -13,6 +13,7 @@ const TextKey = "TextInput.Text"
 type TextInputSpec struct {
 	LabelSpec
 
+	caret       int
 	placeholder string
 }
 
@@ -20,20 +21,60 @@ func (t *TextInputSpec) Placeholder() string {
 	return t.placeholder
 }
 
+// Caret returns the rune index where entered characters will be inserted.
+// The caret is at the end of the text unless it has been moved.
+func (t *TextInputSpec) Caret() int {
+	length := len([]rune(t.Text()))
+	if t.caret < 0 || t.caret > length {
+		return length
+	}
+	return t.caret
+}
+
+// SetCaret moves the caret to the provided rune index, or to the end of the
+// text if the index is negative.
+func (t *TextInputSpec) SetCaret(index int) {
+	t.caret = index
+}
+
+// RetainFrom keeps the caret where it was in the previous render.
+func (t *TextInputSpec) RetainFrom(previous spec.ReadWriter) {
+	t.LabelSpec.RetainFrom(previous)
+	if input, ok := previous.(*TextInputSpec); ok {
+		t.caret = input.caret
+	}
+}
+
 // TextInput is a control that allows the user to input text.
 var TextInput = func(options ...spec.Option) spec.ReadWriter {
-	input := &TextInputSpec{}
+	input := &TextInputSpec{caret: -1}
 
 	var charEnteredHandler = func(e events.Event) {
-		ctrl := e.Target().(spec.ReadWriter)
-		updatedText := ctrl.Text() + e.Payload().(string)
-		ctrl.SetText(updatedText)
-		ctrl.Emit(events.New(events.TextChanged, ctrl, updatedText))
+		entered := e.Payload().(string)
+		text := []rune(input.Text())
+		caret := input.Caret()
+		updatedText := string(text[:caret]) + entered + string(text[caret:])
+		input.SetText(updatedText)
+		input.SetCaret(caret + len([]rune(entered)))
+		input.Emit(events.New(events.TextChanged, input, updatedText))
+	}
+
+	// MoveLeft and MoveRight move the caret instead of focus.
+	var moveCaretHandler = func(offset int) events.EventHandler {
+		return func(e events.Event) {
+			e.Cancel()
+			caret := input.Caret() + offset
+			if caret >= 0 && caret <= len([]rune(input.Text())) {
+				input.SetCaret(caret)
+			}
+		}
 	}
 
 	input.PushUnsub(input.On(events.Blurred, opts.OptionsHandler(opts.SetState("active"))))
 	input.PushUnsub(input.On(events.CharEntered, charEnteredHandler))
 	input.PushUnsub(input.On(events.Focused, opts.OptionsHandler(opts.SetState("focused"))))
+	input.PushUnsub(input.On(events.MoveLeft, moveCaretHandler(-1)))
+	input.PushUnsub(input.On(events.MoveRight, moveCaretHandler(1)))
 	input.SetBgColor(0xfefefeff)
 	input.SetHAlign(spec.AlignLeft)
 	input.SetIsFocusable(true)
@@ -66,4 +66,51 @@ func TestTextInput(t *testing.T) {
 		instance.Emit(events.New(events.CharEntered, instance, "T"))
 		assert.Equal(model.Text, "abcdQRST")
 	})
+
+	t.Run("Caret", func(t *testing.T) {
+		t.Run("Inserts at caret", func(t *testing.T) {
+			instance := ctrl.TextInput(opts.Text("abcd")).(*ctrl.TextInputSpec)
+			assert.Equal(instance.Caret(), 4)
+
+			instance.SetCaret(2)
+			instance.Emit(events.New(events.CharEntered, instance, "Q"))
+			assert.Equal(instance.Text(), "abQcd")
+			assert.Equal(instance.Caret(), 3)
+		})
+
+		t.Run("Moves with MoveLeft and MoveRight", func(t *testing.T) {
+			instance := ctrl.TextInput(opts.Text("ab")).(*ctrl.TextInputSpec)
+			left := events.New(events.MoveLeft, instance, nil)
+			instance.Emit(left)
+			assert.True(left.IsCancelled(), "instead of focus")
+			assert.Equal(instance.Caret(), 1)
+			instance.Emit(events.New(events.MoveLeft, instance, nil))
+			instance.Emit(events.New(events.MoveLeft, instance, nil))
+			assert.Equal(instance.Caret(), 0, "stops at the start")
+			instance.Emit(events.New(events.MoveRight, instance, nil))
+			assert.Equal(instance.Caret(), 1)
+			instance.Emit(events.New(events.MoveRight, instance, nil))
+			instance.Emit(events.New(events.MoveRight, instance, nil))
+			assert.Equal(instance.Caret(), 2, "stops at the end")
+		})
+
+		t.Run("Retained across re-renders", func(t *testing.T) {
+			model := &inputModel{Text: "abcd"}
+			var create = func(model *inputModel) spec.ReadWriter {
+				return ctrl.TextInput(
+					opts.Text(model.Text),
+					opts.On(events.TextChanged, events.StringPayload(model.TextChangedHandler)),
+				)
+			}
+
+			reconciler := spec.NewReconciler()
+			instance := reconciler.Reconcile(create(model))
+			instance.(*ctrl.TextInputSpec).SetCaret(1)
+			instance.Emit(events.New(events.CharEntered, instance, "Q"))
+
+			instance = reconciler.Reconcile(create(model))
+			instance.Emit(events.New(events.CharEntered, instance, "R"))
+			assert.Equal(model.Text, "aQRbcd")
+		})
+	})
 }
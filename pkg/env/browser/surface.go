@@ -7,6 +7,7 @@ import (
 
 	jsCanvas "github.com/oskca/gopherjs-canvas"
 	"github.com/waybeams/waybeams/pkg/helpers"
+	"github.com/waybeams/waybeams/pkg/spec"
 )
 
 type ExternalCanvas interface {
@@ -17,6 +18,18 @@ type ExternalCanvas interface {
 const Clockwise = false
 const Anticlockwise = true
 
+// surfaceState is the drawing state that Save pushes. A canvas clip can
+// only be removed by restoring the canvas state from before it was applied,
+// which also restores the styles, so the Surface keeps this state itself and
+// applies it to the canvas again whenever the clip changes.
+type surfaceState struct {
+	fillStyle   string
+	isScissored bool
+	lineWidth   float64
+	scissor     spec.BoundingBox
+	strokeStyle string
+}
+
 type Surface struct {
 	context *jsCanvas.Context2D
 	canvas  ExternalCanvas
@@ -24,6 +37,13 @@ type Surface struct {
 	flags  []SurfaceOption
 	width  float64
 	height float64
+	// state is the current drawing state, and savedStates holds the state
+	// of each Save that has not been restored yet.
+	state       surfaceState
+	savedStates []surfaceState
+	// isClipped is true while the canvas holds the state from before the
+	// clip of the current scissor was applied.
+	isClipped bool
 
 	lastFontSize    int
 	lastFontFace    string
@@ -75,14 +95,17 @@ func (s *Surface) MoveTo(x float64, y float64) {
 }
 
 func (s *Surface) SetFillColor(color uint) {
-	s.context.FillStyle = helpers.UintToHexString(color)
+	s.state.fillStyle = helpers.UintToHexString(color)
+	s.context.FillStyle = s.state.fillStyle
 }
 
 func (s *Surface) SetStrokeColor(color uint) {
-	s.context.StrokeStyle = helpers.UintToHexString(color)
+	s.state.strokeStyle = helpers.UintToHexString(color)
+	s.context.StrokeStyle = s.state.strokeStyle
 }
 
 func (s *Surface) SetStrokeWidth(width float64) {
+	s.state.lineWidth = width
 	s.context.LineWidth = width
 }
 
@@ -90,6 +113,66 @@ func (s *Surface) Stroke() {
 	s.context.Stroke()
 }
 
+// Scissor clips all further drawing to the provided rectangle, replacing
+// any previous scissor, including one from before the last Save.
+func (s *Surface) Scissor(x, y, width, height float64) {
+	s.state.isScissored = true
+	s.state.scissor = spec.BoundingBox{X: x, Y: y, Width: width, Height: height}
+	s.applyState()
+}
+
+// IntersectScissor clips all further drawing to the intersection of the
+// current scissor and the provided rectangle.
+func (s *Surface) IntersectScissor(x, y, width, height float64) {
+	scissor := spec.BoundingBox{X: x, Y: y, Width: width, Height: height}
+	if s.state.isScissored {
+		scissor = s.state.scissor.Intersect(scissor)
+	}
+	s.Scissor(scissor.X, scissor.Y, scissor.Width, scissor.Height)
+}
+
+func (s *Surface) ResetScissor() {
+	if !s.state.isScissored {
+		return
+	}
+	s.state.isScissored = false
+	s.applyState()
+}
+
+func (s *Surface) Save() {
+	s.savedStates = append(s.savedStates, s.state)
+}
+
+func (s *Surface) Restore() {
+	count := len(s.savedStates)
+	if count == 0 {
+		return
+	}
+	s.state = s.savedStates[count-1]
+	s.savedStates = s.savedStates[:count-1]
+	s.applyState()
+}
+
+// applyState clips the canvas to the current scissor, starting from the
+// unclipped canvas state, and then applies the current styles again.
+func (s *Surface) applyState() {
+	if s.isClipped {
+		s.context.Restore()
+		s.isClipped = false
+	}
+	if s.state.isScissored {
+		s.context.Save()
+		s.isClipped = true
+		scissor := s.state.scissor
+		s.context.BeginPath()
+		s.context.Rect(scissor.X, scissor.Y, scissor.Width, scissor.Height)
+		s.context.Clip()
+	}
+	s.context.FillStyle = s.state.fillStyle
+	s.context.LineWidth = s.state.lineWidth
+	s.context.StrokeStyle = s.state.strokeStyle
+}
+
 func (s *Surface) Arc(xc float64, yc float64, radius float64, angle1 float64, angle2 float64) {
 	s.context.Arc(xc, yc, radius, angle1, angle2, Clockwise)
 }
@@ -32,6 +32,19 @@ func (w *window) BeginFrame() {
 	}
 }
 
+// BufferAge returns 1, as the canvas keeps everything that was drawn by the
+// previous frame.
+func (w *window) BufferAge() int {
+	return 1
+}
+
+// BeginPartialFrame begins a frame that only redraws the provided region.
+// The canvas keeps everything that was drawn before, so this is the same as
+// BeginFrame.
+func (w *window) BeginPartialFrame(region spec.BoundingBox) {
+	w.BeginFrame()
+}
+
 func (w *window) EndFrame() {
 }
 
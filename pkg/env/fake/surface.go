@@ -2,13 +2,12 @@ package fake
 
 import (
 	"math"
+
+	"github.com/waybeams/waybeams/pkg/spec"
 )
 
 // Command stores method name and arguments for a given call.
-type Command struct {
-	Name string
-	Args []interface{}
-}
+type Command = spec.Command
 
 // Fake is a drawing surface that is provided to test Draw implementations.
 // Rather than rendering into some hardware interface, the methods provided here
@@ -80,6 +79,34 @@ func (s *Fake) Stroke() {
 	s.commands = append(s.commands, Command{Name: "Stroke"})
 }
 
+// Scissor limits all further drawing to the provided rectangle.
+func (s *Fake) Scissor(x, y, width, height float64) {
+	args := []interface{}{x, y, width, height}
+	s.commands = append(s.commands, Command{Name: "Scissor", Args: args})
+}
+
+// ResetScissor removes the scissor so that drawing is no longer limited.
+func (s *Fake) ResetScissor() {
+	s.commands = append(s.commands, Command{Name: "ResetScissor"})
+}
+
+// IntersectScissor limits all further drawing to the intersection of the
+// current scissor and the provided rectangle.
+func (s *Fake) IntersectScissor(x, y, width, height float64) {
+	args := []interface{}{x, y, width, height}
+	s.commands = append(s.commands, Command{Name: "IntersectScissor", Args: args})
+}
+
+// Save pushes the current drawing state onto a stack.
+func (s *Fake) Save() {
+	s.commands = append(s.commands, Command{Name: "Save"})
+}
+
+// Restore pops the drawing state that was pushed by the matching Save.
+func (s *Fake) Restore() {
+	s.commands = append(s.commands, Command{Name: "Restore"})
+}
+
 // Arc draws a arc along the provided point, radius and angles.
 func (s *Fake) Arc(xc, yc, radius, angle1, angle2 float64) {
 	args := []interface{}{xc, yc, radius, angle1, angle2}
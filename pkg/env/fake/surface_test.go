@@ -37,4 +37,17 @@ func TestFakeSurface(t *testing.T) {
 		assert.Equal(cmds[1].Name, "Height")
 		assert.Equal(len(cmds[1].Args), 0)
 	})
+
+	t.Run("Save, IntersectScissor and Restore", func(t *testing.T) {
+		s := fake.NewSurface()
+		s.Save()
+		s.IntersectScissor(1, 2, 3, 4)
+		s.Restore()
+		cmds := s.GetCommands()
+		assert.Equal(len(cmds), 3)
+		assert.Equal(cmds[0].Name, "Save")
+		assert.Equal(cmds[1].Name, "IntersectScissor")
+		assert.Equal(cmds[1].Args[3], 4.0)
+		assert.Equal(cmds[2].Name, "Restore")
+	})
 }
@@ -1,6 +1,8 @@
 package fake
 
 import (
+	"sync/atomic"
+
 	"github.com/go-gl/glfw/v3.3/glfw"
 	g "github.com/waybeams/waybeams/pkg/env/glfw"
 	"github.com/waybeams/waybeams/pkg/events"
@@ -10,10 +12,13 @@ import (
 const DefaultFrameRate = 12
 
 type FakeWindow struct {
-	width      float64
-	height     float64
-	pixelRatio float64
-	frameRate  int
+	bufferAge    int
+	width        float64
+	height       float64
+	pixelRatio   float64
+	frameRate    int
+	frameRegions []spec.BoundingBox
+	shouldClose  int32
 }
 
 func (f *FakeWindow) Init() {
@@ -41,6 +46,30 @@ func (f *FakeWindow) Height() float64 {
 }
 
 func (f *FakeWindow) BeginFrame() {
+	f.frameRegions = append(f.frameRegions, spec.BoundingBox{Width: f.width, Height: f.height})
+}
+
+// BufferAge returns the age that was provided to SetBufferAge, which is 0
+// by default, so that each frame is drawn entirely.
+func (f *FakeWindow) BufferAge() int {
+	return f.bufferAge
+}
+
+// SetBufferAge configures how many frames ago the content that each frame
+// begins with was drawn.
+func (f *FakeWindow) SetBufferAge(age int) {
+	f.bufferAge = age
+}
+
+// BeginPartialFrame begins a frame that only redraws the provided region.
+func (f *FakeWindow) BeginPartialFrame(region spec.BoundingBox) {
+	f.frameRegions = append(f.frameRegions, region)
+}
+
+// FrameRegions returns the region that was redrawn by each frame that has
+// begun.
+func (f *FakeWindow) FrameRegions() []spec.BoundingBox {
+	return f.frameRegions
 }
 
 func (f *FakeWindow) EndFrame() {
@@ -61,7 +90,17 @@ func (f *FakeWindow) PixelRatio() float64 {
 }
 
 func (f *FakeWindow) ShouldClose() bool {
-	return false
+	return atomic.LoadInt32(&f.shouldClose) != 0
+}
+
+// SetShouldClose configures the value returned by ShouldClose, which ends the
+// frame loop of a Scheduler. It is safe to call while that loop is running.
+func (f *FakeWindow) SetShouldClose(shouldClose bool) {
+	var value int32
+	if shouldClose {
+		value = 1
+	}
+	atomic.StoreInt32(&f.shouldClose, value)
 }
 
 func (f *FakeWindow) UpdateInput(root spec.ReadWriter) {
@@ -73,12 +112,13 @@ func NewWindow() *FakeWindow {
 
 // FakeGestureSource is a minimal struct that is used for testing Gestures.
 type FakeGestureSource struct {
-	xpos          float64
-	ypos          float64
-	CursorName    glfw.StandardCursor
-	CharCallback  spec.CharCallback
-	KeyCallback   g.KeyCallback
-	MouseCallback g.MouseButtonCallback
+	xpos           float64
+	ypos           float64
+	CursorName     glfw.StandardCursor
+	CharCallback   spec.CharCallback
+	KeyCallback    g.KeyCallback
+	MouseCallback  g.MouseButtonCallback
+	ScrollCallback g.ScrollCallback
 }
 
 func (f *FakeGestureSource) SetCursorPos(xpos, ypos float64) {
@@ -118,6 +158,22 @@ func (f *FakeGestureSource) SetMouseButtonCallback(callback g.MouseButtonCallbac
 	}
 }
 
+func (f *FakeGestureSource) SetScrollCallback(callback g.ScrollCallback) events.Unsubscriber {
+	f.ScrollCallback = callback
+	return func() bool {
+		f.ScrollCallback = nil
+		return true
+	}
+}
+
+// Scroll sends the provided offsets to the ScrollCallback, as a mouse wheel
+// or trackpad would.
+func (f *FakeGestureSource) Scroll(xoff, yoff float64) {
+	if f.ScrollCallback != nil {
+		f.ScrollCallback(xoff, yoff)
+	}
+}
+
 func NewFakeGestureSource() *FakeGestureSource {
 	return &FakeGestureSource{}
 }
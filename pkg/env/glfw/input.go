@@ -1,31 +1,79 @@
 package glfw
 
 import (
+	"math"
+	"time"
+
 	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/waybeams/waybeams/pkg/clock"
 	"github.com/waybeams/waybeams/pkg/events"
 	"github.com/waybeams/waybeams/pkg/spec"
 )
 
+// ClickDistance is how far the cursor can move between the clicks of a
+// DoubleClicked or while a LongPressed is pending. Drags begin once the
+// cursor moves further than this from where it was pressed.
+var ClickDistance = 4.0
+
+// DoubleClickInterval is the longest time between two clicks that are
+// counted as a DoubleClicked.
+var DoubleClickInterval = 500 * time.Millisecond
+
+// LongPressDuration is how long the primary button must be held down before
+// LongPressed is sent.
+var LongPressDuration = 600 * time.Millisecond
+
+// MouseEventPayload is the Payload of pointer events. X and Y are the
+// position of the cursor when the event was sent. ClickCount is the number
+// of clicks in a row (e.g., 2 for a double click) for Released, Clicked and
+// DoubleClicked. Key is the key that sent a Clicked from the keyboard, in
+// which case X and Y are the centre of the focused node.
 type MouseEventPayload struct {
-	Button   glfw.MouseButton
-	Action   glfw.Action
-	Modifier glfw.ModifierKey
+	Button     glfw.MouseButton
+	Action     glfw.Action
+	Modifier   glfw.ModifierKey
+	X          float64
+	Y          float64
+	ClickCount int
+	Key        glfw.Key
+}
+
+// Position returns the position of the cursor when the event was sent.
+func (p *MouseEventPayload) Position() (x, y float64) {
+	return p.X, p.Y
 }
 
 type Input struct {
-	lastMoveTarget spec.ReadWriter
-	source         GestureSource
-	lastXpos       float64
-	lastYpos       float64
-	lastRoot       spec.ReadWriter
-	lastFocused    spec.ReadWriter
+	clickCount      int
+	clock           clock.Clock
+	drag            *spec.DragPayload
+	dragSource      spec.ReadWriter
+	lastClickAt     time.Time
+	lastClickPath   string
+	lastClickX      float64
+	lastClickY      float64
+	lastMoveTarget  spec.ReadWriter
+	source          GestureSource
+	lastXpos        float64
+	lastYpos        float64
+	lastRoot        spec.ReadWriter
+	lastFocused     spec.ReadWriter
+	isLongPressed   bool
+	longPressTarget spec.ReadWriter
+	pressedAt       time.Time
+	pressX          float64
+	pressY          float64
 }
 
 // Update should be called on every frame and will collect any pending
 // changes from the configured glfw.Window object and then bubble as events
 // into the appropriate nodes of the tree.
 func (g *Input) Update(root spec.ReadWriter) {
+	if g.lastRoot != root {
+		g.updateRoot(root)
+	}
 	g.lastRoot = root
+	g.updateLongPress()
 
 	xpos, ypos := g.source.GetCursorPos()
 	if g.lastXpos == xpos && g.lastYpos == ypos {
@@ -33,6 +81,10 @@ func (g *Input) Update(root spec.ReadWriter) {
 	}
 	g.lastXpos = xpos
 	g.lastYpos = ypos
+	if g.longPressTarget != nil && !isWithinClickDistance(g.pressX, g.pressY, xpos, ypos) {
+		g.longPressTarget = nil
+	}
+	g.updateDrag(xpos, ypos)
 
 	target := spec.CoordToControl(root, xpos, ypos)
 	lastTarget := g.lastMoveTarget
@@ -56,34 +108,236 @@ func (g *Input) Update(root spec.ReadWriter) {
 	}
 
 	if target != nil {
-		g.bubbleOn(target, events.New(events.Moved, target, nil))
+		// Moved does not render again on its own, handlers that change
+		// something in response to it call Invalidate.
+		payload := &MouseEventPayload{X: xpos, Y: ypos}
+		target.Bubble(events.New(events.Moved, target, payload))
 	}
 	g.lastMoveTarget = target
 }
 
+// updateRoot will replace references to nodes from a previous tree with
+// their counterparts in the newly rendered tree.
+func (g *Input) updateRoot(root spec.ReadWriter) {
+	g.lastMoveTarget = nodeIn(root, g.lastMoveTarget)
+	g.longPressTarget = nodeIn(root, g.longPressTarget)
+	g.dragSource = nodeIn(root, g.dragSource)
+	if g.drag != nil {
+		g.drag.Source = nodeIn(root, g.drag.Source)
+		g.drag.Target = nodeIn(root, g.drag.Target)
+		if g.drag.Source == nil {
+			// The dragged node was removed.
+			g.drag = nil
+		}
+	}
+	focused := root.FocusedSpec()
+	if focused == nil {
+		// Focus stays on the node with the same Path, even when the
+		// reconciler could not match it with the previous tree.
+		focused = nodeIn(root, g.lastFocused)
+		if focused != nil && focused.IsFocusable() && spec.IsShown(focused) {
+			root.SetFocusedSpec(focused)
+		} else {
+			focused = nil
+		}
+	}
+	g.lastFocused = focused
+}
+
+// nodeIn returns the node of the provided tree that has the same Path as the
+// provided node from a previous tree, or nil.
+func nodeIn(root, r spec.ReadWriter) spec.ReadWriter {
+	if r == nil {
+		return nil
+	}
+	return spec.FirstByPath(root, spec.Path(r))
+}
+
 func (g *Input) onMouseButtonHandler(button glfw.MouseButton, action glfw.Action, mod glfw.ModifierKey) {
 	if g.lastRoot == nil {
 		return
 	}
 
-	lastMoveTarget := g.lastMoveTarget
-	if button == glfw.MouseButton1 && lastMoveTarget != nil && lastMoveTarget.IsFocusable() {
-		payload := &MouseEventPayload{
-			Button:   button,
-			Action:   action,
-			Modifier: mod,
-		}
-
+	isDragEnded := false
+	if button == glfw.MouseButton1 {
 		if action == glfw.Press {
-			g.focusSpec(lastMoveTarget)
-			g.bubbleOn(lastMoveTarget, events.New(events.Pressed, lastMoveTarget, payload))
+			g.dragSource = spec.DraggableAt(g.lastRoot, g.lastXpos, g.lastYpos)
+			g.pressX, g.pressY = g.lastXpos, g.lastYpos
 		} else if action == glfw.Release {
-			g.bubbleOn(lastMoveTarget, events.New(events.Released, lastMoveTarget, payload))
-			g.bubbleOn(lastMoveTarget, events.New(events.Clicked, lastMoveTarget, payload))
+			isDragEnded = g.endDrag()
 		}
-	} else {
+	}
+
+	target := g.lastMoveTarget
+	if target == nil || (button == glfw.MouseButton1 && !target.IsFocusable()) {
 		g.focusSpec(nil)
+		return
+	}
+
+	payload := &MouseEventPayload{
+		Button:   button,
+		Action:   action,
+		Modifier: mod,
+		X:        g.lastXpos,
+		Y:        g.lastYpos,
+	}
+	if action == glfw.Press && target.IsFocusable() {
+		g.focusSpec(target)
+	}
+
+	switch button {
+	case glfw.MouseButton1:
+		g.onPrimaryButton(target, payload, isDragEnded)
+	case glfw.MouseButton2:
+		if action == glfw.Release {
+			g.bubbleOn(target, events.New(events.ContextRequested, target, payload))
+		}
+	case glfw.MouseButton3:
+		if action == glfw.Release {
+			g.bubbleOn(target, events.New(events.MiddleClicked, target, payload))
+		}
+	}
+}
+
+// onPrimaryButton sends Pressed, Released and Clicked, and DoubleClicked for
+// the second click in a row. A release that ends a drag, or that follows a
+// LongPressed, is not a click.
+func (g *Input) onPrimaryButton(target spec.ReadWriter, payload *MouseEventPayload, isDragEnded bool) {
+	if payload.Action == glfw.Press {
+		g.isLongPressed = false
+		g.longPressTarget = target
+		g.pressedAt = g.clock.Now()
+		g.bubbleOn(target, events.New(events.Pressed, target, payload))
+		return
+	}
+	if payload.Action != glfw.Release {
+		return
+	}
+
+	isClick := !g.isLongPressed && !isDragEnded
+	g.isLongPressed = false
+	g.longPressTarget = nil
+	if isClick {
+		payload.ClickCount = g.countClick(target)
+	}
+	g.bubbleOn(target, events.New(events.Released, target, payload))
+	if !isClick {
+		return
+	}
+	g.bubbleOn(target, events.New(events.Clicked, target, payload))
+	if payload.ClickCount == 2 {
+		g.bubbleOn(target, events.New(events.DoubleClicked, target, payload))
+	}
+}
+
+// countClick returns the number of clicks in a row on the provided target,
+// including this one.
+func (g *Input) countClick(target spec.ReadWriter) int {
+	now := g.clock.Now()
+	path := spec.Path(target)
+	if g.clickCount > 0 && path == g.lastClickPath &&
+		now.Sub(g.lastClickAt) <= DoubleClickInterval &&
+		isWithinClickDistance(g.lastClickX, g.lastClickY, g.lastXpos, g.lastYpos) {
+		g.clickCount++
+	} else {
+		g.clickCount = 1
+	}
+	g.lastClickAt = now
+	g.lastClickPath = path
+	g.lastClickX, g.lastClickY = g.lastXpos, g.lastYpos
+	return g.clickCount
+}
+
+// updateLongPress sends LongPressed once the primary button has been held
+// down on the same spot for LongPressDuration.
+func (g *Input) updateLongPress() {
+	target := g.longPressTarget
+	if target == nil || g.isLongPressed || g.clock.Since(g.pressedAt) < LongPressDuration {
+		return
+	}
+	g.isLongPressed = true
+	g.longPressTarget = nil
+	payload := &MouseEventPayload{
+		Button: glfw.MouseButton1,
+		Action: glfw.Press,
+		X:      g.pressX,
+		Y:      g.pressY,
+	}
+	g.bubbleOn(target, events.New(events.LongPressed, target, payload))
+}
+
+// updateDrag begins a drag once the pointer has moved beyond ClickDistance
+// from where it pressed a draggable node, unless a handler of DragStarted
+// cancels it. Drags send DragEntered and DragExited to the drop targets under
+// the pointer, and Dragging to the dragged node.
+func (g *Input) updateDrag(xpos, ypos float64) {
+	if g.drag == nil {
+		source := g.dragSource
+		if source == nil || isWithinClickDistance(g.pressX, g.pressY, xpos, ypos) {
+			return
+		}
+		g.dragSource = nil
+		g.drag = &spec.DragPayload{
+			Source: source,
+			StartX: g.pressX,
+			StartY: g.pressY,
+			X:      xpos,
+			Y:      ypos,
+		}
+		started := events.New(events.DragStarted, source, g.drag)
+		g.bubbleOn(source, started)
+		if started.IsCancelled() {
+			g.drag = nil
+			return
+		}
+		g.longPressTarget = nil
+	}
+
+	drag := g.drag
+	drag.X, drag.Y = xpos, ypos
+	target := spec.DropTargetAt(g.lastRoot, drag.Source, xpos, ypos)
+	if target != drag.Target {
+		if previous := drag.Target; previous != nil {
+			g.bubbleOn(previous, events.New(events.DragExited, previous, drag))
+		}
+		drag.Target = target
+		if target != nil {
+			g.bubbleOn(target, events.New(events.DragEntered, target, drag))
+		}
+	}
+	g.bubbleOn(drag.Source, events.New(events.Dragging, drag.Source, drag))
+}
+
+// endDrag sends Dropped to the drop target under the pointer, if any, and
+// DragEnded to the dragged node. It returns true if a drag was ended.
+func (g *Input) endDrag() bool {
+	drag := g.drag
+	g.drag = nil
+	g.dragSource = nil
+	if drag == nil {
+		return false
+	}
+	if drag.Target != nil {
+		g.bubbleOn(drag.Target, events.New(events.Dropped, drag.Target, drag))
 	}
+	g.bubbleOn(drag.Source, events.New(events.DragEnded, drag.Source, drag))
+	return true
+}
+
+func isWithinClickDistance(x1, y1, x2, y2 float64) bool {
+	return math.Abs(x2-x1) <= ClickDistance && math.Abs(y2-y1) <= ClickDistance
+}
+
+// onScrollHandler sends a Scrolled event to the spec under the cursor, which
+// bubbles until a ScrollView (or any other handler) cancels it.
+func (g *Input) onScrollHandler(xoff, yoff float64) {
+	if g.lastRoot == nil {
+		return
+	}
+	xpos, ypos := g.source.GetCursorPos()
+	target := spec.CoordToControl(g.lastRoot, xpos, ypos)
+	payload := &events.ScrollPayload{DeltaX: xoff, DeltaY: yoff}
+	g.bubbleOn(target, events.New(events.Scrolled, target, payload))
 }
 
 func (g *Input) focusSpec(s spec.ReadWriter) {
@@ -127,6 +381,69 @@ func (g *Input) onKeyHandler(key glfw.Key, scancode int, action glfw.Action, mod
 			g.bubbleOn(focused, events.New(events.EnterKeyReleased, focused, key))
 		}
 	}
+	isPressed := action == glfw.Press || action == glfw.Repeat
+	if move, ok := arrowMoves[key]; ok && isPressed {
+		g.moveFocus(move.name, func() spec.ReadWriter {
+			return spec.NeighborFocusable(g.lastRoot, g.lastFocused, move.direction)
+		})
+	} else if key == glfw.KeyTab && isPressed {
+		backward := mods&glfw.ModShift != 0
+		name := events.MoveNext
+		if backward {
+			name = events.MovePrevious
+		}
+		g.moveFocus(name, func() spec.ReadWriter {
+			return spec.NextFocusable(g.lastRoot, g.lastFocused, backward)
+		})
+	} else if isActivateKey(key) && action == glfw.Release && focused != nil && !focused.IsTextInput() {
+		x, y := spec.LocalToGlobal(focused, focused.Width()/2, focused.Height()/2)
+		payload := &MouseEventPayload{
+			Action:     action,
+			Modifier:   mods,
+			X:          x,
+			Y:          y,
+			ClickCount: 1,
+			Key:        key,
+		}
+		g.bubbleOn(focused, events.New(events.Clicked, focused, payload))
+	}
+}
+
+// arrowMoves are the events that arrow keys send, and the directions that
+// they move focus in.
+var arrowMoves = map[glfw.Key]struct {
+	name      string
+	direction spec.FocusDirection
+}{
+	glfw.KeyDown:  {events.MoveDown, spec.FocusDown},
+	glfw.KeyLeft:  {events.MoveLeft, spec.FocusLeft},
+	glfw.KeyRight: {events.MoveRight, spec.FocusRight},
+	glfw.KeyUp:    {events.MoveUp, spec.FocusUp},
+}
+
+// isActivateKey returns true for the keys that send Clicked to the focused
+// node when they are released. The Payload of these Clicked events is a
+// MouseEventPayload with the Key that was released.
+func isActivateKey(key glfw.Key) bool {
+	return key == glfw.KeyEnter || key == glfw.KeyKPEnter || key == glfw.KeySpace
+}
+
+// moveFocus bubbles the named event (e.g., MoveNext or MoveUp) from the
+// focused node, or the root, and then focuses the node that next returns
+// unless a handler cancelled the event.
+func (g *Input) moveFocus(name string, next func() spec.ReadWriter) {
+	target := g.lastFocused
+	if target == nil {
+		target = g.lastRoot
+	}
+	event := events.New(name, target, nil)
+	g.bubbleOn(target, event)
+	if event.IsCancelled() {
+		return
+	}
+	if node := next(); node != nil && node != g.lastFocused {
+		g.focusSpec(node)
+	}
 }
 
 func (g *Input) bubbleOn(s spec.ReadWriter, event events.Event) {
@@ -136,10 +453,14 @@ func (g *Input) bubbleOn(s spec.ReadWriter, event events.Event) {
 	g.lastRoot.Emit(events.New(events.Invalidated, s, nil))
 }
 
-func NewInput(win GestureSource) *Input {
-	instance := &Input{source: win}
+// NewInput creates an Input that sends the gestures of the provided source
+// as events. Double clicks and long presses are timed with the provided
+// Clock.
+func NewInput(win GestureSource, c clock.Clock) *Input {
+	instance := &Input{clock: c, source: win}
 	win.SetCharCallback(instance.onCharHandler)
 	win.SetKeyCallback(instance.onKeyHandler)
 	win.SetMouseButtonCallback(instance.onMouseButtonHandler)
+	win.SetScrollCallback(instance.onScrollHandler)
 	return instance
 }
@@ -2,9 +2,11 @@ package glfw_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/go-gl/glfw/v3.3/glfw"
 	"github.com/waybeams/assert"
+	"github.com/waybeams/waybeams/pkg/clock"
 	"github.com/waybeams/waybeams/pkg/ctrl"
 	"github.com/waybeams/waybeams/pkg/env/fake"
 	g "github.com/waybeams/waybeams/pkg/env/glfw"
@@ -57,7 +59,7 @@ func TestGlfwInput(t *testing.T) {
 		root.On(events.Entered, handler)
 
 		fakeSource := fake.NewFakeGestureSource()
-		input := g.NewInput(fakeSource)
+		input := g.NewInput(fakeSource, clock.NewFake())
 
 		fakeSource.SetCursorPos(10, 10)
 		input.Update(root)
@@ -97,9 +99,407 @@ func TestGlfwInput(t *testing.T) {
 		root.On(events.Invalidated, handler)
 
 		fakeSource := fake.NewFakeGestureSource()
-		input := g.NewInput(fakeSource)
+		input := g.NewInput(fakeSource, clock.NewFake())
 		fakeSource.SetCursorPos(10, 10)
 		input.Update(root)
 		assert.Equal(received[0].Name(), events.Invalidated)
 	})
+
+	t.Run("Does not render again when the cursor only moved", func(t *testing.T) {
+		root := createTree()
+		invalidated := 0
+		moved := 0
+		root.On(events.Invalidated, func(e events.Event) {
+			invalidated++
+		})
+		root.On(events.Moved, func(e events.Event) {
+			moved++
+		})
+
+		fakeSource := fake.NewFakeGestureSource()
+		input := g.NewInput(fakeSource, clock.NewFake())
+		fakeSource.SetCursorPos(10, 10)
+		input.Update(root)
+		invalidated = 0
+		fakeSource.SetCursorPos(12, 12)
+		input.Update(root)
+		assert.Equal(moved, 2)
+		assert.Equal(invalidated, 0)
+	})
+
+	t.Run("Sends the cursor position with Moved", func(t *testing.T) {
+		root := createTree()
+		var payload *g.MouseEventPayload
+		root.On(events.Moved, func(e events.Event) {
+			payload = e.Payload().(*g.MouseEventPayload)
+		})
+
+		fakeSource := fake.NewFakeGestureSource()
+		input := g.NewInput(fakeSource, clock.NewFake())
+		fakeSource.SetCursorPos(10, 40)
+		input.Update(root)
+		x, y := payload.Position()
+		assert.Equal(x, 10)
+		assert.Equal(y, 40)
+	})
+
+	t.Run("Bubbles Scrolled from the spec under the cursor", func(t *testing.T) {
+		root := createTree()
+		received := []events.Event{}
+		root.On(events.Scrolled, func(e events.Event) {
+			received = append(received, e)
+		})
+
+		fakeSource := fake.NewFakeGestureSource()
+		input := g.NewInput(fakeSource, clock.NewFake())
+		fakeSource.SetCursorPos(10, 40)
+		input.Update(root)
+		fakeSource.Scroll(0.5, -2)
+
+		assert.Equal(len(received), 1)
+		assert.Equal(spec.Path(received[0].Target().(spec.Reader)), spec.Path(root.ChildAt(1)))
+		payload := received[0].Payload().(*events.ScrollPayload)
+		assert.Equal(payload.DeltaX, 0.5)
+		assert.Equal(payload.DeltaY, -2)
+	})
+
+	t.Run("Scrolls a ScrollView", func(t *testing.T) {
+		view := ctrl.ScrollView(
+			opts.Width(100),
+			opts.Height(100),
+			opts.Child(ctrl.Box(opts.Key("content"), opts.Height(300))),
+		)
+		layout.Layout(view, fake.NewSurface())
+
+		fakeSource := fake.NewFakeGestureSource()
+		input := g.NewInput(fakeSource, clock.NewFake())
+		fakeSource.SetCursorPos(10, 10)
+		input.Update(view)
+		fakeSource.Scroll(0, -1)
+		assert.Equal(view.ScrollY(), ctrl.ScrollStep)
+	})
+
+	t.Run("Mouse buttons", func(t *testing.T) {
+		var setup = func() (*spec.Spec, *fake.FakeGestureSource, *g.Input, clock.Fake, *[]string) {
+			root := createTree()
+			received := []string{}
+			for _, name := range []string{events.Clicked, events.ContextRequested, events.DoubleClicked,
+				events.LongPressed, events.MiddleClicked, events.Pressed, events.Released} {
+				root.On(name, func(e events.Event) {
+					received = append(received, e.Name())
+				})
+			}
+			fakeClock := clock.NewFake()
+			fakeSource := fake.NewFakeGestureSource()
+			input := g.NewInput(fakeSource, fakeClock)
+			fakeSource.SetCursorPos(10, 10)
+			input.Update(root)
+			return root, fakeSource, input, fakeClock, &received
+		}
+
+		var click = func(source *fake.FakeGestureSource, button glfw.MouseButton) {
+			source.MouseCallback(button, glfw.Press, 0)
+			source.MouseCallback(button, glfw.Release, 0)
+		}
+
+		t.Run("Counts clicks in a row", func(t *testing.T) {
+			root, source, _, fakeClock, received := setup()
+			var counts []int
+			root.On(events.Clicked, func(e events.Event) {
+				counts = append(counts, e.Payload().(*g.MouseEventPayload).ClickCount)
+			})
+
+			click(source, glfw.MouseButton1)
+			fakeClock.Add(200 * time.Millisecond)
+			click(source, glfw.MouseButton1)
+			fakeClock.Add(200 * time.Millisecond)
+			click(source, glfw.MouseButton1)
+			fakeClock.Add(g.DoubleClickInterval + time.Millisecond)
+			click(source, glfw.MouseButton1)
+
+			assert.Equal(len(counts), 4)
+			assert.Equal(counts[0], 1)
+			assert.Equal(counts[1], 2)
+			assert.Equal(counts[2], 3)
+			assert.Equal(counts[3], 1)
+			doubleClicks := 0
+			for _, name := range *received {
+				if name == events.DoubleClicked {
+					doubleClicks++
+				}
+			}
+			assert.Equal(doubleClicks, 1)
+		})
+
+		t.Run("Long presses instead of clicking", func(t *testing.T) {
+			root, source, input, fakeClock, received := setup()
+			source.MouseCallback(glfw.MouseButton1, glfw.Press, 0)
+			fakeClock.Add(g.LongPressDuration - time.Millisecond)
+			input.Update(root)
+			assert.Equal(len(*received), 1)
+
+			fakeClock.Add(time.Millisecond)
+			input.Update(root)
+			input.Update(root)
+			source.MouseCallback(glfw.MouseButton1, glfw.Release, 0)
+			assert.Equal(len(*received), 3)
+			assert.Equal((*received)[1], events.LongPressed)
+			assert.Equal((*received)[2], events.Released)
+		})
+
+		t.Run("Does not long press after moving", func(t *testing.T) {
+			root, source, input, fakeClock, received := setup()
+			source.MouseCallback(glfw.MouseButton1, glfw.Press, 0)
+			source.SetCursorPos(20, 10)
+			input.Update(root)
+			fakeClock.Add(g.LongPressDuration)
+			input.Update(root)
+			assert.Equal(len(*received), 1)
+			assert.Equal((*received)[0], events.Pressed)
+		})
+
+		t.Run("Requests a context menu and middle clicks", func(t *testing.T) {
+			_, source, _, _, received := setup()
+			click(source, glfw.MouseButton2)
+			click(source, glfw.MouseButton3)
+			assert.Equal(len(*received), 2)
+			assert.Equal((*received)[0], events.ContextRequested)
+			assert.Equal((*received)[1], events.MiddleClicked)
+		})
+	})
+
+	t.Run("Drag and drop", func(t *testing.T) {
+		var setup = func() (spec.ReadWriter, *fake.FakeGestureSource, *g.Input, *[]string) {
+			root := ctrl.VBox(
+				opts.Width(100),
+				opts.Height(100),
+				opts.HAlign(spec.AlignLeft),
+				opts.Gutter(0),
+				opts.Child(ctrl.Button(opts.Key("item"), opts.IsDraggable(true), opts.Width(100), opts.Height(20))),
+				opts.Child(ctrl.Box(opts.Key("trash"), opts.IsDropTarget(true), opts.Width(100), opts.Height(40))),
+			)
+			layout.Layout(root, fake.NewSurface())
+			received := []string{}
+			for _, name := range []string{events.Clicked, events.DragEnded, events.DragEntered, events.DragExited,
+				events.DragStarted, events.Dragging, events.Dropped} {
+				root.On(name, func(e events.Event) {
+					received = append(received, e.Name())
+				})
+			}
+			fakeSource := fake.NewFakeGestureSource()
+			input := g.NewInput(fakeSource, clock.NewFake())
+			fakeSource.SetCursorPos(10, 10)
+			input.Update(root)
+			return root, fakeSource, input, &received
+		}
+
+		t.Run("Drops onto drop targets", func(t *testing.T) {
+			root, source, input, received := setup()
+			root.On(events.DragStarted, func(e events.Event) {
+				e.Payload().(*spec.DragPayload).Data = "todo"
+			})
+			var dropped *spec.DragPayload
+			spec.FirstByKey(root, "trash").On(events.Dropped, func(e events.Event) {
+				dropped = e.Payload().(*spec.DragPayload)
+			})
+
+			source.MouseCallback(glfw.MouseButton1, glfw.Press, 0)
+			source.SetCursorPos(12, 10)
+			input.Update(root)
+			assert.Equal(len(*received), 0, "within ClickDistance")
+
+			source.SetCursorPos(10, 50)
+			input.Update(root)
+			source.SetCursorPos(10, 10)
+			input.Update(root)
+			source.SetCursorPos(10, 50)
+			input.Update(root)
+			source.MouseCallback(glfw.MouseButton1, glfw.Release, 0)
+
+			expected := []string{
+				events.DragStarted, events.DragEntered, events.Dragging,
+				events.DragExited, events.Dragging,
+				events.DragEntered, events.Dragging,
+				events.Dropped, events.DragEnded,
+			}
+			assert.Equal(len(*received), len(expected))
+			for index, name := range expected {
+				assert.Equal((*received)[index], name)
+			}
+			assert.Equal(dropped.Data, "todo")
+			assert.Equal(dropped.Source.Key(), "item")
+			assert.Equal(dropped.Target.Key(), "trash")
+			assert.Equal(dropped.StartY, 10)
+			assert.Equal(dropped.Y, 50)
+		})
+
+		t.Run("Does not click after a drag", func(t *testing.T) {
+			root, source, input, received := setup()
+			source.MouseCallback(glfw.MouseButton1, glfw.Press, 0)
+			source.SetCursorPos(90, 10)
+			input.Update(root)
+			source.MouseCallback(glfw.MouseButton1, glfw.Release, 0)
+			assert.Equal(len(*received), 3)
+			assert.Equal((*received)[2], events.DragEnded)
+		})
+
+		t.Run("Can be cancelled", func(t *testing.T) {
+			root, source, input, received := setup()
+			root.On(events.DragStarted, func(e events.Event) {
+				e.Cancel()
+			})
+			source.MouseCallback(glfw.MouseButton1, glfw.Press, 0)
+			source.SetCursorPos(10, 50)
+			input.Update(root)
+			source.MouseCallback(glfw.MouseButton1, glfw.Release, 0)
+			assert.Equal(len(*received), 1)
+			assert.Equal((*received)[0], events.DragStarted)
+		})
+	})
+
+	t.Run("Keyboard focus", func(t *testing.T) {
+		var createFields = func() spec.ReadWriter {
+			root := ctrl.VBox(
+				opts.Key("Root"),
+				opts.Width(100),
+				opts.Height(100),
+				opts.Child(ctrl.Box(opts.Key("first"), opts.Height(20), opts.IsFocusable(true))),
+				opts.Child(ctrl.Box(opts.Key("skipped"), opts.Height(20), opts.IsFocusable(true), opts.TabIndex(-1))),
+				opts.Child(ctrl.Box(opts.Key("second"), opts.Height(20), opts.IsFocusable(true))),
+				opts.Child(ctrl.Box(opts.Key("hidden"), opts.Height(20), opts.IsFocusable(true), opts.Visible(false))),
+				opts.Child(ctrl.Box(opts.Key("third"), opts.Height(20), opts.IsFocusable(true))),
+			)
+			layout.Layout(root, fake.NewSurface())
+			return root
+		}
+
+		var setup = func() (spec.ReadWriter, *fake.FakeGestureSource, *g.Input) {
+			root := createFields()
+			source := fake.NewFakeGestureSource()
+			input := g.NewInput(source, clock.NewFake())
+			input.Update(root)
+			return root, source, input
+		}
+
+		var focusedKey = func(root spec.ReadWriter) string {
+			if focused := root.FocusedSpec(); focused != nil {
+				return focused.Key()
+			}
+			return ""
+		}
+
+		t.Run("Moves with Tab and Shift+Tab", func(t *testing.T) {
+			root, source, _ := setup()
+			source.KeyCallback(glfw.KeyTab, 0, glfw.Press, 0)
+			assert.Equal(focusedKey(root), "first")
+			source.KeyCallback(glfw.KeyTab, 0, glfw.Release, 0)
+			assert.Equal(focusedKey(root), "first")
+			source.KeyCallback(glfw.KeyTab, 0, glfw.Press, 0)
+			assert.Equal(focusedKey(root), "second")
+			source.KeyCallback(glfw.KeyTab, 0, glfw.Repeat, 0)
+			assert.Equal(focusedKey(root), "third")
+			source.KeyCallback(glfw.KeyTab, 0, glfw.Press, 0)
+			assert.Equal(focusedKey(root), "first", "wraps around")
+			source.KeyCallback(glfw.KeyTab, 0, glfw.Press, glfw.ModShift)
+			assert.Equal(focusedKey(root), "third")
+		})
+
+		t.Run("Sends MoveNext and MovePrevious", func(t *testing.T) {
+			root, source, _ := setup()
+			received := []string{}
+			root.On(events.MoveNext, func(e events.Event) {
+				received = append(received, e.Name())
+				e.Cancel()
+			})
+			root.On(events.MovePrevious, func(e events.Event) {
+				received = append(received, e.Name())
+			})
+			source.KeyCallback(glfw.KeyTab, 0, glfw.Press, 0)
+			assert.Equal(focusedKey(root), "", "cancelled")
+			source.KeyCallback(glfw.KeyTab, 0, glfw.Press, glfw.ModShift)
+			assert.Equal(focusedKey(root), "third")
+			assert.Equal(len(received), 2)
+			assert.Equal(received[0], events.MoveNext)
+			assert.Equal(received[1], events.MovePrevious)
+		})
+
+		t.Run("Keeps focus across renders by Path", func(t *testing.T) {
+			root, source, input := setup()
+			source.KeyCallback(glfw.KeyTab, 0, glfw.Press, 0)
+			source.KeyCallback(glfw.KeyTab, 0, glfw.Press, 0)
+			assert.Equal(focusedKey(root), "second")
+			next := createFields()
+			input.Update(next)
+			assert.Equal(focusedKey(next), "second")
+			source.KeyCallback(glfw.KeyTab, 0, glfw.Press, 0)
+			assert.Equal(focusedKey(next), "third")
+		})
+
+		t.Run("Moves with arrow keys", func(t *testing.T) {
+			root, source, _ := setup()
+			received := []string{}
+			root.On(events.MoveDown, func(e events.Event) {
+				received = append(received, e.Name())
+			})
+			root.On(events.MoveUp, func(e events.Event) {
+				received = append(received, e.Name())
+			})
+			source.KeyCallback(glfw.KeyDown, 0, glfw.Press, 0)
+			assert.Equal(focusedKey(root), "first")
+			source.KeyCallback(glfw.KeyDown, 0, glfw.Repeat, 0)
+			assert.Equal(focusedKey(root), "second")
+			source.KeyCallback(glfw.KeyRight, 0, glfw.Press, 0)
+			assert.Equal(focusedKey(root), "second")
+			source.KeyCallback(glfw.KeyUp, 0, glfw.Press, 0)
+			source.KeyCallback(glfw.KeyUp, 0, glfw.Press, 0)
+			assert.Equal(focusedKey(root), "first", "stops at the edge")
+			assert.Equal(len(received), 4)
+			assert.Equal(received[3], events.MoveUp)
+		})
+
+		t.Run("Moves the caret of a TextInput with Left and Right", func(t *testing.T) {
+			root := ctrl.HBox(
+				opts.Width(100),
+				opts.Height(20),
+				opts.Child(ctrl.TextInput(opts.Key("input"), opts.Text("abc"), opts.Width(20))),
+				opts.Child(ctrl.Box(opts.Key("button"), opts.IsFocusable(true), opts.Width(20))),
+			)
+			layout.Layout(root, fake.NewSurface())
+			source := fake.NewFakeGestureSource()
+			input := g.NewInput(source, clock.NewFake())
+			input.Update(root)
+			source.KeyCallback(glfw.KeyTab, 0, glfw.Press, 0)
+			assert.Equal(focusedKey(root), "input")
+			source.KeyCallback(glfw.KeyLeft, 0, glfw.Press, 0)
+			assert.Equal(focusedKey(root), "input")
+			caret := root.FocusedSpec().(*ctrl.TextInputSpec).Caret()
+			assert.Equal(caret, 2)
+			source.KeyCallback(glfw.KeyRight, 0, glfw.Press, 0)
+			source.KeyCallback(glfw.KeyRight, 0, glfw.Press, 0)
+			assert.Equal(focusedKey(root), "input")
+		})
+
+		t.Run("Clicks the focused spec with Enter and Space", func(t *testing.T) {
+			root, source, _ := setup()
+			clicked := []*g.MouseEventPayload{}
+			root.On(events.Clicked, func(e events.Event) {
+				assert.Equal(e.Target().(spec.Reader).Key(), "first")
+				clicked = append(clicked, e.Payload().(*g.MouseEventPayload))
+			})
+			source.KeyCallback(glfw.KeyEnter, 0, glfw.Release, 0)
+			assert.Equal(len(clicked), 0, "nothing focused")
+			source.KeyCallback(glfw.KeyTab, 0, glfw.Press, 0)
+			source.KeyCallback(glfw.KeyEnter, 0, glfw.Press, 0)
+			assert.Equal(len(clicked), 0, "on release")
+			source.KeyCallback(glfw.KeyEnter, 0, glfw.Release, 0)
+			source.KeyCallback(glfw.KeySpace, 0, glfw.Release, 0)
+			assert.Equal(len(clicked), 2)
+			assert.Equal(clicked[1].Key, glfw.KeySpace)
+			assert.Equal(clicked[1].ClickCount, 1)
+			first := root.ChildAt(0)
+			x, y := clicked[1].Position()
+			assert.Equal(x, first.X()+first.Width()/2)
+			assert.Equal(y, 10, "centre of the focused spec")
+		})
+	})
 }
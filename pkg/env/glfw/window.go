@@ -1,8 +1,11 @@
 package glfw
 
 import (
+	"strings"
+
 	"github.com/go-gl/gl/v2.1/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/waybeams/waybeams/pkg/clock"
 	"github.com/waybeams/waybeams/pkg/events"
 	"github.com/waybeams/waybeams/pkg/spec"
 )
@@ -15,6 +18,7 @@ const ResizedEvent = "GlfwWindowResized"
 
 type KeyCallback func(key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey)
 type MouseButtonCallback func(button glfw.MouseButton, action glfw.Action, mod glfw.ModifierKey)
+type ScrollCallback func(xoff, yoff float64)
 
 type GestureSource interface {
 	GetCursorPos() (xpos, ypos float64)
@@ -22,6 +26,7 @@ type GestureSource interface {
 	SetCharCallback(callback spec.CharCallback) events.Unsubscriber
 	SetKeyCallback(callback KeyCallback) events.Unsubscriber
 	SetMouseButtonCallback(callback MouseButtonCallback) events.Unsubscriber
+	SetScrollCallback(callback ScrollCallback) events.Unsubscriber
 }
 
 type Option func(win *window)
@@ -34,14 +39,20 @@ type WindowHint struct {
 type window struct {
 	events.EmitterBase
 
-	frameRate    int
-	height       float64
-	hints        []WindowHint
-	input        *Input
-	nativeWindow *glfw.Window
-	pixelRatio   float64
-	title        string
-	width        float64
+	clock     clock.Clock
+	frameRate int
+	// framebuffer holds each frame after it was copied to the back buffer,
+	// so that the next frame can begin with it (see BufferAge).
+	framebuffer        uint32
+	height             float64
+	hints              []WindowHint
+	input              *Input
+	isFramebufferDrawn bool
+	nativeWindow       *glfw.Window
+	pixelRatio         float64
+	renderbuffers      [2]uint32
+	title              string
+	width              float64
 }
 
 func (win *window) OnResize(handler events.EventHandler) events.Unsubscriber {
@@ -53,6 +64,9 @@ func (win *window) AddHint(h WindowHint) {
 }
 
 func (win *window) BeginFrame() {
+	if win.framebuffer != 0 {
+		gl.BindFramebuffer(gl.FRAMEBUFFER, win.framebuffer)
+	}
 	// TODO(lbayes): Make receiver for BgColor on Window
 	gl.ClearColor(255, 255, 255, 255)
 	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT | gl.STENCIL_BUFFER_BIT)
@@ -81,6 +95,37 @@ func (win *window) BeginFrame() {
 	*/
 }
 
+// BufferAge returns 1 once a frame was drawn into the framebuffer, which
+// keeps it for the next frame. The back buffer is not guaranteed to keep
+// anything between swaps, so 0 is returned when framebuffer objects are not
+// supported, or when the framebuffer was just resized.
+func (win *window) BufferAge() int {
+	if win.framebuffer == 0 || !win.isFramebufferDrawn {
+		return 0
+	}
+	return 1
+}
+
+// BeginPartialFrame begins a frame that only clears the provided region of
+// the framebuffer, and begins a frame that clears everything when there is
+// no previous frame to begin with (see BufferAge).
+func (win *window) BeginPartialFrame(region spec.BoundingBox) {
+	if win.BufferAge() == 0 {
+		win.BeginFrame()
+		return
+	}
+	ratio := win.PixelRatio()
+	gl.Enable(gl.SCISSOR_TEST)
+	gl.Scissor(
+		int32(region.X*ratio),
+		int32((win.Height()-region.Y-region.Height)*ratio),
+		int32(region.Width*ratio),
+		int32(region.Height*ratio),
+	)
+	win.BeginFrame()
+	gl.Disable(gl.SCISSOR_TEST)
+}
+
 func (win *window) Close() {
 	win.nativeWindow.Destroy()
 	glfw.Terminate()
@@ -95,6 +140,16 @@ func (win *window) ShouldClose() bool {
 
 func (win *window) EndFrame() {
 	gl.Enable(gl.DEPTH_TEST)
+	if win.framebuffer != 0 {
+		// Copy the whole frame, as the back buffer may hold anything.
+		width, height := win.nativeWindow.GetFramebufferSize()
+		gl.Disable(gl.SCISSOR_TEST)
+		gl.BindFramebuffer(gl.READ_FRAMEBUFFER, win.framebuffer)
+		gl.BindFramebuffer(gl.DRAW_FRAMEBUFFER, 0)
+		gl.BlitFramebuffer(0, 0, int32(width), int32(height), 0, 0, int32(width), int32(height), gl.COLOR_BUFFER_BIT, gl.NEAREST)
+		gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+		win.isFramebufferDrawn = true
+	}
 	win.nativeWindow.SwapBuffers()
 }
 
@@ -166,19 +221,68 @@ func (win *window) resizeHandler(width, height int) {
 	win.SetWidth(float64(width))
 	win.SetHeight(float64(height))
 	gl.Viewport(0, 0, int32(width), int32(height))
+	win.resizeFramebuffer()
 	win.Emit(events.New(ResizedEvent, win, nil))
 }
 
+// isFramebufferSupported returns true if the current OpenGL context
+// supports framebuffer objects.
+func isFramebufferSupported() bool {
+	version := gl.GoStr(gl.GetString(gl.VERSION))
+	extensions := gl.GoStr(gl.GetString(gl.EXTENSIONS))
+	return version >= "3" || strings.Contains(extensions, "GL_ARB_framebuffer_object")
+}
+
+// initFramebuffer creates the framebuffer that frames are drawn into, when
+// framebuffer objects are supported. Frames are otherwise drawn directly to
+// the back buffer, and are always drawn entirely.
+func (win *window) initFramebuffer() {
+	if !isFramebufferSupported() {
+		return
+	}
+	gl.GenFramebuffers(1, &win.framebuffer)
+	gl.GenRenderbuffers(int32(len(win.renderbuffers)), &win.renderbuffers[0])
+	win.resizeFramebuffer()
+}
+
+// resizeFramebuffer allocates the framebuffer at the size of the window,
+// which discards the frame that it held.
+func (win *window) resizeFramebuffer() {
+	if win.framebuffer == 0 {
+		return
+	}
+	width, height := win.nativeWindow.GetFramebufferSize()
+	gl.BindRenderbuffer(gl.RENDERBUFFER, win.renderbuffers[0])
+	gl.RenderbufferStorage(gl.RENDERBUFFER, gl.RGBA8, int32(width), int32(height))
+	gl.BindRenderbuffer(gl.RENDERBUFFER, win.renderbuffers[1])
+	gl.RenderbufferStorage(gl.RENDERBUFFER, gl.DEPTH24_STENCIL8, int32(width), int32(height))
+	gl.BindRenderbuffer(gl.RENDERBUFFER, 0)
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, win.framebuffer)
+	gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.RENDERBUFFER, win.renderbuffers[0])
+	gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.DEPTH_STENCIL_ATTACHMENT, gl.RENDERBUFFER, win.renderbuffers[1])
+	status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+
+	win.isFramebufferDrawn = false
+	if status != gl.FRAMEBUFFER_COMPLETE {
+		gl.DeleteFramebuffers(1, &win.framebuffer)
+		gl.DeleteRenderbuffers(int32(len(win.renderbuffers)), &win.renderbuffers[0])
+		win.framebuffer = 0
+	}
+}
+
 func (win *window) initGl() {
 	if err := gl.Init(); err != nil {
 		panic(err)
 	}
 
 	gl.Viewport(0, 0, int32(win.Width()), int32(win.Height()))
+	win.initFramebuffer()
 }
 
 func (win *window) initInput() {
-	win.input = NewInput(win)
+	win.input = NewInput(win, win.clock)
 }
 
 func (win *window) Init() {
@@ -271,6 +375,19 @@ func (win *window) SetMouseButtonCallback(callback MouseButtonCallback) events.U
 	}
 }
 
+func (win *window) SetScrollCallback(callback ScrollCallback) events.Unsubscriber {
+	win.nativeWindow.SetScrollCallback(func(w *glfw.Window, xoff float64, yoff float64) {
+		callback(xoff, yoff)
+	})
+	return func() bool {
+		if win.nativeWindow != nil {
+			win.nativeWindow.SetScrollCallback(nil)
+			return true
+		}
+		return false
+	}
+}
+
 func NewWindow(options ...WindowOption) *window {
 	defaults := []WindowOption{
 		Width(DefaultWidth),
@@ -290,7 +407,7 @@ func NewWindow(options ...WindowOption) *window {
 		// Hint(glfw.OpenGLForwardCompatible, glfw.True),
 	}
 
-	w := &window{}
+	w := &window{clock: clock.New()}
 	// Merge and override defaults with provided options.
 	options = append(defaults, options...)
 	for _, option := range options {
@@ -2,8 +2,17 @@ package glfw
 
 import (
 	g "github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/waybeams/waybeams/pkg/clock"
 )
 
+// Clock configures the Clock that times double clicks and long presses,
+// which should be the Clock that was given to the Scheduler.
+func Clock(c clock.Clock) WindowOption {
+	return func(win *window) {
+		win.clock = c
+	}
+}
+
 type WindowOption func(*window)
 
 func Width(width float64) WindowOption {
@@ -108,6 +108,26 @@ func (s *Surface) Stroke() {
 	s.context.Stroke()
 }
 
+func (s *Surface) Scissor(x, y, width, height float64) {
+	s.context.Scissor(float32(x), float32(y), float32(width), float32(height))
+}
+
+func (s *Surface) ResetScissor() {
+	s.context.ResetScissor()
+}
+
+func (s *Surface) IntersectScissor(x, y, width, height float64) {
+	s.context.IntersectScissor(float32(x), float32(y), float32(width), float32(height))
+}
+
+func (s *Surface) Save() {
+	s.context.Save()
+}
+
+func (s *Surface) Restore() {
+	s.context.Restore()
+}
+
 func (s *Surface) Arc(xc float64, yc float64, radius float64, angle1 float64, angle2 float64) {
 	// TODO(lbayes): Update external Surface to include direction and facilitate for Cairo
 	s.context.Arc(float32(xc), float32(yc), float32(radius), float32(angle1), float32(angle2), nanovgo.Clockwise)
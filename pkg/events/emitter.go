@@ -104,12 +104,21 @@ type registeredHandler struct {
 	id        int64
 }
 
+// Subscription describes a handler that has been registered for an event name.
+// ID is unique to each registration, even of the same handler.
+type Subscription struct {
+	EventName string
+	Handler   EventHandler
+	ID        int64
+}
+
 type Emitter interface {
 	On(eventName string, handler EventHandler) Unsubscriber
 	Bubble(event Event)
 	Emit(event Event)
 	RemoveAllHandlers() bool
 	RemoveAllHandlersFor(eventName string) bool
+	Subscriptions() []Subscription
 }
 
 type EmitterBase struct {
@@ -135,6 +144,15 @@ func (e *EmitterBase) RemoveAllHandlers() bool {
 	return found
 }
 
+// Subscriptions returns every registered handler in the order it was added.
+func (e *EmitterBase) Subscriptions() []Subscription {
+	result := []Subscription{}
+	for _, entry := range e.handlers {
+		result = append(result, Subscription{EventName: entry.eventName, Handler: entry.handler, ID: entry.id})
+	}
+	return result
+}
+
 func (e *EmitterBase) Bubble(event Event) {
 	// NOTE(lbayes): Spec overrides this method and implements support
 	// that requires access to the Composable interface.
@@ -25,6 +25,18 @@ func TestDispatcher(t *testing.T) {
 		assert.Equal(calledWith.Target(), instance, "received Target")
 	})
 
+	t.Run("Subscriptions", func(t *testing.T) {
+		instance := events.NewEmitter()
+		assert.Equal(len(instance.Subscriptions()), 0)
+
+		instance.On("fake-one", func(e events.Event) {})
+		instance.On("fake-two", func(e events.Event) {})
+		subscriptions := instance.Subscriptions()
+		assert.Equal(len(subscriptions), 2)
+		assert.Equal(subscriptions[0].EventName, "fake-one")
+		assert.Equal(subscriptions[1].EventName, "fake-two")
+	})
+
 	t.Run("RemoveHandler", func(t *testing.T) {
 		var calledWith events.Event
 		handler := func(e events.Event) {
@@ -9,17 +9,26 @@ const KeyReleased = "KeyReleased"
 const Moved = "Moved"
 const Pressed = "Pressed"
 const Released = "Released"
+const Scrolled = "Scrolled"
 
 // Spec Notifications (past tense)
 const Blurred = "Blurred"
 const Clicked = "Clicked"
+const ContextRequested = "ContextRequested"
+const DoubleClicked = "DoubleClicked"
 const DragEnded = "DragEnded"
+const DragEntered = "DragEntered"
+const DragExited = "DragExited"
 const DragStarted = "DragStarted"
+const Dragging = "Dragging"
+const Dropped = "Dropped"
 const Entered = "Entered"
 const Exited = "Exited"
 const Focused = "Focused"
 const FrameEntered = "FrameEntered"
 const Hovered = "Hovered"
+const LongPressed = "LongPressed"
+const MiddleClicked = "MiddleClicked"
 const Submitted = "Submitted"
 const TextChanged = "TextChanged"
 
@@ -34,11 +43,14 @@ const MoveRight = "MoveRight"
 const MoveUp = "MoveUp"
 
 // Spec Lifecycle
+const Added = "Added"
 const Configured = "Configured"
 const Created = "Created"
 const DrawCompleted = "DrawCompleted"
 const Invalidated = "Invalidated"
 const LayoutCompleted = "LayoutCompleted"
+const LayoutFailed = "LayoutFailed"
+const Removed = "Removed"
 
 var AllEvents = []string{
 	// Gesture Notifications
@@ -47,6 +59,7 @@ var AllEvents = []string{
 	Moved,
 	Pressed,
 	Released,
+	Scrolled,
 	KeyEntered,
 	KeyPressed,
 	KeyReleased,
@@ -54,13 +67,21 @@ var AllEvents = []string{
 	// Spec Notifications
 	Blurred,
 	Clicked,
+	ContextRequested,
+	DoubleClicked,
 	DragEnded,
+	DragEntered,
+	DragExited,
 	DragStarted,
+	Dragging,
+	Dropped,
 	Entered,
 	Exited,
 	Focused,
 	FrameEntered,
 	Hovered,
+	LongPressed,
+	MiddleClicked,
 	Submitted,
 	TextChanged,
 
@@ -75,9 +96,20 @@ var AllEvents = []string{
 	MoveUp,
 
 	// Spec Lifecycle
+	Added,
 	Configured,
 	Created,
 	DrawCompleted,
 	Invalidated,
 	LayoutCompleted,
+	LayoutFailed,
+	Removed,
+}
+
+// ScrollPayload is the Payload of Scrolled events, with the distance that a
+// mouse wheel or trackpad moved on each axis. Positive values scroll toward
+// the start of the content, which is up or to the left.
+type ScrollPayload struct {
+	DeltaX float64
+	DeltaY float64
 }
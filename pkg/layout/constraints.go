@@ -0,0 +1,301 @@
+package layout
+
+import (
+	"errors"
+	"math"
+	"strings"
+
+	"github.com/waybeams/waybeams/pkg/constraint"
+	"github.com/waybeams/waybeams/pkg/events"
+	"github.com/waybeams/waybeams/pkg/spec"
+)
+
+// Strengths of the implicit constraints that keep children at the start of
+// the parent and at their measured size. These are weaker than
+// constraint.Weak, so that any declared constraint will win. Children that
+// declare constraints hold their position more weakly than the children
+// they refer to, so that they are the ones that move.
+const (
+	constraintSizeStrength     = constraint.Weak / 2
+	constraintPositionStrength = constraint.Weak / 10
+	constraintDeclaredStrength = constraint.Weak / 100
+	constraintParentStrength   = constraint.Weak / 1000
+)
+
+// constraintAttribute describes a named attribute as a combination of the
+// position and size of a Spec on one axis.
+type constraintAttribute struct {
+	axis     spec.LayoutAxis
+	position float64
+	size     float64
+}
+
+var constraintAttributes = map[string]constraintAttribute{
+	"left":    {axis: spec.LayoutHorizontal, position: 1},
+	"right":   {axis: spec.LayoutHorizontal, position: 1, size: 1},
+	"width":   {axis: spec.LayoutHorizontal, size: 1},
+	"centerX": {axis: spec.LayoutHorizontal, position: 1, size: 0.5},
+	"top":     {axis: spec.LayoutVertical, position: 1},
+	"bottom":  {axis: spec.LayoutVertical, position: 1, size: 1},
+	"height":  {axis: spec.LayoutVertical, size: 1},
+	"centerY": {axis: spec.LayoutVertical, position: 1, size: 0.5},
+}
+
+// ConstraintError describes a constraint that could not be applied by the
+// ConstraintLayoutType. It is the payload of the events.LayoutFailed event
+// that is bubbled from the Spec that declared the constraint, and is
+// returned by LayoutWithErrors.
+type ConstraintError struct {
+	Path       string
+	Constraint string
+	Err        error
+}
+
+func (e *ConstraintError) Error() string {
+	return e.Path + ": " + e.Constraint + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error, which is
+// constraint.ErrUnsatisfiableConstraint for constraints that conflict with
+// required constraints.
+func (e *ConstraintError) Unwrap() error {
+	return e.Err
+}
+
+// constraintVariables are the solver variables of a child on one axis.
+type constraintVariables struct {
+	position *constraint.Variable
+	size     *constraint.Variable
+}
+
+// constraintSystem relates the children of a Spec on the axis of the
+// provided delegate.
+type constraintSystem struct {
+	children   []spec.ReadWriter
+	d          spec.ReadWriter
+	delegate   Delegate
+	parentSize constraint.Expression
+	solver     *constraint.Solver
+	variables  map[spec.ReadWriter]*constraintVariables
+}
+
+// ConstraintOnAxis performs a Constraint layout on the provided delegate axis.
+//
+// Children declare linear constraints with opts.Constraints, like
+// "left == sibling.right + 8" or "width >= 120 @ medium". Names refer to an
+// attribute (left, right, width, centerX, top, bottom, height or centerY) of
+// the child itself, or of a target in front of a dot: "parent", "sibling"
+// (the previous layoutable child, also "previous"), "next", or the Key of
+// another child. The attributes of the parent describe the area inside of
+// its padding.
+//
+// Children without constraints stay at the start of the parent and keep
+// their measured size, while flexible children fill the parent. Constraints
+// that cannot be parsed, or that conflict with required constraints, are
+// skipped and reported by bubbling events.LayoutFailed from the child with a
+// *ConstraintError payload.
+func ConstraintOnAxis(delegate Delegate, d spec.ReadWriter) (childrenSize float64) {
+	if d.ChildCount() == 0 {
+		return delegate.Size(d)
+	}
+
+	// Lay out every child first, so that nested containers report their
+	// content size.
+	for _, child := range d.Children() {
+		delegate.LayoutSpec(child)
+	}
+
+	system := newConstraintSystem(delegate, d)
+	for _, child := range system.children {
+		for _, source := range child.Constraints() {
+			system.add(child, source)
+		}
+	}
+	system.solver.UpdateVariables()
+
+	maxEnd := 0.0
+	for _, child := range system.children {
+		variables := system.variables[child]
+		size := variables.size.Value()
+		if size != delegate.Size(child) {
+			delegate.SetSize(child, size)
+			delegate.LayoutSpec(child)
+		}
+		delegate.SetPosition(child, variables.position.Value())
+		maxEnd = math.Max(maxEnd, delegate.Position(child)+delegate.Size(child))
+	}
+
+	childrenSize = math.Max(0, maxEnd-delegate.PaddingFirst(d))
+	if !system.parentSize.IsConstant() {
+		childrenSize = math.Max(childrenSize, system.parentSize.Value())
+	}
+	delegate.SetChildrenSize(d, childrenSize)
+	return childrenSize
+}
+
+func newConstraintSystem(delegate Delegate, d spec.ReadWriter) *constraintSystem {
+	system := &constraintSystem{
+		children:  getLayoutableChildren(d),
+		d:         d,
+		delegate:  delegate,
+		solver:    constraint.NewSolver(),
+		variables: make(map[spec.ReadWriter]*constraintVariables),
+	}
+
+	var add = func(lhs constraint.Expression, op constraint.Operator, rhs float64, strength constraint.Strength) {
+		// These constraints are never in conflict with one another.
+		system.solver.AddConstraint(constraint.Relation(lhs, op, constraint.NewExpression(rhs), strength))
+	}
+
+	// Parents without a size on this axis are sized by their children.
+	start := delegate.PaddingFirst(d)
+	system.parentSize = constraint.NewExpression(math.Max(0, delegate.Size(d)-delegate.Padding(d)))
+	if delegate.Size(d) == 0 {
+		system.parentSize = constraint.VariableExpression(constraint.NewVariable(spec.Path(d) + ".size"))
+		add(system.parentSize, constraint.Equal, 0, constraintParentStrength)
+	}
+
+	for _, child := range system.children {
+		path := spec.Path(child)
+		variables := &constraintVariables{
+			position: constraint.NewVariable(path + ".position"),
+			size:     constraint.NewVariable(path + ".size"),
+		}
+		system.variables[child] = variables
+		position := constraint.VariableExpression(variables.position)
+		size := constraint.VariableExpression(variables.size)
+
+		if len(child.Constraints()) > 0 {
+			add(position, constraint.Equal, start, constraintDeclaredStrength)
+		} else {
+			add(position, constraint.Equal, start, constraintPositionStrength)
+		}
+		if delegate.IsFlexible(child) {
+			system.solver.AddConstraint(constraint.Relation(size, constraint.Equal, system.parentSize, constraintSizeStrength))
+		} else {
+			add(size, constraint.Equal, delegate.Size(child), constraintSizeStrength)
+		}
+		add(size, constraint.GreaterThanOrEqual, math.Max(0, delegate.MinSize(child)), constraint.Required)
+		if delegate.MaxSize(child) > 0 {
+			add(size, constraint.LessThanOrEqual, delegate.MaxSize(child), constraint.Required)
+		}
+		if !system.parentSize.IsConstant() {
+			end := position.Add(size).Sub(system.parentSize)
+			add(end, constraint.LessThanOrEqual, start, constraint.Required)
+		}
+	}
+	return system
+}
+
+// add parses the provided constraint of a child and adds it to the solver
+// when it refers to the current axis.
+func (s *constraintSystem) add(child spec.ReadWriter, source string) {
+	onAxis := false
+	c, err := constraint.Parse(source, func(name string) (constraint.Expression, error) {
+		expression, isOnAxis, err := s.resolve(child, name)
+		onAxis = onAxis || isOnAxis
+		return expression, err
+	})
+
+	if err != nil {
+		// Parse errors do not depend on the axis, only report them once.
+		if s.delegate.Axis() == spec.LayoutHorizontal {
+			s.fail(child, source, err)
+		}
+		return
+	}
+	if !onAxis {
+		return
+	}
+	if err := s.solver.AddConstraint(c); err != nil {
+		s.fail(child, source, err)
+	}
+}
+
+func (s *constraintSystem) fail(child spec.ReadWriter, source string, err error) {
+	payload := &ConstraintError{Path: spec.Path(child), Constraint: source, Err: err}
+	child.Bubble(events.New(events.LayoutFailed, child, payload))
+}
+
+// resolve returns the Expression for a name like "sibling.right" and whether
+// it refers to the current axis. Attributes on the other axis are constant.
+func (s *constraintSystem) resolve(child spec.ReadWriter, name string) (expression constraint.Expression, onAxis bool, err error) {
+	targetName, attributeName := "", name
+	if index := strings.LastIndex(name, "."); index > -1 {
+		targetName, attributeName = name[:index], name[index+1:]
+	}
+
+	attribute, ok := constraintAttributes[attributeName]
+	if !ok {
+		return expression, false, errors.New("unknown attribute \"" + attributeName + "\"")
+	}
+
+	target, err := s.target(child, targetName)
+	if err != nil {
+		return expression, false, err
+	}
+
+	onAxis = attribute.axis == s.delegate.Axis()
+	delegate := s.delegate
+	if !onAxis {
+		delegate = hDelegate
+		if attribute.axis == spec.LayoutVertical {
+			delegate = vDelegate
+		}
+	}
+
+	var position, size constraint.Expression
+	switch {
+	case target == s.d && onAxis:
+		position = constraint.NewExpression(delegate.PaddingFirst(target))
+		size = s.parentSize
+	case target == s.d:
+		position = constraint.NewExpression(delegate.PaddingFirst(target))
+		size = constraint.NewExpression(math.Max(0, delegate.Size(target)-delegate.Padding(target)))
+	case onAxis:
+		position = constraint.VariableExpression(s.variables[target].position)
+		size = constraint.VariableExpression(s.variables[target].size)
+	default:
+		position = constraint.NewExpression(delegate.Position(target))
+		size = constraint.NewExpression(delegate.Size(target))
+	}
+	return position.Scale(attribute.position).Add(size.Scale(attribute.size)), onAxis, nil
+}
+
+// target returns the Spec that is referred to by the provided name, relative
+// to the provided child.
+func (s *constraintSystem) target(child spec.ReadWriter, name string) (spec.ReadWriter, error) {
+	switch name {
+	case "", "self":
+		return child, nil
+	case "parent":
+		return s.d, nil
+	case "sibling", "previous", "next":
+		index := s.indexOf(child)
+		if name == "next" {
+			index++
+		} else {
+			index--
+		}
+		if index < 0 || index >= len(s.children) {
+			return nil, errors.New("there is no " + name + " layoutable child")
+		}
+		return s.children[index], nil
+	}
+
+	for _, other := range s.children {
+		if other.Key() == name {
+			return other, nil
+		}
+	}
+	return nil, errors.New("unknown key \"" + name + "\"")
+}
+
+func (s *constraintSystem) indexOf(child spec.ReadWriter) int {
+	for index, other := range s.children {
+		if other == child {
+			return index
+		}
+	}
+	return -1
+}
@@ -0,0 +1,180 @@
+package layout_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/waybeams/assert"
+	"github.com/waybeams/waybeams/pkg/constraint"
+	"github.com/waybeams/waybeams/pkg/ctrl"
+	surface "github.com/waybeams/waybeams/pkg/env/fake"
+	"github.com/waybeams/waybeams/pkg/events"
+	"github.com/waybeams/waybeams/pkg/fakes"
+	"github.com/waybeams/waybeams/pkg/layout"
+	"github.com/waybeams/waybeams/pkg/opts"
+	"github.com/waybeams/waybeams/pkg/spec"
+)
+
+func TestConstraintLayout(t *testing.T) {
+	var createCanvas = func(options ...spec.Option) spec.ReadWriter {
+		defaults := []spec.Option{
+			opts.Key("canvas"),
+			opts.Width(300),
+			opts.Height(100),
+			opts.Padding(10),
+		}
+		return ctrl.Canvas(append(defaults, options...)...)
+	}
+
+	var collectErrors = func(result *[]*layout.ConstraintError) spec.Option {
+		return opts.On(events.LayoutFailed, func(e events.Event) {
+			*result = append(*result, e.Payload().(*layout.ConstraintError))
+		})
+	}
+
+	t.Run("Children without constraints", func(t *testing.T) {
+		root := layout.Layout(createCanvas(
+			opts.Child(fakes.Fake(opts.Key("a"), opts.Width(50), opts.Height(20))),
+			opts.Child(fakes.Fake(opts.Key("b"), opts.FlexWidth(1), opts.Height(20))),
+		), surface.NewSurface())
+
+		a := spec.FirstByKey(root, "a")
+		assert.Equal(a.X(), 10)
+		assert.Equal(a.Y(), 10)
+		assert.Equal(a.Width(), 50)
+
+		// Flexible children fill the parent.
+		assert.Equal(spec.FirstByKey(root, "b").Width(), 280)
+	})
+
+	t.Run("Relative to sibling", func(t *testing.T) {
+		root := layout.Layout(createCanvas(
+			opts.Child(fakes.Fake(opts.Key("a"), opts.Width(50), opts.Height(20))),
+			opts.Child(fakes.Fake(
+				opts.Key("b"),
+				opts.Width(30),
+				opts.Height(20),
+				opts.Constraints("left == sibling.right + 8", "top == sibling.bottom"),
+			)),
+		), surface.NewSurface())
+
+		b := spec.FirstByKey(root, "b")
+		assert.Equal(b.X(), 68)
+		assert.Equal(b.Y(), 30)
+	})
+
+	t.Run("Relative to key and next", func(t *testing.T) {
+		root := layout.Layout(createCanvas(
+			opts.Child(fakes.Fake(opts.Key("a"), opts.Width(50), opts.Constraints("right == next.left"))),
+			opts.Child(fakes.Fake(opts.Key("b"), opts.Width(30), opts.Constraints("left == c.right"))),
+			opts.Child(fakes.Fake(opts.Key("c"), opts.Width(20), opts.Constraints("left == 100"))),
+		), surface.NewSurface())
+
+		assert.Equal(spec.FirstByKey(root, "b").X(), 120)
+		assert.Equal(spec.FirstByKey(root, "a").X(), 70)
+	})
+
+	t.Run("Relative to parent", func(t *testing.T) {
+		root := layout.Layout(createCanvas(
+			opts.Child(fakes.Fake(
+				opts.Key("a"),
+				opts.Width(50),
+				opts.Height(20),
+				opts.Constraints("right == parent.right", "centerY == parent.centerY"),
+			)),
+			opts.Child(fakes.Fake(
+				opts.Key("b"),
+				opts.Height(20),
+				opts.Constraints("width == parent.width / 2", "bottom == parent.bottom"),
+			)),
+		), surface.NewSurface())
+
+		a := spec.FirstByKey(root, "a")
+		assert.Equal(a.X(), 240)
+		assert.Equal(a.Y(), 40)
+
+		b := spec.FirstByKey(root, "b")
+		assert.Equal(b.Width(), 140)
+		assert.Equal(b.Y(), 70)
+	})
+
+	t.Run("Strengths", func(t *testing.T) {
+		root := layout.Layout(createCanvas(
+			opts.Child(fakes.Fake(
+				opts.Key("a"),
+				opts.Width(50),
+				opts.Constraints("width >= 120 @ medium"),
+			)),
+			opts.Child(fakes.Fake(
+				opts.Key("b"),
+				opts.Width(50),
+				opts.Constraints("width >= 120 @ medium", "width <= 100 @ strong"),
+			)),
+		), surface.NewSurface())
+
+		assert.Equal(spec.FirstByKey(root, "a").Width(), 120)
+		assert.Equal(spec.FirstByKey(root, "b").Width(), 100)
+	})
+
+	t.Run("Sized by children", func(t *testing.T) {
+		root := layout.Layout(ctrl.Canvas(
+			opts.Padding(5),
+			opts.Child(fakes.Fake(opts.Key("a"), opts.Width(50), opts.Height(20))),
+			opts.Child(fakes.Fake(opts.Key("b"), opts.Width(30), opts.Height(10), opts.Constraints("left == a.right + 10"))),
+			opts.Child(fakes.Fake(opts.Key("c"), opts.Width(10), opts.Height(10), opts.Constraints("right == parent.right"))),
+		), surface.NewSurface())
+
+		assert.Equal(root.ChildrenWidth(), 90)
+		assert.Equal(root.Width(), 100)
+		assert.Equal(root.Height(), 30)
+		assert.Equal(spec.FirstByKey(root, "c").X(), 85)
+	})
+
+	t.Run("Reports unsatisfiable constraints", func(t *testing.T) {
+		failures := []*layout.ConstraintError{}
+		root := layout.Layout(createCanvas(
+			collectErrors(&failures),
+			opts.Child(fakes.Fake(
+				opts.Key("a"),
+				opts.Constraints("width >= 200", "width <= 100", "height == 30"),
+			)),
+		), surface.NewSurface())
+
+		assert.Equal(len(failures), 1)
+		assert.Equal(failures[0].Path, "/canvas/a")
+		assert.Equal(failures[0].Constraint, "width <= 100")
+		assert.True(errors.Is(failures[0], constraint.ErrUnsatisfiableConstraint))
+		assert.Equal(failures[0].Error(), "/canvas/a: width <= 100: unsatisfiable constraint")
+
+		// Other constraints are still applied.
+		a := spec.FirstByKey(root, "a")
+		assert.Equal(a.Width(), 200)
+		assert.Equal(a.Height(), 30)
+	})
+
+	t.Run("Returns unsatisfiable constraints", func(t *testing.T) {
+		root := createCanvas(
+			opts.Child(fakes.Fake(opts.Key("a"), opts.Constraints("width >= 200", "width <= 100"))),
+		)
+		failures := layout.LayoutWithErrors(root, surface.NewSurface())
+		assert.Equal(len(failures), 1)
+		assert.Equal(failures[0].Constraint, "width <= 100")
+
+		// Clean trees are not laid out again.
+		assert.Equal(len(layout.LayoutWithErrors(root, surface.NewSurface())), 0)
+	})
+
+	t.Run("Reports invalid constraints once", func(t *testing.T) {
+		failures := []*layout.ConstraintError{}
+		layout.Layout(createCanvas(
+			collectErrors(&failures),
+			opts.Child(fakes.Fake(opts.Constraints("left == unknown.right", "left ==", "size == 10", "left == previous.right"))),
+		), surface.NewSurface())
+
+		assert.Equal(len(failures), 4)
+		assert.Match("unknown key \"unknown\"", failures[0].Error())
+		assert.Match("invalid constraint: unexpected end", failures[1].Error())
+		assert.Match("unknown attribute \"size\"", failures[2].Error())
+		assert.Match("there is no previous layoutable child", failures[3].Error())
+	})
+}
@@ -0,0 +1,189 @@
+package layout
+
+import (
+	"math"
+
+	"github.com/waybeams/waybeams/pkg/spec"
+)
+
+// drawnStyle holds the properties of a node that change how it is drawn.
+type drawnStyle struct {
+	bgColor     uint
+	fontColor   uint
+	fontFace    string
+	fontSize    float64
+	specName    string
+	state       string
+	strokeColor uint
+	strokeSize  float64
+	text        string
+	visibility  spec.VisibilityValue
+}
+
+// drawnNode is what was drawn for a node of a Spec tree.
+type drawnNode struct {
+	bounds   spec.BoundingBox
+	children []*drawnNode
+	style    drawnStyle
+	subtree  spec.BoundingBox
+}
+
+// Damage tracks what was drawn for each node of a Spec tree, so that a frame
+// only needs to redraw the regions that changed since the previous frame.
+type Damage struct {
+	previous []*drawnNode
+}
+
+// Update records the provided tree, which must already be laid out, and
+// returns the region that changed since the previous Update. The region
+// covers both the previous and the current bounds of changed nodes. The
+// whole tree is damaged on the first Update.
+func (d *Damage) Update(root spec.ReadWriter) spec.BoundingBox {
+	var next []*drawnNode
+	for _, layer := range drawLayers(root) {
+		parentX, parentY := 0.0, 0.0
+		if parent := layer.Parent(); parent != nil {
+			parentX, parentY = drawOrigin(parent)
+		}
+		next = append(next, newDrawnNode(layer, parentX, parentY))
+	}
+	region := damagedLayers(d.previous, next)
+	d.previous = next
+
+	if region.IsEmpty() {
+		return region
+	}
+	// Align to whole pixels, so that anti-aliased edges are redrawn too.
+	x, y := math.Floor(region.X), math.Floor(region.Y)
+	return spec.BoundingBox{
+		X:      x,
+		Y:      y,
+		Width:  math.Ceil(region.X+region.Width) - x,
+		Height: math.Ceil(region.Y+region.Height) - y,
+	}
+}
+
+// Draw draws the provided tree onto the provided Surface, clipped to the
+// provided region. Subtrees that do not intersect the region are skipped.
+// The tree must be the one that was provided to the last Update.
+func (d *Damage) Draw(root spec.ReadWriter, s spec.Surface, region spec.BoundingBox) {
+	if d.previous == nil || region.IsEmpty() {
+		return
+	}
+	s.Scissor(region.X, region.Y, region.Width, region.Height)
+	for index, layer := range drawLayers(root) {
+		list, _ := displayListFor(layer, s)
+		x, y := drawOrigin(layer)
+		drawDamaged(list, d.previous[index], s, region, x, y)
+	}
+	s.ResetScissor()
+}
+
+func drawDamaged(list *spec.DisplayList, drawn *drawnNode, s spec.Surface, region spec.BoundingBox, x, y float64) {
+	if !drawn.subtree.Intersects(region) {
+		return
+	}
+	if drawn.bounds.Intersects(region) {
+		list.ReplayCommands(s, x, y)
+	}
+	if !list.Clip.IsEmpty() && len(list.Children) > 0 {
+		s.Save()
+		s.IntersectScissor(x+list.Clip.X, y+list.Clip.Y, list.Clip.Width, list.Clip.Height)
+		defer s.Restore()
+	}
+	for index, child := range list.Children {
+		drawDamaged(child, drawn.children[index], s, region, x+child.X, y+child.Y)
+	}
+}
+
+// NewDamage creates a Damage that has not drawn anything yet.
+func NewDamage() *Damage {
+	return &Damage{}
+}
+
+// newDrawnNode records the provided node, whose parent is at the provided
+// global coordinates.
+func newDrawnNode(r spec.Reader, parentX, parentY float64) *drawnNode {
+	x, y := parentX+r.X(), parentY+r.Y()
+	// Strokes are drawn around the bounds of a node.
+	outset := r.StrokeSize() + 1
+	node := &drawnNode{
+		bounds: spec.BoundingBox{
+			X:      x - outset,
+			Y:      y - outset,
+			Width:  r.Width() + outset*2,
+			Height: r.Height() + outset*2,
+		},
+		style: drawnStyle{
+			bgColor:     r.BgColor(),
+			fontColor:   r.FontColor(),
+			fontFace:    r.FontFace(),
+			fontSize:    r.FontSize(),
+			specName:    r.SpecName(),
+			state:       r.State(),
+			strokeColor: r.StrokeColor(),
+			strokeSize:  r.StrokeSize(),
+			text:        r.Text(),
+			visibility:  r.Visibility(),
+		},
+	}
+
+	node.subtree = node.bounds
+	children := spec.DrawOrder(r)
+	if !r.Visible() {
+		children = nil
+	}
+	node.children = make([]*drawnNode, len(children))
+	childrenBounds := spec.BoundingBox{}
+	for index, child := range children {
+		node.children[index] = newDrawnNode(child, x, y)
+		childrenBounds = childrenBounds.Union(node.children[index].subtree)
+	}
+	if r.ClipChildren() {
+		// Children are not drawn outside of a node that clips them.
+		childrenBounds = childrenBounds.Intersect(node.bounds)
+	}
+	node.subtree = node.subtree.Union(childrenBounds)
+	return node
+}
+
+// damagedLayers returns the union of the regions that differ between the
+// provided previous and next layers. Every layer is damaged when an overlay
+// was added or removed.
+func damagedLayers(previous, next []*drawnNode) spec.BoundingBox {
+	region := spec.BoundingBox{}
+	if previous != nil && len(previous) != len(next) {
+		for _, layer := range previous {
+			region = region.Union(layer.subtree)
+		}
+		previous = nil
+	}
+	for index, layer := range next {
+		var previousLayer *drawnNode
+		if previous != nil {
+			previousLayer = previous[index]
+		}
+		region = region.Union(damagedRegion(previousLayer, layer))
+	}
+	return region
+}
+
+// damagedRegion returns the union of the regions that differ between the
+// provided previous and next drawn trees.
+func damagedRegion(previous, next *drawnNode) spec.BoundingBox {
+	if previous == nil {
+		return next.subtree
+	}
+	if len(previous.children) != len(next.children) {
+		return previous.subtree.Union(next.subtree)
+	}
+
+	region := spec.BoundingBox{}
+	if previous.bounds != next.bounds || previous.style != next.style {
+		region = previous.bounds.Union(next.bounds)
+	}
+	for index, child := range next.children {
+		region = region.Union(damagedRegion(previous.children[index], child))
+	}
+	return region
+}
@@ -0,0 +1,104 @@
+package layout_test
+
+import (
+	"testing"
+
+	"github.com/waybeams/assert"
+	"github.com/waybeams/waybeams/pkg/ctrl"
+	surface "github.com/waybeams/waybeams/pkg/env/fake"
+	"github.com/waybeams/waybeams/pkg/layout"
+	"github.com/waybeams/waybeams/pkg/opts"
+	"github.com/waybeams/waybeams/pkg/spec"
+)
+
+func TestDamage(t *testing.T) {
+	var create = func(firstWidth float64, secondColor uint) spec.ReadWriter {
+		return layout.Layout(ctrl.HBox(
+			opts.Key("root"),
+			opts.Child(ctrl.Box(opts.Key("one"), opts.Width(firstWidth), opts.Height(20))),
+			opts.Child(ctrl.Box(opts.Key("two"), opts.Width(30), opts.Height(20), opts.BgColor(secondColor))),
+			opts.Child(ctrl.Box(opts.Key("three"), opts.Width(30), opts.Height(20))),
+		), surface.NewSurface())
+	}
+
+	var rects = func(s *surface.Fake) int {
+		count := 0
+		for _, cmd := range s.GetCommands() {
+			if cmd.Name == "Rect" {
+				count++
+			}
+		}
+		return count
+	}
+
+	t.Run("Damages the whole tree first", func(t *testing.T) {
+		damage := layout.NewDamage()
+		region := damage.Update(create(30, 0xff0000ff))
+		assert.Equal(region, spec.BoundingBox{X: -1, Y: -1, Width: 92, Height: 22})
+	})
+
+	t.Run("Unchanged trees are not damaged", func(t *testing.T) {
+		damage := layout.NewDamage()
+		damage.Update(create(30, 0xff0000ff))
+		assert.True(damage.Update(create(30, 0xff0000ff)).IsEmpty())
+	})
+
+	t.Run("Damages changed styles", func(t *testing.T) {
+		damage := layout.NewDamage()
+		damage.Update(create(30, 0xff0000ff))
+		region := damage.Update(create(30, 0x00ff00ff))
+		assert.Equal(region, spec.BoundingBox{X: 29, Y: -1, Width: 32, Height: 22})
+	})
+
+	t.Run("Damages previous and next bounds", func(t *testing.T) {
+		damage := layout.NewDamage()
+		damage.Update(create(30, 0xff0000ff))
+		// Growing the first box moves the others.
+		region := damage.Update(create(40, 0xff0000ff))
+		assert.Equal(region, spec.BoundingBox{X: -1, Y: -1, Width: 102, Height: 22})
+	})
+
+	t.Run("Draws only the damaged region", func(t *testing.T) {
+		damage := layout.NewDamage()
+		damage.Update(create(30, 0xff0000ff))
+		root := create(30, 0x00ff00ff)
+		region := damage.Update(root)
+
+		s := surface.NewSurface()
+		damage.Draw(root, s, region)
+		commands := s.GetCommands()
+		assert.Equal(commands[0].Name, "Scissor")
+		assert.Equal(commands[len(commands)-1].Name, "ResetScissor")
+		// The root, the box that changed and both of its neighbors, whose
+		// strokes overlap it, each draw a fill and a stroke.
+		assert.Equal(rects(s), 8)
+	})
+
+	t.Run("Damages and draws overlays", func(t *testing.T) {
+		var createWithPopup = func(isOpen bool) spec.ReadWriter {
+			return layout.Layout(ctrl.HBox(
+				opts.Child(ctrl.Box(
+					opts.Width(30),
+					opts.Height(20),
+					opts.Child(ctrl.Box(
+						opts.IsOverlay(true),
+						opts.Visible(isOpen),
+						opts.Y(20),
+						opts.Width(40),
+						opts.Height(40),
+					)),
+				)),
+			), surface.NewSurface())
+		}
+		damage := layout.NewDamage()
+		damage.Update(createWithPopup(false))
+		root := createWithPopup(true)
+		region := damage.Update(root)
+		assert.Equal(region, spec.BoundingBox{X: -1, Y: -1, Width: 42, Height: 62})
+
+		s := surface.NewSurface()
+		damage.Draw(root, s, spec.BoundingBox{X: 0, Y: 50, Width: 10, Height: 10})
+		// Only the overlay intersects the region.
+		assert.Equal(rects(s), 2)
+	})
+}
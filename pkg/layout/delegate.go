@@ -6,9 +6,15 @@ import "github.com/waybeams/waybeams/pkg/spec"
 type Delegate interface {
 	ActualSize(d spec.Reader) float64
 	Align(d spec.Reader) spec.Alignment
+	AspectSize(d spec.Reader) float64
+	Axis() spec.LayoutAxis
+	ChildrenSize(d spec.Reader) float64
 	Flex(d spec.Reader) float64 // GetPercent?
 	IsFlexible(d spec.Reader) bool
 	LayoutSpec(c spec.ReadWriter) (updatedSize float64)
+	Margin(d spec.Reader) float64
+	MarginFirst(d spec.Reader) float64
+	MarginLast(d spec.Reader) float64
 	MaxSize(d spec.Reader) float64
 	MinSize(d spec.Reader) float64
 	Padding(d spec.Reader) float64
@@ -16,6 +22,7 @@ type Delegate interface {
 	PaddingLast(d spec.Reader) float64
 	PaddingOnAxis(d spec.Reader) float64
 	Position(d spec.Reader) float64
+	Preferred(d spec.Reader) float64
 	SetActualSize(d spec.Writer, size float64)
 	SetChildrenSize(d spec.Writer, size float64)
 	SetContentSize(d spec.Writer, size float64)
@@ -24,10 +31,6 @@ type Delegate interface {
 	Size(d spec.Reader) float64
 
 	/*
-		Axis() spec.LayoutAxis
-		ChildrenSize(d spec.Reader) float64
 		InferredSize(d spec.Reader) float64
-		Position(d spec.Reader) float64
-		Preferred(d spec.Reader) float64
 	*/
 }
@@ -0,0 +1,152 @@
+package layout
+
+import (
+	"math"
+
+	"github.com/waybeams/waybeams/pkg/spec"
+)
+
+// dockSide is how a child is placed on the axis of a delegate.
+type dockSide int
+
+const (
+	dockFree dockSide = iota
+	dockFirst
+	dockLast
+	dockSpan
+)
+
+// dockGetSide returns how the provided child is placed on the axis of the
+// provided delegate.
+func dockGetSide(delegate Delegate, child spec.Reader) dockSide {
+	first, last := spec.DockLeft, spec.DockRight
+	if delegate.Axis() == spec.LayoutVertical {
+		first, last = spec.DockTop, spec.DockBottom
+	}
+
+	switch child.Dock() {
+	case spec.DockNone:
+		return dockFree
+	case first:
+		return dockFirst
+	case last:
+		return dockLast
+	default:
+		return dockSpan
+	}
+}
+
+// dockGetAnchors returns whether the provided child is anchored to the first
+// and last edges of its parent on the axis of the provided delegate, and the
+// offset from each edge.
+func dockGetAnchors(delegate Delegate, child spec.Reader) (hasFirst, hasLast bool, first, last float64) {
+	anchors := child.Anchors()
+	if delegate.Axis() == spec.LayoutVertical {
+		return anchors.Has(spec.EdgeTop), anchors.Has(spec.EdgeBottom), anchors.Top, anchors.Bottom
+	}
+	return anchors.Has(spec.EdgeLeft), anchors.Has(spec.EdgeRight), anchors.Left, anchors.Right
+}
+
+// dockIsAnchored returns true if the provided child is positioned by its
+// anchors rather than by the remaining space.
+func dockIsAnchored(child spec.Reader) bool {
+	return child.Dock() == spec.DockNone && child.Anchors().Edges != 0
+}
+
+// DockOnAxis performs a Dock layout on the provided delegate axis.
+//
+// Children are attached in order to the edges of the space that remains
+// inside the parent padding. DockLeft and DockRight children (DockTop and
+// DockBottom vertically) keep their size and take space from that edge,
+// while the other docked children stretch across the remaining space.
+// DockFill children fill whatever space remains on both axes.
+//
+// Children that are not docked, but pin edges with AnchorLeft, AnchorTop,
+// AnchorRight or AnchorBottom, are positioned from the matching edges of the
+// parent, ignoring docked siblings, and stretch when both edges on an axis
+// are pinned. Any other child is aligned within the remaining space by the
+// HAlign or VAlign of the parent, like a Stack.
+func DockOnAxis(delegate Delegate, d spec.ReadWriter) (childrenSize float64) {
+	if d.ChildCount() == 0 {
+		return delegate.Size(d)
+	}
+
+	// Lay out every child first, so that nested containers report their
+	// content size.
+	for _, child := range d.Children() {
+		delegate.LayoutSpec(child)
+	}
+
+	children := getLayoutableChildren(d)
+	childrenSize = dockGetChildrenSize(delegate, children)
+	paddingFirst := delegate.PaddingFirst(d)
+	available := math.Max(delegate.Size(d)-delegate.Padding(d), childrenSize)
+
+	first, last := paddingFirst, paddingFirst+available
+	for _, child := range children {
+		if dockIsAnchored(child) {
+			dockPositionAnchored(delegate, child, paddingFirst, available)
+			continue
+		}
+
+		switch dockGetSide(delegate, child) {
+		case dockFirst:
+			delegate.SetPosition(child, first)
+			first += delegate.Size(child)
+		case dockLast:
+			last -= delegate.Size(child)
+			delegate.SetPosition(child, last)
+		case dockSpan:
+			delegate.SetSize(child, math.Max(0, last-first))
+			delegate.LayoutSpec(child)
+			delegate.SetPosition(child, first)
+		default:
+			delegate.SetPosition(child, first+getAlignOffset(delegate.Align(d), last-first, delegate.Size(child)))
+		}
+	}
+
+	delegate.SetChildrenSize(d, childrenSize)
+	return childrenSize
+}
+
+// dockPositionAnchored positions and sizes a child by its anchors within the
+// provided space.
+func dockPositionAnchored(delegate Delegate, child spec.ReadWriter, start, space float64) {
+	hasFirst, hasLast, first, last := dockGetAnchors(delegate, child)
+	switch {
+	case hasFirst && hasLast:
+		delegate.SetSize(child, math.Max(0, space-first-last))
+		delegate.LayoutSpec(child)
+		delegate.SetPosition(child, start+first)
+	case hasLast:
+		delegate.SetPosition(child, start+space-last-delegate.Size(child))
+	default:
+		delegate.SetPosition(child, start+first)
+	}
+}
+
+// dockGetChildrenSize returns the smallest space that fits every child on
+// the axis of the provided delegate.
+func dockGetChildrenSize(delegate Delegate, children []spec.ReadWriter) float64 {
+	// Each docked child only sees the space left by the children before
+	// it, so work backwards from the last one.
+	docked := 0.0
+	anchored := 0.0
+	for index := len(children) - 1; index >= 0; index-- {
+		child := children[index]
+		size := delegate.Size(child)
+		if dockIsAnchored(child) {
+			_, _, first, last := dockGetAnchors(delegate, child)
+			anchored = math.Max(anchored, first+size+last)
+			continue
+		}
+
+		switch dockGetSide(delegate, child) {
+		case dockFirst, dockLast:
+			docked += size
+		default:
+			docked = math.Max(docked, size)
+		}
+	}
+	return math.Max(docked, anchored)
+}
@@ -0,0 +1,141 @@
+package layout_test
+
+import (
+	"testing"
+
+	"github.com/waybeams/assert"
+	"github.com/waybeams/waybeams/pkg/ctrl"
+	surface "github.com/waybeams/waybeams/pkg/env/fake"
+	"github.com/waybeams/waybeams/pkg/fakes"
+	"github.com/waybeams/waybeams/pkg/layout"
+	"github.com/waybeams/waybeams/pkg/opts"
+	"github.com/waybeams/waybeams/pkg/spec"
+)
+
+func TestDockLayout(t *testing.T) {
+	var createShell = func(options ...spec.Option) spec.ReadWriter {
+		defaults := []spec.Option{
+			opts.Width(400),
+			opts.Height(300),
+			opts.Padding(10),
+			opts.Child(fakes.Fake(opts.Key("toolbar"), opts.Dock(spec.DockTop), opts.Height(30))),
+			opts.Child(fakes.Fake(opts.Key("status"), opts.Dock(spec.DockBottom), opts.Height(20))),
+			opts.Child(fakes.Fake(opts.Key("sidebar"), opts.Dock(spec.DockLeft), opts.Width(80))),
+			opts.Child(fakes.Fake(opts.Key("content"), opts.Dock(spec.DockFill))),
+			opts.Child(fakes.Fake(opts.Key("excluded"), opts.ExcludeFromLayout(true), opts.X(5), opts.Y(6))),
+		}
+		return ctrl.DockPanel(append(defaults, options...)...)
+	}
+
+	t.Run("Application shell", func(t *testing.T) {
+		root := layout.Layout(createShell(), surface.NewSurface())
+
+		toolbar := spec.FirstByKey(root, "toolbar")
+		assert.Equal(toolbar.X(), 10)
+		assert.Equal(toolbar.Y(), 10)
+		assert.Equal(toolbar.Width(), 380)
+		assert.Equal(toolbar.Height(), 30)
+
+		status := spec.FirstByKey(root, "status")
+		assert.Equal(status.X(), 10)
+		assert.Equal(status.Y(), 270)
+		assert.Equal(status.Width(), 380)
+
+		sidebar := spec.FirstByKey(root, "sidebar")
+		assert.Equal(sidebar.X(), 10)
+		assert.Equal(sidebar.Y(), 40)
+		assert.Equal(sidebar.Width(), 80)
+		assert.Equal(sidebar.Height(), 230)
+
+		content := spec.FirstByKey(root, "content")
+		assert.Equal(content.X(), 90)
+		assert.Equal(content.Y(), 40)
+		assert.Equal(content.Width(), 300)
+		assert.Equal(content.Height(), 230)
+	})
+
+	t.Run("DockRight takes space from the right", func(t *testing.T) {
+		root := layout.Layout(createShell(
+			opts.Child(fakes.Fake(opts.Key("inspector"), opts.Dock(spec.DockRight), opts.Width(50))),
+		), surface.NewSurface())
+
+		inspector := spec.FirstByKey(root, "inspector")
+		assert.Equal(inspector.X(), 340)
+		assert.Equal(inspector.Y(), 40)
+		assert.Equal(inspector.Height(), 230)
+	})
+
+	t.Run("Respects ExcludeFromLayout", func(t *testing.T) {
+		root := layout.Layout(createShell(), surface.NewSurface())
+		excluded := spec.FirstByKey(root, "excluded")
+		assert.Equal(excluded.X(), 5)
+		assert.Equal(excluded.Y(), 6)
+	})
+
+	t.Run("Sized by children", func(t *testing.T) {
+		root := layout.Layout(ctrl.DockPanel(
+			opts.Padding(5),
+			opts.Child(fakes.Fake(opts.Dock(spec.DockTop), opts.Width(100), opts.Height(30))),
+			opts.Child(fakes.Fake(opts.Dock(spec.DockLeft), opts.Width(50), opts.Height(40))),
+			opts.Child(fakes.Fake(opts.Key("fill"), opts.Dock(spec.DockFill), opts.Width(20), opts.Height(10))),
+		), surface.NewSurface())
+
+		assert.Equal(root.ChildrenWidth(), 100)
+		assert.Equal(root.ChildrenHeight(), 70)
+		assert.Equal(root.Width(), 110)
+		assert.Equal(root.Height(), 80)
+
+		fill := spec.FirstByKey(root, "fill")
+		assert.Equal(fill.X(), 55)
+		assert.Equal(fill.Width(), 50)
+		assert.Equal(fill.Height(), 40)
+	})
+
+	t.Run("Anchors", func(t *testing.T) {
+		root := layout.Layout(ctrl.DockPanel(
+			opts.Width(200),
+			opts.Height(100),
+			opts.Padding(10),
+			opts.Child(fakes.Fake(opts.Dock(spec.DockLeft), opts.Width(50))),
+			opts.Child(fakes.Fake(
+				opts.Key("corner"),
+				opts.AnchorRight(5),
+				opts.AnchorBottom(5),
+				opts.Width(20),
+				opts.Height(10),
+			)),
+			opts.Child(fakes.Fake(
+				opts.Key("stretched"),
+				opts.AnchorLeft(5),
+				opts.AnchorRight(5),
+				opts.AnchorTop(0),
+				opts.Height(10),
+			)),
+		), surface.NewSurface())
+
+		corner := spec.FirstByKey(root, "corner")
+		assert.Equal(corner.X(), 165)
+		assert.Equal(corner.Y(), 75)
+
+		// Anchors ignore docked siblings.
+		stretched := spec.FirstByKey(root, "stretched")
+		assert.Equal(stretched.X(), 15)
+		assert.Equal(stretched.Y(), 10)
+		assert.Equal(stretched.Width(), 170)
+	})
+
+	t.Run("Free children are aligned in the remaining space", func(t *testing.T) {
+		root := layout.Layout(ctrl.DockPanel(
+			opts.Width(200),
+			opts.Height(100),
+			opts.HAlign(spec.AlignCenter),
+			opts.VAlign(spec.AlignTop),
+			opts.Child(fakes.Fake(opts.Dock(spec.DockLeft), opts.Width(50))),
+			opts.Child(fakes.Fake(opts.Key("free"), opts.Width(10), opts.Height(10))),
+		), surface.NewSurface())
+
+		free := spec.FirstByKey(root, "free")
+		assert.Equal(free.X(), 120)
+		assert.Equal(free.Y(), 0)
+	})
+}
@@ -1,20 +1,122 @@
 package layout
 
 import (
+	"reflect"
+
 	"github.com/waybeams/waybeams/pkg/spec"
 	"github.com/waybeams/waybeams/pkg/views"
 )
 
-// Draw the provided spec tree onto the provided Surface
-func Draw(r spec.Reader, s spec.Surface) {
-	s = spec.NewOffsetSurface(r, s)
+// displayKey holds the properties of a node that its RenderHandler draws
+// from. Coordinates are relative to the node, so that a node that only moves
+// can keep its Commands.
+type displayKey struct {
+	bgColor      uint
+	clipChildren bool
+	fontColor    uint
+	fontFace     string
+	fontSize     float64
+	height       float64
+	specName     string
+	state        string
+	strokeColor  uint
+	strokeSize   float64
+	text         string
+	textX        float64
+	textY        float64
+	view         uintptr
+	visibility   spec.VisibilityValue
+	width        float64
+}
+
+func newDisplayKey(r spec.Reader, view spec.RenderHandler) displayKey {
+	return displayKey{
+		bgColor:      r.BgColor(),
+		clipChildren: r.ClipChildren(),
+		fontColor:    r.FontColor(),
+		fontFace:     r.FontFace(),
+		fontSize:     r.FontSize(),
+		height:       r.Height(),
+		specName:     r.SpecName(),
+		state:        r.State(),
+		strokeColor:  r.StrokeColor(),
+		strokeSize:   r.StrokeSize(),
+		text:         r.Text(),
+		textX:        r.TextX() - r.X(),
+		textY:        r.TextY() - r.Y(),
+		view:         reflect.ValueOf(view).Pointer(),
+		visibility:   r.Visibility(),
+		width:        r.Width(),
+	}
+}
+
+// Draw the provided spec tree onto the provided Surface. The Commands of each
+// node are kept in its DisplayList and replayed, rather than drawn again,
+// while the properties it is drawn from do not change. Children are drawn in
+// spec.DrawOrder, and the Overlays of the tree are drawn above it.
+func Draw(r spec.ReadWriter, s spec.Surface) {
+	for _, layer := range drawLayers(r) {
+		list, _ := displayListFor(layer, s)
+		x, y := drawOrigin(layer)
+		list.Replay(s, x, y)
+	}
+}
+
+// drawOrigin returns the coordinates that the provided node is drawn at. Unlike
+// spec.LocalToGlobal, these include the position of the root.
+func drawOrigin(r spec.ReadWriter) (x, y float64) {
+	root := spec.Root(r)
+	x, y = spec.LocalToGlobal(r, 0, 0)
+	return x + root.X(), y + root.Y()
+}
+
+// drawLayers returns the provided tree, followed by its overlay stack.
+func drawLayers(r spec.ReadWriter) []spec.ReadWriter {
+	layers := []spec.ReadWriter{r}
+	if r.Visible() {
+		layers = append(layers, spec.Overlays(r)...)
+	}
+	return layers
+}
+
+// displayListFor returns the DisplayList of the provided node, which is only
+// recorded again for nodes whose properties have changed. The returned flag is
+// true when the previous DisplayList of the whole subtree was reused.
+func displayListFor(r spec.ReadWriter, s spec.Surface) (*spec.DisplayList, bool) {
 	view := r.View()
 	if view == nil {
 		view = views.RectangleView
 	}
-	view(s, r)
+	key := newDisplayKey(r, view)
+	previous := r.DisplayList()
+	children := spec.DrawOrder(r)
+	if !r.Visible() {
+		children = nil
+	}
 
-	for _, child := range r.Children() {
-		Draw(child, s)
+	isReused := previous != nil && previous.Key == key && previous.X == r.X() &&
+		previous.Y == r.Y() && len(previous.Children) == len(children)
+	childLists := make([]*spec.DisplayList, len(children))
+	for index, child := range children {
+		childList, isChildReused := displayListFor(child, s)
+		childLists[index] = childList
+		isReused = isReused && isChildReused && previous.Children[index] == childList
+	}
+	if isReused {
+		return previous, true
+	}
+
+	list := &spec.DisplayList{Children: childLists, Key: key, X: r.X(), Y: r.Y()}
+	if r.ClipChildren() {
+		list.Clip = spec.BoundingBox{Width: r.Width(), Height: r.Height()}
+	}
+	if previous != nil && previous.Key == key {
+		list.Commands = previous.Commands
+	} else if r.Visible() {
+		recorder := spec.NewRecorder(s, -r.X(), -r.Y())
+		view(recorder, r)
+		list.Commands = recorder.Commands()
 	}
+	r.SetDisplayList(list)
+	return list, false
 }
@@ -0,0 +1,198 @@
+package layout_test
+
+import (
+	"testing"
+
+	"github.com/waybeams/assert"
+	"github.com/waybeams/waybeams/pkg/ctrl"
+	surface "github.com/waybeams/waybeams/pkg/env/fake"
+	"github.com/waybeams/waybeams/pkg/layout"
+	"github.com/waybeams/waybeams/pkg/opts"
+	"github.com/waybeams/waybeams/pkg/spec"
+	"github.com/waybeams/waybeams/pkg/views"
+)
+
+func TestDraw(t *testing.T) {
+	var drawCount int
+	var countingView = func(s spec.Surface, r spec.Reader) {
+		drawCount++
+		views.RectangleView(s, r)
+	}
+
+	var firstRect = func(s *surface.Fake) []interface{} {
+		for _, cmd := range s.GetCommands() {
+			if cmd.Name == "Rect" {
+				return cmd.Args
+			}
+		}
+		return nil
+	}
+
+	var rectsOf = func(s *surface.Fake) []surface.Command {
+		rects := []surface.Command{}
+		for _, cmd := range s.GetCommands() {
+			if cmd.Name == "Rect" {
+				rects = append(rects, cmd)
+			}
+		}
+		return rects
+	}
+
+	t.Run("Draws children in global coordinates", func(t *testing.T) {
+		root := layout.Layout(ctrl.VBox(
+			opts.X(5),
+			opts.Padding(10),
+			opts.Child(ctrl.Box(opts.Width(20), opts.Height(30))),
+		), surface.NewSurface())
+		s := surface.NewSurface()
+		layout.Draw(root, s)
+		rects := rectsOf(s)
+		assert.Equal(len(rects), 4)
+		assert.Equal(rects[2].Args[0], 15.0)
+		assert.Equal(rects[2].Args[1], 10.0)
+		assert.Equal(rects[2].Args[2], 20.0)
+		assert.Equal(rects[2].Args[3], 30.0)
+	})
+
+	t.Run("Does not draw hidden subtrees", func(t *testing.T) {
+		root := layout.Layout(ctrl.VBox(
+			opts.Child(ctrl.Box(
+				opts.Visible(false),
+				opts.Child(ctrl.Box(opts.Width(20), opts.Height(30))),
+			)),
+		), surface.NewSurface())
+		s := surface.NewSurface()
+		layout.Draw(root, s)
+		assert.Equal(len(rectsOf(s)), 2)
+
+		root.ChildAt(0).SetVisible(true)
+		s = surface.NewSurface()
+		layout.Draw(root, s)
+		assert.Equal(len(rectsOf(s)), 6)
+	})
+
+	t.Run("Draws children by ZIndex", func(t *testing.T) {
+		root := layout.Layout(ctrl.HBox(
+			opts.Child(ctrl.Box(opts.Width(10), opts.Height(10), opts.ZIndex(1))),
+			opts.Child(ctrl.Box(opts.Width(20), opts.Height(10))),
+		), surface.NewSurface())
+		s := surface.NewSurface()
+		layout.Draw(root, s)
+		rects := rectsOf(s)
+		assert.Equal(len(rects), 6)
+		assert.Equal(rects[2].Args[2], 20.0)
+		assert.Equal(rects[4].Args[2], 10.0)
+	})
+
+	t.Run("Draws overlays above the tree", func(t *testing.T) {
+		root := layout.Layout(ctrl.VBox(
+			opts.Child(ctrl.Box(
+				opts.Width(10),
+				opts.Height(10),
+				opts.Child(ctrl.Box(opts.IsOverlay(true), opts.X(5), opts.Y(5), opts.Width(30), opts.Height(30))),
+			)),
+			opts.Child(ctrl.Box(opts.Width(20), opts.Height(10), opts.ZIndex(5))),
+		), surface.NewSurface())
+		s := surface.NewSurface()
+		layout.Draw(root, s)
+		rects := rectsOf(s)
+		assert.Equal(len(rects), 8)
+		overlay := rects[6].Args
+		assert.Equal(overlay[0], root.ChildAt(0).X()+5)
+		assert.Equal(overlay[1], 5.0)
+		assert.Equal(overlay[2], 30.0)
+	})
+
+	t.Run("Clips children", func(t *testing.T) {
+		root := layout.Layout(ctrl.VBox(
+			opts.X(10),
+			opts.Width(50),
+			opts.Height(40),
+			opts.ClipChildren(true),
+			opts.Child(ctrl.Box(opts.ExcludeFromLayout(true), opts.X(40), opts.Width(30), opts.Height(30))),
+		), surface.NewSurface())
+		s := surface.NewSurface()
+		layout.Draw(root, s)
+
+		names := []string{}
+		for _, cmd := range s.GetCommands() {
+			names = append(names, cmd.Name)
+		}
+		assert.Equal(names[9], "Save")
+		assert.Equal(names[10], "IntersectScissor")
+		assert.Equal(names[len(names)-1], "Restore")
+		clip := s.GetCommands()[10].Args
+		assert.Equal(clip[0], 10.0)
+		assert.Equal(clip[1], 0.0)
+		assert.Equal(clip[2], 50.0)
+		assert.Equal(clip[3], 40.0)
+	})
+
+	t.Run("Replays unchanged subtrees", func(t *testing.T) {
+		drawCount = 0
+		root := layout.Layout(ctrl.VBox(
+			opts.Child(ctrl.Box(opts.View(countingView), opts.Width(20), opts.Height(30))),
+		), surface.NewSurface())
+		first := surface.NewSurface()
+		layout.Draw(root, first)
+		second := surface.NewSurface()
+		layout.Draw(root, second)
+
+		assert.Equal(drawCount, 1)
+		assert.Equal(len(second.GetCommands()), len(first.GetCommands()))
+		assert.True(root.DisplayList() != nil)
+	})
+
+	t.Run("Records changed nodes again", func(t *testing.T) {
+		drawCount = 0
+		var box spec.ReadWriter
+		root := layout.Layout(ctrl.VBox(
+			opts.Child(ctrl.Box(opts.View(countingView), opts.Width(20), opts.Height(30))),
+		), surface.NewSurface())
+		box = root.ChildAt(0)
+		layout.Draw(root, surface.NewSurface())
+		previous := root.DisplayList()
+
+		box.SetBgColor(0xff0000ff)
+		s := surface.NewSurface()
+		layout.Draw(root, s)
+		assert.Equal(drawCount, 2)
+		assert.True(root.DisplayList() != previous)
+		// The unchanged parent keeps its Commands.
+		assert.Equal(len(root.DisplayList().Commands), len(previous.Commands))
+	})
+
+	t.Run("Moved nodes are replayed at their new position", func(t *testing.T) {
+		drawCount = 0
+		root := layout.Layout(ctrl.Box(
+			opts.View(countingView),
+			opts.Width(20),
+			opts.Height(30),
+		), surface.NewSurface())
+		layout.Draw(root, surface.NewSurface())
+
+		root.SetX(40)
+		s := surface.NewSurface()
+		layout.Draw(root, s)
+		assert.Equal(drawCount, 1)
+		assert.Equal(firstRect(s)[0], 40.0)
+	})
+
+	t.Run("Retains DisplayLists across renders", func(t *testing.T) {
+		drawCount = 0
+		reconciler := spec.NewReconciler()
+		var create = func() spec.ReadWriter {
+			return ctrl.VBox(
+				opts.Key("root"),
+				opts.Child(ctrl.Box(opts.Key("box"), opts.View(countingView), opts.Width(20), opts.Height(30))),
+			)
+		}
+		root := layout.Layout(reconciler.Reconcile(create()), surface.NewSurface())
+		layout.Draw(root, surface.NewSurface())
+
+		next := layout.Layout(reconciler.Reconcile(create()), surface.NewSurface())
+		layout.Draw(next, surface.NewSurface())
+		assert.Equal(drawCount, 1)
+		assert.True(next.DisplayList() == root.DisplayList())
+	})
+}
@@ -0,0 +1,217 @@
+package layout
+
+import (
+	"math"
+
+	"github.com/waybeams/waybeams/pkg/spec"
+)
+
+// gridPlacement is the zero-based location of a child within a Grid.
+type gridPlacement struct {
+	child      spec.ReadWriter
+	column     int
+	columnSpan int
+	row        int
+	rowSpan    int
+}
+
+// start returns the first track and the number of tracks the placement
+// covers on the axis of the provided delegate.
+func (p gridPlacement) start(delegate Delegate) (start, span int) {
+	if delegate.Axis() == spec.LayoutHorizontal {
+		return p.column, p.columnSpan
+	}
+	return p.row, p.rowSpan
+}
+
+// GridOnAxis performs a Grid layout on the provided delegate axis.
+//
+// Children are placed in the cells described by GridColumns and GridRows,
+// either explicitly with GridColumn and GridRow, or automatically in the
+// next free cell, row by row. Children that are placed beyond the declared
+// tracks create additional auto tracks.
+//
+// Fixed tracks always have their declared size. Auto tracks fit the largest
+// child placed only in that track, and flexible tracks share whatever space
+// remains, but will not shrink below their content. Children that span
+// multiple tracks do not contribute to track sizes. Children that are
+// flexible on an axis fill their cell, while others are aligned within it by
+// the HAlign or VAlign of the Grid.
+func GridOnAxis(delegate Delegate, d spec.ReadWriter) (childrenSize float64) {
+	if d.ChildCount() == 0 {
+		return delegate.Size(d)
+	}
+
+	// Lay out children that are not going to be sized by the Grid first, so
+	// that nested containers report their content size to auto tracks.
+	for _, child := range d.Children() {
+		if isExcludedFromLayout(child) || !delegate.IsFlexible(child) {
+			delegate.LayoutSpec(child)
+		}
+	}
+
+	placements, columnCount, rowCount := gridGetPlacements(d)
+	tracks, count, gutter := d.GridColumns(), columnCount, d.ColumnGutter()
+	if delegate.Axis() == spec.LayoutVertical {
+		tracks, count, gutter = d.GridRows(), rowCount, d.RowGutter()
+	}
+
+	sizes := gridGetTrackSizes(delegate, d, tracks, count, gutter, placements)
+	offsets := make([]float64, count)
+	position := delegate.PaddingFirst(d)
+	for index, size := range sizes {
+		offsets[index] = position
+		position += size + gutter
+	}
+
+	for _, placement := range placements {
+		start, span := placement.start(delegate)
+		cellSize := gutter * float64(span-1)
+		for _, size := range sizes[start : start+span] {
+			cellSize += size
+		}
+
+		child := placement.child
+		if delegate.IsFlexible(child) {
+			delegate.SetSize(child, cellSize)
+			delegate.LayoutSpec(child)
+		}
+		delegate.SetPosition(child, offsets[start]+getAlignOffset(delegate.Align(d), cellSize, delegate.Size(child)))
+	}
+
+	childrenSize = position - gutter - delegate.PaddingFirst(d)
+	delegate.SetChildrenSize(d, childrenSize)
+	return childrenSize
+}
+
+// gridGetPlacements assigns a cell to every layoutable child of the provided
+// Grid and returns the number of columns and rows that are needed.
+func gridGetPlacements(d spec.ReadWriter) (placements []gridPlacement, columnCount, rowCount int) {
+	children := getLayoutableChildren(d)
+
+	columnCount = len(d.GridColumns())
+	for _, child := range children {
+		if child.GridColumn() > 0 {
+			columnCount = maxInt(columnCount, child.GridColumn()-1+child.GridColumnSpan())
+		}
+	}
+	columnCount = maxInt(columnCount, 1)
+
+	occupied := make(map[[2]int]bool)
+	var isFree = func(row, column, rowSpan, columnSpan int) bool {
+		if column+columnSpan > columnCount {
+			return false
+		}
+		for r := row; r < row+rowSpan; r++ {
+			for c := column; c < column+columnSpan; c++ {
+				if occupied[[2]int{r, c}] {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	cursorRow, cursorColumn := 0, 0
+	for _, child := range children {
+		placement := gridPlacement{
+			child:      child,
+			column:     child.GridColumn() - 1,
+			columnSpan: child.GridColumnSpan(),
+			row:        child.GridRow() - 1,
+			rowSpan:    child.GridRowSpan(),
+		}
+		if placement.column < 0 {
+			placement.columnSpan = minInt(placement.columnSpan, columnCount)
+		}
+
+		switch {
+		case placement.row > -1 && placement.column > -1:
+			// Explicitly placed.
+		case placement.row > -1:
+			placement.column = 0
+			for column := 0; column+placement.columnSpan <= columnCount; column++ {
+				if isFree(placement.row, column, placement.rowSpan, placement.columnSpan) {
+					placement.column = column
+					break
+				}
+			}
+		case placement.column > -1:
+			placement.row = 0
+			for !isFree(placement.row, placement.column, placement.rowSpan, placement.columnSpan) {
+				placement.row++
+			}
+		default:
+			row, column := cursorRow, cursorColumn
+			for !isFree(row, column, placement.rowSpan, placement.columnSpan) {
+				column++
+				if column+placement.columnSpan > columnCount {
+					row++
+					column = 0
+				}
+			}
+			placement.row, placement.column = row, column
+			cursorRow, cursorColumn = row, column+placement.columnSpan
+		}
+
+		for r := placement.row; r < placement.row+placement.rowSpan; r++ {
+			for c := placement.column; c < placement.column+placement.columnSpan; c++ {
+				occupied[[2]int{r, c}] = true
+			}
+		}
+		rowCount = maxInt(rowCount, placement.row+placement.rowSpan)
+		placements = append(placements, placement)
+	}
+
+	rowCount = maxInt(rowCount, len(d.GridRows()))
+	return placements, columnCount, rowCount
+}
+
+// gridGetTrackSizes returns the size of each track on the axis of the
+// provided delegate.
+func gridGetTrackSizes(delegate Delegate, d spec.ReadWriter, tracks []spec.GridTrack, count int, gutter float64, placements []gridPlacement) []float64 {
+	sizes := make([]float64, count)
+	for _, placement := range placements {
+		start, span := placement.start(delegate)
+		if span == 1 && !delegate.IsFlexible(placement.child) {
+			sizes[start] = math.Max(sizes[start], delegate.Size(placement.child))
+		}
+	}
+
+	available := delegate.Size(d) - delegate.Padding(d) - gutter*float64(count-1)
+	flexSum := 0.0
+	for index := range sizes {
+		if index < len(tracks) && tracks[index].IsFlexible() {
+			flexSum += tracks[index].Flex
+			continue
+		}
+		if index < len(tracks) && !tracks[index].IsAuto() {
+			sizes[index] = tracks[index].Size
+		}
+		available -= sizes[index]
+	}
+
+	if flexSum > 0 {
+		unitSize := math.Max(0, available) / flexSum
+		for index, track := range tracks {
+			if index < count && track.IsFlexible() {
+				sizes[index] = math.Max(sizes[index], math.Floor(track.Flex*unitSize))
+			}
+		}
+	}
+	return sizes
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,126 @@
+package layout_test
+
+import (
+	"testing"
+
+	"github.com/waybeams/assert"
+	"github.com/waybeams/waybeams/pkg/ctrl"
+	surface "github.com/waybeams/waybeams/pkg/env/fake"
+	"github.com/waybeams/waybeams/pkg/fakes"
+	"github.com/waybeams/waybeams/pkg/layout"
+	"github.com/waybeams/waybeams/pkg/opts"
+	"github.com/waybeams/waybeams/pkg/spec"
+)
+
+func TestGridLayout(t *testing.T) {
+	var createForm = func(options ...spec.Option) spec.ReadWriter {
+		defaults := []spec.Option{
+			opts.Key("form"),
+			opts.Width(400),
+			opts.Padding(10),
+			opts.ColumnGutter(10),
+			opts.RowGutter(5),
+			opts.HAlign(spec.AlignLeft),
+			opts.VAlign(spec.AlignTop),
+			opts.GridColumns(spec.AutoTrack(), spec.FlexTrack(1), spec.FixedTrack(50)),
+			opts.Child(ctrl.Label(opts.Key("nameLabel"), opts.Text("Name"))),
+			opts.Child(ctrl.Box(opts.Key("name"), opts.FlexWidth(1), opts.Height(20))),
+			opts.Child(ctrl.Box(opts.Key("nameIcon"), opts.Width(20), opts.Height(30))),
+			opts.Child(ctrl.Label(opts.Key("emailLabel"), opts.Text("Email address"))),
+			opts.Child(ctrl.Box(opts.Key("email"), opts.FlexWidth(1), opts.FlexHeight(1))),
+		}
+		return ctrl.Grid(append(defaults, options...)...)
+	}
+
+	t.Run("Auto tracks fit measured content", func(t *testing.T) {
+		root := layout.Layout(createForm(), surface.NewSurface())
+
+		// "Email address" is the widest Label in the first column.
+		emailLabel := spec.FirstByKey(root, "emailLabel")
+		assert.Equal(emailLabel.Width(), 131)
+		assert.Equal(spec.FirstByKey(root, "name").X(), 10+131+10)
+		assert.Equal(spec.FirstByKey(root, "email").X(), 10+131+10)
+	})
+
+	t.Run("Flex tracks share remaining space", func(t *testing.T) {
+		root := layout.Layout(createForm(), surface.NewSurface())
+		// 400 - padding (20) - gutters (20) - auto (131) - fixed (50)
+		assert.Equal(spec.FirstByKey(root, "name").Width(), 179)
+		assert.Equal(spec.FirstByKey(root, "nameIcon").X(), 10+131+10+179+10)
+		assert.Equal(root.ChildrenWidth(), 380)
+	})
+
+	t.Run("Rows fit the largest child with row gutter", func(t *testing.T) {
+		root := layout.Layout(createForm(), surface.NewSurface())
+		assert.Equal(spec.FirstByKey(root, "nameLabel").Y(), 10)
+		assert.Equal(spec.FirstByKey(root, "emailLabel").Y(), 10+30+5)
+
+		// Flexible children fill their cell.
+		email := spec.FirstByKey(root, "email")
+		assert.Equal(email.Y(), 45)
+		assert.Equal(email.Height(), 24)
+		assert.Equal(root.ChildrenHeight(), 30+5+24)
+		assert.Equal(root.Height(), 79)
+	})
+
+	t.Run("Aligns static children within cells", func(t *testing.T) {
+		root := layout.Layout(createForm(
+			opts.HAlign(spec.AlignRight),
+			opts.VAlign(spec.AlignCenter),
+		), surface.NewSurface())
+
+		nameLabel := spec.FirstByKey(root, "nameLabel")
+		assert.Equal(nameLabel.X(), 10+131-40)
+		assert.Equal(nameLabel.Y(), 10+3)
+	})
+
+	t.Run("Explicit placement and spans", func(t *testing.T) {
+		root := layout.Layout(ctrl.Grid(
+			opts.GridColumns(spec.FixedTrack(50), spec.FixedTrack(50), spec.FixedTrack(50)),
+			opts.GridRows(spec.FixedTrack(20), spec.FixedTrack(20)),
+			opts.Gutter(10),
+			opts.HAlign(spec.AlignLeft),
+			opts.VAlign(spec.AlignTop),
+			opts.Child(fakes.Fake(opts.Key("header"), opts.GridColumnSpan(3), opts.FlexWidth(1), opts.FlexHeight(1))),
+			opts.Child(fakes.Fake(opts.Key("side"), opts.GridRow(2), opts.GridColumn(3), opts.FlexHeight(1))),
+			opts.Child(fakes.Fake(opts.Key("auto"), opts.FlexWidth(1))),
+		), surface.NewSurface())
+
+		header := spec.FirstByKey(root, "header")
+		assert.Equal(header.Width(), 170)
+		assert.Equal(header.Height(), 20)
+
+		side := spec.FirstByKey(root, "side")
+		assert.Equal(side.X(), 120)
+		assert.Equal(side.Y(), 30)
+
+		// The first free cell after the header.
+		auto := spec.FirstByKey(root, "auto")
+		assert.Equal(auto.X(), 0)
+		assert.Equal(auto.Y(), 30)
+		assert.Equal(auto.Width(), 50)
+	})
+
+	t.Run("Implicit tracks", func(t *testing.T) {
+		root := layout.Layout(ctrl.Grid(
+			opts.GridColumns(spec.FixedTrack(50), spec.FixedTrack(60)),
+			opts.HAlign(spec.AlignLeft),
+			opts.VAlign(spec.AlignTop),
+			opts.Child(fakes.Fake(opts.Height(10))),
+			opts.Child(fakes.Fake(opts.Height(10))),
+			opts.Child(fakes.Fake(opts.Key("third"), opts.Height(15))),
+			opts.Child(fakes.Fake(opts.Key("far"), opts.GridColumn(4), opts.GridRow(1), opts.Width(5))),
+		), surface.NewSurface())
+
+		assert.Equal(spec.FirstByKey(root, "third").X(), 110)
+		assert.Equal(spec.FirstByKey(root, "third").Y(), 0)
+		assert.Equal(spec.FirstByKey(root, "far").X(), 110)
+		assert.Equal(root.ChildrenWidth(), 115)
+	})
+
+	t.Run("Gutter defaults both axes", func(t *testing.T) {
+		grid := ctrl.Grid(opts.Gutter(7))
+		assert.Equal(grid.ColumnGutter(), 7)
+		assert.Equal(grid.RowGutter(), 7)
+	})
+}
@@ -1,8 +1,6 @@
 package layout
 
 import (
-	"strconv"
-
 	"github.com/waybeams/waybeams/pkg/spec"
 )
 
@@ -10,18 +8,7 @@ import (
 type horizontalDelegate struct{}
 
 func (h *horizontalDelegate) LayoutSpec(c spec.ReadWriter) (updatedSize float64) {
-	switch c.LayoutType() {
-	case spec.HorizontalFlowLayoutType:
-		return FlowOnAxis(h, c)
-	case spec.VerticalFlowLayoutType:
-		fallthrough
-	case spec.StackLayoutType:
-		return StackOnAxis(h, c)
-	case spec.NoLayoutType:
-		return None(h, c)
-	default:
-		panic("ERROR: Requested LayoutTypeValue (" + strconv.Itoa(int(c.LayoutType())) + ") is not supported:")
-	}
+	return layoutSpec(h, c)
 }
 
 func (h *horizontalDelegate) ActualSize(d spec.Reader) float64 {
@@ -36,8 +23,17 @@ func (h *horizontalDelegate) Axis() spec.LayoutAxis {
 	return spec.LayoutHorizontal
 }
 
+// AspectSize returns the width implied by the height and AspectRatio of
+// the provided Spec, or zero if it has no AspectRatio.
+func (h *horizontalDelegate) AspectSize(d spec.Reader) float64 {
+	if d.AspectRatio() <= 0 {
+		return 0
+	}
+	return d.Height() * d.AspectRatio()
+}
+
 func (h *horizontalDelegate) ChildrenSize(d spec.Reader) float64 {
-	return 0
+	return d.ChildrenWidth()
 }
 
 func (h *horizontalDelegate) Flex(d spec.Reader) float64 {
@@ -52,6 +48,18 @@ func (h *horizontalDelegate) IsFlexible(d spec.Reader) bool {
 	return d.FlexWidth() > 0
 }
 
+func (h *horizontalDelegate) Margin(d spec.Reader) float64 {
+	return d.HorizontalMargin()
+}
+
+func (h *horizontalDelegate) MarginFirst(d spec.Reader) float64 {
+	return d.MarginLeft()
+}
+
+func (h *horizontalDelegate) MarginLast(d spec.Reader) float64 {
+	return d.MarginRight()
+}
+
 func (h *horizontalDelegate) MaxSize(d spec.Reader) float64 {
 	return d.MaxWidth()
 }
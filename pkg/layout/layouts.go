@@ -3,6 +3,7 @@ package layout
 import (
 	"math"
 
+	"github.com/waybeams/waybeams/pkg/events"
 	"github.com/waybeams/waybeams/pkg/spec"
 )
 
@@ -18,8 +19,12 @@ func init() {
 	vDelegate = &verticalDelegate{}
 }
 
-// Measure the provided tree, using leaf-first traversal.
+// Measure the provided tree, using leaf-first traversal. Subtrees that have
+// not changed since they were last laid out are not measured again.
 func Measure(r spec.ReadWriter, s spec.Surface) {
+	if !r.IsLayoutDirty() {
+		return
+	}
 	// Leaf first traversal
 	for _, child := range r.Children() {
 		Measure(child, s)
@@ -31,20 +36,100 @@ func Measure(r spec.ReadWriter, s spec.Surface) {
 	}
 }
 
+// MeasureForWidth asks each measured Spec that implements
+// spec.HeightForWidthMeasurer for its height at the width it was given by
+// the horizontal layout pass.
+func MeasureForWidth(r spec.ReadWriter, s spec.Surface) {
+	if !r.IsLayoutDirty() {
+		return
+	}
+	for _, child := range r.Children() {
+		MeasureForWidth(child, s)
+	}
+	if measurer, ok := r.(spec.HeightForWidthMeasurer); ok && r.IsMeasured() {
+		width := math.Max(0, r.Width()-r.HorizontalPadding())
+		r.SetContentHeight(measurer.MeasureHeightForWidth(s, width))
+	}
+}
+
 // Layout the provided control and all of it's children.
+//
+// Widths are laid out before heights, so that Specs that implement
+// spec.HeightForWidthMeasurer can report their height for the width that
+// they were given.
+//
+// Only the nodes that were marked dirty (see spec.Spec.InvalidateLayout)
+// since the previous layout are laid out again. Clean subtrees keep their
+// size and content, and are only moved by their parents. A node that only
+// contains dirty nodes is laid out again when the size of one of its
+// children changed.
 func Layout(r spec.ReadWriter, s spec.Surface) spec.ReadWriter {
-	s = spec.NewOffsetSurface(r, s)
-	Measure(r, s)
-	if r.ChildCount() == 0 {
-		return r
+	if r.IsLayoutDirty() {
+		layoutDirty(r, spec.NewOffsetSurface(r, s))
 	}
-	w := hDelegate.LayoutSpec(r)
-	h := vDelegate.LayoutSpec(r)
-	r.SetChildrenWidth(w)
-	r.SetChildrenHeight(h)
 	return r
 }
 
+// LayoutWithErrors lays out the provided tree like Layout, and returns each
+// ConstraintError that was reported by the nodes that were laid out again.
+func LayoutWithErrors(r spec.ReadWriter, s spec.Surface) []*ConstraintError {
+	var result []*ConstraintError
+	unsubscribe := r.On(events.LayoutFailed, func(e events.Event) {
+		if err, ok := e.Payload().(*ConstraintError); ok {
+			result = append(result, err)
+		}
+	})
+	defer unsubscribe()
+	Layout(r, s)
+	return result
+}
+
+// layoutDirty lays out the nodes of the provided dirty subtree that need
+// layout, and returns true if the size of the provided node changed.
+func layoutDirty(r spec.ReadWriter, s spec.Surface) bool {
+	if !r.NeedsLayout() {
+		isResized := false
+		for _, child := range r.Children() {
+			if child.IsLayoutDirty() && layoutDirty(child, s) {
+				isResized = true
+			}
+		}
+		if !isResized {
+			r.SetIsLayoutDirty(false)
+			return false
+		}
+	}
+	width, height := r.Width(), r.Height()
+	layoutNode(r, s)
+	return r.Width() != width || r.Height() != height
+}
+
+// layoutNode lays out the children of the provided node on both axes.
+func layoutNode(r spec.ReadWriter, s spec.Surface) {
+	Measure(r, s)
+	if r.ChildCount() > 0 {
+		w := hDelegate.LayoutSpec(r)
+		MeasureForWidth(r, s)
+		h := vDelegate.LayoutSpec(r)
+		r.SetChildrenWidth(w)
+		r.SetChildrenHeight(h)
+	} else {
+		MeasureForWidth(r, s)
+	}
+	clearLayoutDirty(r)
+}
+
+// clearLayoutDirty marks the provided dirty subtree as laid out.
+func clearLayoutDirty(r spec.ReadWriter) {
+	if !r.IsLayoutDirty() {
+		return
+	}
+	r.SetIsLayoutDirty(false)
+	for _, child := range r.Children() {
+		clearLayoutDirty(child)
+	}
+}
+
 // None Layout will prevent any automated layout from the current node through all children.
 func None(delegate Delegate, d spec.ReadWriter) (childrenSize float64) {
 	return delegate.Size(d)
@@ -53,7 +138,11 @@ func None(delegate Delegate, d spec.ReadWriter) (childrenSize float64) {
 func layoutStackChildren(d spec.ReadWriter, delegate Delegate) float64 {
 	maxSize := 0.0
 	for _, child := range d.Children() {
-		maxSize = math.Max(maxSize, delegate.LayoutSpec(child))
+		childrenSize := delegate.LayoutSpec(child)
+		if !isExcludedFromLayout(child) {
+			maxSize = math.Max(maxSize, childrenSize)
+			maxSize = math.Max(maxSize, delegate.Size(child)+delegate.Margin(child))
+		}
 	}
 	return maxSize
 }
@@ -94,6 +183,9 @@ func FlowOnAxis(delegate Delegate, d spec.ReadWriter) (childrenSize float64) {
 	if d.ChildCount() == 0 {
 		return delegate.Size(d)
 	}
+	for _, child := range getLayoutableChildren(d) {
+		scalePreferredChild(delegate, d, child)
+	}
 	flowScaleChildren(delegate, d, nil)
 
 	childrenSize = layoutFlowChildren(delegate, d)
@@ -104,8 +196,14 @@ func FlowOnAxis(delegate Delegate, d spec.ReadWriter) (childrenSize float64) {
 	return childrenSize
 }
 
+// isExcludedFromLayout returns true for nodes that do not take space in the
+// layout of their parent, including those that are collapsed or overlays.
+func isExcludedFromLayout(d spec.Reader) bool {
+	return d.ExcludeFromLayout() || d.IsOverlay() || d.Visibility() == spec.VisibilityCollapsed
+}
+
 func notExcludedFromLayout(d spec.Reader) bool {
-	return !d.ExcludeFromLayout()
+	return !isExcludedFromLayout(d)
 }
 
 // Collect the layoutable children of a Displayable
@@ -115,7 +213,7 @@ func getLayoutableChildren(d spec.ReadWriter) []spec.ReadWriter {
 
 func getFlexibleChildren(delegate Delegate, d spec.ReadWriter) []spec.ReadWriter {
 	return spec.FilteredChildren(d, func(child spec.Reader) bool {
-		isExcluded := child.ExcludeFromLayout()
+		isExcluded := isExcludedFromLayout(child)
 		isFlexible := delegate.IsFlexible(child)
 		return isFlexible && !isExcluded
 	})
@@ -176,14 +274,47 @@ func flowScaleChildren(delegate Delegate, d spec.ReadWriter, flexibleChildren []
 // Position the scaled children and return the new parent dimension.
 func flowPositionChildren(delegate Delegate, s spec.ReadWriter) (childrenSize float64) {
 	children := getNotExcludedFromLayoutChildren(s)
-	paddingFirst := delegate.PaddingFirst(s)
-	position := paddingFirst
 	gutter := s.Gutter()
 	for _, child := range children {
+		childrenSize += delegate.Size(child) + delegate.Margin(child) + gutter
+	}
+	childrenSize -= gutter
+
+	offset, spacing := flowGetJustifySpacing(s.Justify(), delegate.Size(s)-delegate.Padding(s)-childrenSize, len(children))
+	position := delegate.PaddingFirst(s) + offset
+	for _, child := range children {
+		position += delegate.MarginFirst(child)
 		delegate.SetPosition(child, position)
-		position = position + delegate.Size(child) + gutter
+		position = position + delegate.Size(child) + delegate.MarginLast(child) + gutter + spacing
+	}
+	return childrenSize
+}
+
+// flowGetJustifySpacing returns the offset of the first child and the space
+// to add between children in order to distribute the provided free space.
+func flowGetJustifySpacing(justify spec.JustifyValue, free float64, count int) (offset, spacing float64) {
+	if free <= 0 || count == 0 {
+		return 0, 0
+	}
+	switch justify {
+	case spec.JustifyEnd:
+		return free, 0
+	case spec.JustifyCenter:
+		return free / 2, 0
+	case spec.JustifySpaceBetween:
+		if count == 1 {
+			return 0, 0
+		}
+		return 0, free / float64(count-1)
+	case spec.JustifySpaceAround:
+		spacing = free / float64(count)
+		return spacing / 2, spacing
+	case spec.JustifySpaceEvenly:
+		spacing = free / float64(count+1)
+		return spacing, spacing
+	default:
+		return 0, 0
 	}
-	return position - gutter - paddingFirst
 }
 
 func flowSpreadRemainder(delegate Delegate, flexibleChildren []spec.ReadWriter, remainder float64) {
@@ -230,13 +361,33 @@ func stackScaleChildren(delegate Delegate, d spec.ReadWriter) (childrenSize floa
 
 	for _, child := range children {
 		if childIsFlexible(delegate, child, flexibleChildren) {
-			delegate.SetSize(child, availablePixels)
+			delegate.SetSize(child, availablePixels-delegate.Margin(child))
+		} else {
+			scalePreferredChild(delegate, d, child)
 		}
-		childrenSize = math.Max(childrenSize, delegate.Size(child))
+		childrenSize = math.Max(childrenSize, delegate.Size(child)+delegate.Margin(child))
 	}
 	return childrenSize
 }
 
+// scalePreferredChild sizes a child that is not flexible on the axis of the
+// provided delegate from its percentage PrefWidth or PrefHeight, or from its
+// AspectRatio.
+func scalePreferredChild(delegate Delegate, d spec.ReadWriter, child spec.ReadWriter) {
+	if delegate.IsFlexible(child) {
+		return
+	}
+	if preferred := delegate.Preferred(child); preferred > 0 {
+		delegate.SetSize(child, math.Floor(getAvailablePixels(delegate, d)*preferred/100))
+		return
+	}
+	// Width is derived from height only when there is no width.
+	aspectSize := delegate.AspectSize(child)
+	if aspectSize > 0 && (delegate.Axis() == spec.LayoutVertical || delegate.Size(child) == 0) {
+		delegate.SetSize(child, aspectSize)
+	}
+}
+
 // Get the (Size - Padding) on delegated axis for STACK layouts.
 func getAvailablePixels(delegate Delegate, d spec.ReadWriter) float64 {
 	return delegate.Size(d) - delegate.Padding(d)
@@ -248,51 +399,85 @@ func flowGetAvailablePixels(delegate Delegate, d spec.ReadWriter, flexibleChildr
 	for _, child := range staticChildren {
 		staticChildrenSize += math.Max(0.0, delegate.Size(child))
 	}
-	return delegate.Size(d) - delegate.Padding(d) - staticChildrenSize
+	margins := 0.0
+	for _, child := range getLayoutableChildren(d) {
+		margins += delegate.Margin(child)
+	}
+	return delegate.Size(d) - delegate.Padding(d) - staticChildrenSize - margins
 }
 
 func stackPositionChildren(delegate Delegate, d spec.ReadWriter) {
-	align := delegate.Align(d)
+	for _, child := range getLayoutableChildren(d) {
+		switch stackGetAlign(delegate, d, child) {
+		case spec.AlignLeft:
+			fallthrough
+		case spec.AlignTop:
+			stackPositionChildFirst(delegate, d, child)
+		case spec.AlignCenter:
+			stackPositionChildCenter(delegate, d, child)
+		default:
+			// case spec.AlignRight:
+			// fallthrough
+			// case spec.AlignBottom:
+			stackPositionChildLast(delegate, d, child)
+		}
+	}
+}
+
+// stackGetAlign returns the alignment of the provided child on the axis of
+// the provided delegate, which is the AlignSelf of the child when it applies
+// to that axis, or the alignment of the parent.
+func stackGetAlign(delegate Delegate, d spec.ReadWriter, child spec.Reader) spec.Alignment {
+	if child.HasAlignSelf() {
+		align := child.AlignSelf()
+		switch align {
+		case spec.AlignCenter:
+			return align
+		case spec.AlignLeft, spec.AlignRight:
+			if delegate.Axis() == spec.LayoutHorizontal {
+				return align
+			}
+		case spec.AlignTop, spec.AlignBottom:
+			if delegate.Axis() == spec.LayoutVertical {
+				return align
+			}
+		}
+	}
+	return delegate.Align(d)
+}
+
+// getAlignOffset returns the offset of an entry of the provided size within
+// the provided space, using the same alignment rules as Stack layouts.
+func getAlignOffset(align spec.Alignment, space, size float64) float64 {
 	switch align {
 	case spec.AlignLeft:
 		fallthrough
 	case spec.AlignTop:
-		stackPositionChildrenFirst(delegate, d)
+		return 0
 	case spec.AlignCenter:
-		stackPositionChildrenCenter(delegate, d)
+		fallthrough
+	case spec.AlignMiddle:
+		return (space - size) / 2
 	default:
-		// case spec.AlignRight:
-		// fallthrough
-		// case spec.AlignBottom:
-		stackPositionChildrenLast(delegate, d)
+		return space - size
 	}
 }
 
-func stackPositionChildrenFirst(delegate Delegate, d spec.ReadWriter) {
-	// Position all children in upper left of container
+func stackPositionChildFirst(delegate Delegate, d spec.ReadWriter, child spec.ReadWriter) {
+	// Position the child in upper left of container
 	pos := delegate.PaddingFirst(d)
-	for _, child := range getLayoutableChildren(d) {
-		delegate.SetPosition(child, pos)
-	}
+	delegate.SetPosition(child, pos+delegate.MarginFirst(child))
 }
 
-func stackPositionChildrenCenter(delegate Delegate, d spec.ReadWriter) {
-	// Position all children in upper left of container
-
+func stackPositionChildCenter(delegate Delegate, d spec.ReadWriter, child spec.ReadWriter) {
 	space := delegate.Size(d) - delegate.Padding(d)
-	paddingFirst := delegate.PaddingFirst(d)
-
-	for _, child := range getLayoutableChildren(d) {
-		childSize := delegate.Size(child)
-		pos := paddingFirst + ((space - childSize) / 2)
-		delegate.SetPosition(child, pos)
-	}
+	childSize := delegate.Size(child) + delegate.Margin(child)
+	pos := delegate.PaddingFirst(d) + delegate.MarginFirst(child) + ((space - childSize) / 2)
+	delegate.SetPosition(child, pos)
 }
 
-func stackPositionChildrenLast(delegate Delegate, d spec.ReadWriter) {
+func stackPositionChildLast(delegate Delegate, d spec.ReadWriter, child spec.ReadWriter) {
 	last := delegate.Size(d) - delegate.PaddingLast(d)
-	for _, child := range getLayoutableChildren(d) {
-		pos := last - delegate.Size(child)
-		delegate.SetPosition(child, pos)
-	}
+	pos := last - delegate.Size(child) - delegate.MarginLast(child)
+	delegate.SetPosition(child, pos)
 }
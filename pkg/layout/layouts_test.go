@@ -427,4 +427,386 @@ func TestLayout(t *testing.T) {
 		assert.Equal(child.Width(), 182)
 		assert.Equal(child.Height(), 34)
 	})
+
+	t.Run("Margins", func(t *testing.T) {
+		t.Run("Flow positions children after margins", func(t *testing.T) {
+			root := ctrl.VBox(
+				opts.Width(100),
+				opts.Padding(5),
+				opts.Gutter(10),
+				opts.Child(ctrl.Box(opts.Key("one"), opts.FlexWidth(1), opts.Height(20), opts.Margin(3))),
+				opts.Child(ctrl.Box(opts.Key("two"), opts.Width(50), opts.Height(20), opts.MarginTop(7))),
+			)
+			layout.Layout(root, fakeSurface())
+
+			one := spec.FirstByKey(root, "one")
+			two := spec.FirstByKey(root, "two")
+
+			assert.Equal(one.Y(), 8)
+			assert.Equal(two.Y(), 48)
+			assert.Equal(root.ChildrenHeight(), 63)
+
+			// Stack scales flexible children within their margins.
+			assert.Equal(one.Width(), 84)
+			assert.Equal(one.X(), 8)
+			assert.Equal(two.X(), 45)
+		})
+
+		t.Run("Flow flexible children share space outside margins", func(t *testing.T) {
+			root := ctrl.HBox(
+				opts.Width(100),
+				opts.Child(ctrl.Box(opts.Key("one"), opts.FlexWidth(1), opts.MarginLeft(10))),
+				opts.Child(ctrl.Box(opts.Key("two"), opts.FlexWidth(1), opts.MarginLeft(10))),
+			)
+			layout.Layout(root, fakeSurface())
+
+			one := spec.FirstByKey(root, "one")
+			two := spec.FirstByKey(root, "two")
+			assert.Equal(one.Width(), 40)
+			assert.Equal(one.X(), 10)
+			assert.Equal(two.Width(), 40)
+			assert.Equal(two.X(), 60)
+		})
+
+		t.Run("Stack parent grows to include margins", func(t *testing.T) {
+			root := ctrl.Box(
+				opts.HAlign(spec.AlignLeft),
+				opts.VAlign(spec.AlignCenter),
+				opts.Child(ctrl.Box(opts.Key("one"), opts.Width(20), opts.Height(20), opts.Margin(5))),
+			)
+			layout.Layout(root, fakeSurface())
+
+			one := spec.FirstByKey(root, "one")
+			assert.Equal(root.Width(), 30)
+			assert.Equal(root.Height(), 30)
+			assert.Equal(one.X(), 5)
+			assert.Equal(one.Y(), 5)
+		})
+	})
+
+	t.Run("Percentage sizes", func(t *testing.T) {
+		t.Run("Flow", func(t *testing.T) {
+			root := ctrl.HBox(
+				opts.Width(200),
+				opts.Padding(10),
+				opts.Child(ctrl.Box(opts.Key("one"), opts.PrefWidth(25))),
+				opts.Child(ctrl.Box(opts.Key("two"), opts.FlexWidth(1))),
+			)
+			layout.Layout(root, fakeSurface())
+
+			assert.Equal(spec.FirstByKey(root, "one").Width(), 45)
+			assert.Equal(spec.FirstByKey(root, "two").Width(), 135)
+		})
+
+		t.Run("Stack", func(t *testing.T) {
+			root := ctrl.HBox(
+				opts.Height(100),
+				opts.Child(ctrl.Box(opts.Key("one"), opts.PrefHeight(50))),
+				opts.Child(ctrl.Box(opts.Key("two"), opts.PrefHeight(50), opts.FlexHeight(1))),
+			)
+			layout.Layout(root, fakeSurface())
+
+			assert.Equal(spec.FirstByKey(root, "one").Height(), 50)
+			// Flex takes precedence.
+			assert.Equal(spec.FirstByKey(root, "two").Height(), 100)
+		})
+	})
+
+	t.Run("Aspect ratio", func(t *testing.T) {
+		root := ctrl.VBox(
+			opts.Width(200),
+			opts.Child(ctrl.Box(opts.Key("one"), opts.FlexWidth(1), opts.AspectRatio(2))),
+			opts.Child(ctrl.Box(opts.Key("two"), opts.Height(30), opts.AspectRatio(2))),
+			opts.Child(ctrl.Box(opts.Key("three"), opts.Width(40), opts.PrefWidth(50), opts.AspectRatio(0.5))),
+		)
+		layout.Layout(root, fakeSurface())
+
+		one := spec.FirstByKey(root, "one")
+		assert.Equal(one.Width(), 200)
+		assert.Equal(one.Height(), 100)
+
+		// Width is derived from height when there is no width.
+		two := spec.FirstByKey(root, "two")
+		assert.Equal(two.Width(), 60)
+		assert.Equal(two.Y(), 100)
+
+		// Height follows a percentage width.
+		three := spec.FirstByKey(root, "three")
+		assert.Equal(three.Width(), 100)
+		assert.Equal(three.Height(), 200)
+	})
+
+	t.Run("Justify", func(t *testing.T) {
+		var positions = func(justify spec.JustifyValue) [2]float64 {
+			root := ctrl.HBox(
+				opts.Width(100),
+				opts.Justify(justify),
+				opts.Child(ctrl.Box(opts.Width(20), opts.Height(10))),
+				opts.Child(ctrl.Box(opts.Width(20), opts.Height(10))),
+			)
+			layout.Layout(root, fakeSurface())
+			assert.Equal(root.ChildrenWidth(), 40)
+			return [2]float64{root.ChildAt(0).X(), root.ChildAt(1).X()}
+		}
+
+		assert.Equal(positions(spec.JustifyStart), [2]float64{0, 20})
+		assert.Equal(positions(spec.JustifyEnd), [2]float64{60, 80})
+		assert.Equal(positions(spec.JustifyCenter), [2]float64{30, 50})
+		assert.Equal(positions(spec.JustifySpaceBetween), [2]float64{0, 80})
+		assert.Equal(positions(spec.JustifySpaceAround), [2]float64{15, 65})
+		assert.Equal(positions(spec.JustifySpaceEvenly), [2]float64{20, 60})
+	})
+
+	t.Run("Justify ignores overflowing children", func(t *testing.T) {
+		root := ctrl.VBox(
+			opts.Height(30),
+			opts.Padding(5),
+			opts.Gutter(5),
+			opts.Justify(spec.JustifyEnd),
+			opts.Child(ctrl.Box(opts.Key("one"), opts.Height(20))),
+			opts.Child(ctrl.Box(opts.Key("two"), opts.Height(20))),
+		)
+		layout.Layout(root, fakeSurface())
+		assert.Equal(spec.FirstByKey(root, "one").Y(), 5)
+		assert.Equal(spec.FirstByKey(root, "two").Y(), 30)
+		assert.Equal(root.Height(), 55)
+	})
+
+	t.Run("AlignSelf", func(t *testing.T) {
+		root := ctrl.HBox(
+			opts.Width(100),
+			opts.Height(50),
+			opts.VAlign(spec.AlignTop),
+			opts.Child(ctrl.Box(opts.Key("one"), opts.Width(10), opts.Height(10))),
+			opts.Child(ctrl.Box(opts.Key("two"), opts.Width(10), opts.Height(10), opts.AlignSelf(spec.AlignBottom))),
+			opts.Child(ctrl.Box(opts.Key("three"), opts.Width(10), opts.Height(10), opts.AlignSelf(spec.AlignCenter))),
+			// Horizontal alignments do not apply to the vertical axis.
+			opts.Child(ctrl.Box(opts.Key("four"), opts.Width(10), opts.Height(10), opts.AlignSelf(spec.AlignRight))),
+		)
+		layout.Layout(root, fakeSurface())
+
+		assert.Equal(spec.FirstByKey(root, "one").Y(), 0)
+		assert.Equal(spec.FirstByKey(root, "two").Y(), 40)
+		assert.Equal(spec.FirstByKey(root, "three").Y(), 20)
+		assert.Equal(spec.FirstByKey(root, "four").Y(), 0)
+		assert.Equal(spec.FirstByKey(root, "four").X(), 30)
+	})
+
+	t.Run("AlignSelf in Stack", func(t *testing.T) {
+		root := ctrl.Box(
+			opts.Width(100),
+			opts.Height(50),
+			opts.HAlign(spec.AlignLeft),
+			opts.VAlign(spec.AlignTop),
+			opts.Child(ctrl.Box(opts.Key("one"), opts.Width(10), opts.Height(10), opts.AlignSelf(spec.AlignRight))),
+		)
+		layout.Layout(root, fakeSurface())
+
+		one := spec.FirstByKey(root, "one")
+		assert.Equal(one.X(), 90)
+		assert.Equal(one.Y(), 0)
+	})
+
+	t.Run("Visibility", func(t *testing.T) {
+		var create = func(visibility spec.VisibilityValue) spec.ReadWriter {
+			return layout.Layout(ctrl.VBox(
+				opts.HAlign(spec.AlignLeft),
+				opts.Child(ctrl.Box(opts.Key("one"), opts.Width(10), opts.Height(10), opts.Visibility(visibility))),
+				opts.Child(ctrl.Box(opts.Key("two"), opts.Width(10), opts.Height(10))),
+			), fakeSurface())
+		}
+
+		t.Run("Hidden keeps its place", func(t *testing.T) {
+			root := create(spec.VisibilityHidden)
+			assert.Equal(spec.FirstByKey(root, "two").Y(), 10)
+			assert.Equal(root.Height(), 20)
+		})
+
+		t.Run("Collapsed is removed", func(t *testing.T) {
+			root := create(spec.VisibilityCollapsed)
+			assert.Equal(spec.FirstByKey(root, "two").Y(), 0)
+			assert.Equal(root.Height(), 10)
+		})
+	})
+}
+
+func TestIncrementalLayout(t *testing.T) {
+	var createLabels = func() spec.ReadWriter {
+		return ctrl.HBox(
+			opts.Child(ctrl.Label(opts.Key("one"), opts.FontSize(10), opts.Text("one"))),
+			opts.Child(ctrl.Label(opts.Key("two"), opts.FontSize(10), opts.Text("two"))),
+			opts.Child(ctrl.Label(opts.Key("three"), opts.FontSize(10), opts.Text("three"))),
+		)
+	}
+
+	var measured = func(s *surface.Fake) []string {
+		result := []string{}
+		for _, cmd := range s.GetCommands() {
+			if cmd.Name == "Text" {
+				result = append(result, cmd.Args[2].(string))
+			}
+		}
+		return result
+	}
+
+	t.Run("Clears dirty nodes", func(t *testing.T) {
+		root := layout.Layout(createLabels(), surface.NewSurface())
+		assert.False(root.IsLayoutDirty())
+		assert.False(spec.FirstByKey(root, "two").IsLayoutDirty())
+	})
+
+	t.Run("Skips clean trees", func(t *testing.T) {
+		root := layout.Layout(createLabels(), surface.NewSurface())
+		s := surface.NewSurface()
+		layout.Layout(root, s)
+		assert.Equal(len(measured(s)), 0)
+	})
+
+	t.Run("Only measures dirty nodes", func(t *testing.T) {
+		root := layout.Layout(createLabels(), surface.NewSurface())
+		assert.Equal(spec.FirstByKey(root, "two").X(), 12)
+
+		spec.FirstByKey(root, "one").SetText("first")
+		s := surface.NewSurface()
+		layout.Layout(root, s)
+
+		texts := measured(s)
+		assert.Equal(len(texts), 1)
+		assert.Equal(texts[0], "first")
+		// Clean siblings are still moved.
+		assert.Equal(spec.FirstByKey(root, "one").Width(), 21)
+		assert.Equal(spec.FirstByKey(root, "two").X(), 21)
+		assert.Equal(root.Width(), 54)
+	})
+
+	t.Run("Does not lay out parents of children that keep their size", func(t *testing.T) {
+		root := layout.Layout(ctrl.HBox(
+			opts.Child(ctrl.HBox(opts.Key("box"), opts.Width(50), opts.Height(20),
+				opts.Child(ctrl.Label(opts.Key("one"), opts.Text("one"))),
+			)),
+			opts.Child(ctrl.Label(opts.Key("two"), opts.Text("two"))),
+		), surface.NewSurface())
+		assert.Equal(spec.FirstByKey(root, "two").X(), 50)
+
+		// Moved by hand to detect a layout of the root.
+		spec.FirstByKey(root, "two").SetX(0)
+		spec.FirstByKey(root, "one").SetText("first")
+		layout.Layout(root, surface.NewSurface())
+		assert.Equal(spec.FirstByKey(root, "one").Width(), 50)
+		assert.Equal(spec.FirstByKey(root, "two").X(), 0)
+		assert.False(root.IsLayoutDirty())
+	})
+
+	t.Run("Lays out parents of children that were resized", func(t *testing.T) {
+		root := layout.Layout(ctrl.HBox(
+			opts.Child(ctrl.HBox(opts.Key("row"),
+				opts.Child(ctrl.Label(opts.Key("one"), opts.Text("one"))),
+			)),
+			opts.Child(ctrl.Label(opts.Key("two"), opts.Text("two"))),
+		), surface.NewSurface())
+		assert.Equal(spec.FirstByKey(root, "two").X(), 30)
+
+		spec.FirstByKey(root, "one").SetText("first")
+		layout.Layout(root, surface.NewSurface())
+		assert.Equal(spec.FirstByKey(root, "row").Width(), 50)
+		assert.Equal(spec.FirstByKey(root, "two").X(), 50)
+		assert.Equal(root.Width(), 80)
+	})
+
+	t.Run("Relays out clean children that were resized", func(t *testing.T) {
+		root := layout.Layout(ctrl.VBox(
+			opts.Width(100),
+			opts.Child(ctrl.HBox(opts.Key("row"), opts.FlexWidth(1),
+				opts.Child(fakes.Fake(opts.Key("fill"), opts.FlexWidth(1))),
+			)),
+		), surface.NewSurface())
+		assert.Equal(spec.FirstByKey(root, "fill").Width(), 100)
+
+		root.SetWidth(150)
+		layout.Layout(root, surface.NewSurface())
+		assert.Equal(spec.FirstByKey(root, "row").Width(), 150)
+		assert.Equal(spec.FirstByKey(root, "fill").Width(), 150)
+	})
+
+	t.Run("Measures again when an inherited font changes", func(t *testing.T) {
+		root := layout.Layout(ctrl.HBox(
+			opts.FontSize(10),
+			opts.Child(ctrl.Label(opts.Key("label"), opts.Text("hello"))),
+		), surface.NewSurface())
+		assert.Equal(spec.FirstByKey(root, "label").Width(), 21)
+
+		root.SetFontSize(40)
+		layout.Layout(root, surface.NewSurface())
+		assert.Equal(spec.FirstByKey(root, "label").Width(), 84)
+	})
+
+	t.Run("Relays out clean children that were shrunk", func(t *testing.T) {
+		root := layout.Layout(ctrl.VBox(
+			opts.Width(400),
+			opts.Height(300),
+			opts.Child(fakes.Fake(opts.Key("fill"), opts.FlexWidth(1), opts.FlexHeight(1))),
+		), surface.NewSurface())
+		assert.Equal(spec.FirstByKey(root, "fill").Width(), 400)
+
+		root.SetWidth(100)
+		root.SetHeight(50)
+		layout.Layout(root, surface.NewSurface())
+		assert.Equal(root.Width(), 100)
+		assert.Equal(root.Height(), 50)
+		assert.Equal(spec.FirstByKey(root, "fill").Width(), 100)
+		assert.Equal(spec.FirstByKey(root, "fill").Height(), 50)
+	})
+}
+
+// createLargeTree returns a tree with 10,000 nodes, in 100 rows of 99 boxes.
+func createLargeTree() spec.ReadWriter {
+	rows := make([]spec.ReadWriter, 100)
+	for row := range rows {
+		boxes := make([]spec.ReadWriter, 99)
+		for index := range boxes {
+			boxes[index] = ctrl.Box(opts.Width(10), opts.Height(10), opts.Padding(1))
+		}
+		rows[row] = ctrl.HBox(opts.FlexWidth(1), opts.Gutter(2), opts.Children(boxes))
+	}
+	return ctrl.VBox(opts.Width(1200), opts.Height(1200), opts.Children(rows))
+}
+
+func invalidateTree(r spec.ReadWriter) {
+	r.SetIsLayoutDirty(true)
+	for _, child := range r.Children() {
+		invalidateTree(child)
+	}
+}
+
+func BenchmarkLayout(b *testing.B) {
+	s := surface.NewSurface()
+
+	b.Run("Full", func(b *testing.B) {
+		root := layout.Layout(createLargeTree(), s)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			invalidateTree(root)
+			b.StartTimer()
+			layout.Layout(root, s)
+		}
+	})
+
+	b.Run("Incremental", func(b *testing.B) {
+		root := layout.Layout(createLargeTree(), s)
+		leaf := root.ChildAt(50).ChildAt(50)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			leaf.SetWidth(float64(10 + i%2))
+			layout.Layout(root, s)
+		}
+	})
+
+	b.Run("Unchanged", func(b *testing.B) {
+		root := layout.Layout(createLargeTree(), s)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			layout.Layout(root, s)
+		}
+	})
 }
@@ -0,0 +1,67 @@
+package layout
+
+import "github.com/waybeams/waybeams/pkg/spec"
+
+// Handler lays out the children of the provided Spec on the axis of the
+// provided Delegate and returns the size of those children on that axis.
+// Handlers are called once per axis, first horizontally, then vertically.
+type Handler func(delegate Delegate, r spec.ReadWriter) (childrenSize float64)
+
+var handlers = make(map[spec.LayoutTypeValue]Handler)
+
+func init() {
+	Register(spec.NoLayoutType, None)
+	Register(spec.StackLayoutType, StackOnAxis)
+	Register(spec.HorizontalFlowLayoutType, AxisHandler(FlowOnAxis, StackOnAxis))
+	Register(spec.VerticalFlowLayoutType, AxisHandler(StackOnAxis, FlowOnAxis))
+	Register(spec.RowLayoutType, RowOnAxis)
+	Register(spec.GridLayoutType, GridOnAxis)
+	Register(spec.ConstraintLayoutType, ConstraintOnAxis)
+	Register(spec.DockLayoutType, DockOnAxis)
+	Register(spec.ScrollLayoutType, ScrollOnAxis)
+}
+
+// Register associates a Handler with the provided LayoutTypeValue, replacing
+// any Handler that was already registered with it. Specs select the Handler
+// with opts.LayoutType.
+func Register(layoutType spec.LayoutTypeValue, handler Handler) {
+	handlers[layoutType] = handler
+}
+
+// HandlerFor returns the Handler that was registered with the provided
+// LayoutTypeValue.
+func HandlerFor(layoutType spec.LayoutTypeValue) (Handler, bool) {
+	handler, ok := handlers[layoutType]
+	return handler, ok
+}
+
+// AxisHandler creates a Handler that calls the horizontal Handler on the
+// horizontal axis and the vertical Handler on the vertical axis.
+func AxisHandler(horizontal, vertical Handler) Handler {
+	return func(delegate Delegate, r spec.ReadWriter) float64 {
+		if delegate.Axis() == spec.LayoutHorizontal {
+			return horizontal(delegate, r)
+		}
+		return vertical(delegate, r)
+	}
+}
+
+// layoutSpec calls the Handler registered for the LayoutType of the provided
+// Spec on the axis of the provided Delegate. Specs that have not changed
+// since they were last laid out keep their previous layout.
+func layoutSpec(delegate Delegate, r spec.ReadWriter) float64 {
+	if !r.IsLayoutDirty() {
+		if r.ChildCount() == 0 {
+			return delegate.Size(r)
+		}
+		return delegate.ChildrenSize(r)
+	}
+	handler, ok := HandlerFor(r.LayoutType())
+	if !ok {
+		panic("ERROR: Requested LayoutTypeValue (" + string(r.LayoutType()) + ") is not supported")
+	}
+	// The size of the children from the previous layout would otherwise keep
+	// this Spec from getting smaller (see spec.Spec.Width).
+	delegate.SetChildrenSize(r, 0)
+	return handler(delegate, r)
+}
@@ -0,0 +1,82 @@
+package layout_test
+
+import (
+	"testing"
+
+	"github.com/waybeams/assert"
+	surface "github.com/waybeams/waybeams/pkg/env/fake"
+	"github.com/waybeams/waybeams/pkg/fakes"
+	"github.com/waybeams/waybeams/pkg/layout"
+	"github.com/waybeams/waybeams/pkg/opts"
+	"github.com/waybeams/waybeams/pkg/spec"
+)
+
+const diagonalLayoutType spec.LayoutTypeValue = "Diagonal"
+
+// diagonal places each child after the previous one on both axes.
+func diagonal(delegate layout.Delegate, r spec.ReadWriter) float64 {
+	position := delegate.PaddingFirst(r)
+	for _, child := range r.Children() {
+		delegate.SetPosition(child, position)
+		position += delegate.Size(child)
+	}
+	return position - delegate.PaddingFirst(r)
+}
+
+func TestRegistry(t *testing.T) {
+	layout.Register(diagonalLayoutType, diagonal)
+
+	t.Run("Built in layouts are registered", func(t *testing.T) {
+		types := []spec.LayoutTypeValue{
+			spec.NoLayoutType,
+			spec.StackLayoutType,
+			spec.HorizontalFlowLayoutType,
+			spec.VerticalFlowLayoutType,
+			spec.RowLayoutType,
+			spec.GridLayoutType,
+			spec.ConstraintLayoutType,
+			spec.DockLayoutType,
+			spec.ScrollLayoutType,
+		}
+		for _, layoutType := range types {
+			_, ok := layout.HandlerFor(layoutType)
+			assert.True(ok, string(layoutType))
+		}
+	})
+
+	t.Run("Registered layouts are selected by LayoutType", func(t *testing.T) {
+		root := layout.Layout(fakes.Fake(
+			opts.LayoutType(diagonalLayoutType),
+			opts.Padding(5),
+			opts.Child(fakes.Fake(opts.Width(10), opts.Height(20))),
+			opts.Child(fakes.Fake(opts.Width(30), opts.Height(40))),
+		), surface.NewSurface())
+
+		assert.Equal(root.ChildAt(1).X(), 15)
+		assert.Equal(root.ChildAt(1).Y(), 25)
+		assert.Equal(root.ChildrenWidth(), 40)
+		assert.Equal(root.ChildrenHeight(), 60)
+		assert.Equal(root.Width(), 50)
+	})
+
+	t.Run("AxisHandler", func(t *testing.T) {
+		layout.Register("HorizontalDiagonal", layout.AxisHandler(diagonal, layout.StackOnAxis))
+		root := layout.Layout(fakes.Fake(
+			opts.LayoutType("HorizontalDiagonal"),
+			opts.Child(fakes.Fake(opts.Width(10), opts.Height(20))),
+			opts.Child(fakes.Fake(opts.Width(30), opts.Height(40))),
+		), surface.NewSurface())
+
+		assert.Equal(root.ChildAt(1).X(), 10)
+		assert.Equal(root.ChildAt(1).Y(), 0)
+	})
+
+	t.Run("Unknown LayoutType panics", func(t *testing.T) {
+		assert.Panic("Diagonal2", func() {
+			layout.Layout(fakes.Fake(
+				opts.LayoutType("Diagonal2"),
+				opts.Child(fakes.Fake()),
+			), surface.NewSurface())
+		})
+	})
+}
@@ -0,0 +1,111 @@
+package layout
+
+import (
+	"math"
+
+	"github.com/waybeams/waybeams/pkg/spec"
+)
+
+// RowOnAxis performs a wrapping Row layout on the provided delegate axis.
+//
+// Children flow horizontally and wrap onto a new row whenever the next child
+// would exceed the content width of the container. Gutter is applied between
+// children in a row and between rows. HAlign positions each row within the
+// content width and VAlign positions each child within the height of its row.
+// Containers without a width will not wrap.
+func RowOnAxis(delegate Delegate, d spec.ReadWriter) (childrenSize float64) {
+	if d.ChildCount() == 0 {
+		return delegate.Size(d)
+	}
+
+	for _, child := range d.Children() {
+		delegate.LayoutSpec(child)
+	}
+
+	if delegate.Axis() == spec.LayoutHorizontal {
+		childrenSize = rowPositionChildrenHorizontally(d)
+	} else {
+		childrenSize = rowPositionChildrenVertically(d)
+	}
+	delegate.SetChildrenSize(d, childrenSize)
+	return childrenSize
+}
+
+// rowGetRows splits the layoutable children of the provided Spec into rows
+// that fit within its content width.
+func rowGetRows(d spec.ReadWriter) [][]spec.ReadWriter {
+	available := d.Width() - d.HorizontalPadding()
+	gutter := d.Gutter()
+	rows := [][]spec.ReadWriter{}
+	row := []spec.ReadWriter{}
+	rowWidth := 0.0
+
+	for _, child := range getLayoutableChildren(d) {
+		width := child.Width()
+		if len(row) > 0 && available > 0 && rowWidth+gutter+width > available {
+			rows = append(rows, row)
+			row = []spec.ReadWriter{}
+			rowWidth = 0
+		}
+		if len(row) > 0 {
+			rowWidth += gutter
+		}
+		row = append(row, child)
+		rowWidth += width
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func rowGetWidth(row []spec.ReadWriter, gutter float64) float64 {
+	width := gutter * float64(len(row)-1)
+	for _, child := range row {
+		width += child.Width()
+	}
+	return width
+}
+
+func rowGetHeight(row []spec.ReadWriter) float64 {
+	height := 0.0
+	for _, child := range row {
+		height = math.Max(height, child.Height())
+	}
+	return height
+}
+
+func rowPositionChildrenHorizontally(d spec.ReadWriter) (childrenSize float64) {
+	gutter := d.Gutter()
+	rows := rowGetRows(d)
+	for _, row := range rows {
+		childrenSize = math.Max(childrenSize, rowGetWidth(row, gutter))
+	}
+
+	space := math.Max(childrenSize, d.Width()-d.HorizontalPadding())
+	for _, row := range rows {
+		position := d.PaddingLeft() + getAlignOffset(d.HAlign(), space, rowGetWidth(row, gutter))
+		for _, child := range row {
+			child.SetX(position)
+			position += child.Width() + gutter
+		}
+	}
+	return childrenSize
+}
+
+func rowPositionChildrenVertically(d spec.ReadWriter) (childrenSize float64) {
+	gutter := d.Gutter()
+	position := d.PaddingTop()
+	rows := rowGetRows(d)
+	if len(rows) == 0 {
+		return 0
+	}
+	for _, row := range rows {
+		rowHeight := rowGetHeight(row)
+		for _, child := range row {
+			child.SetY(position + getAlignOffset(d.VAlign(), rowHeight, child.Height()))
+		}
+		position += rowHeight + gutter
+	}
+	return position - gutter - d.PaddingTop()
+}
@@ -0,0 +1,95 @@
+package layout_test
+
+import (
+	"testing"
+
+	"github.com/waybeams/assert"
+	"github.com/waybeams/waybeams/pkg/ctrl"
+	surface "github.com/waybeams/waybeams/pkg/env/fake"
+	"github.com/waybeams/waybeams/pkg/fakes"
+	"github.com/waybeams/waybeams/pkg/layout"
+	"github.com/waybeams/waybeams/pkg/opts"
+	"github.com/waybeams/waybeams/pkg/spec"
+)
+
+func TestRowLayout(t *testing.T) {
+	var createRow = func(options ...spec.Option) spec.ReadWriter {
+		defaults := []spec.Option{
+			opts.Key("row"),
+			opts.Width(100),
+			opts.Padding(5),
+			opts.Gutter(10),
+			opts.HAlign(spec.AlignLeft),
+			opts.VAlign(spec.AlignTop),
+			opts.Child(fakes.Fake(opts.Key("one"), opts.Width(40), opts.Height(10))),
+			opts.Child(fakes.Fake(opts.Key("two"), opts.Width(40), opts.Height(20))),
+			opts.Child(fakes.Fake(opts.Key("three"), opts.Width(60), opts.Height(30))),
+			opts.Child(fakes.Fake(opts.Key("four"), opts.Width(20), opts.Height(10))),
+		}
+		return ctrl.Row(append(defaults, options...)...)
+	}
+
+	t.Run("Wraps children onto rows", func(t *testing.T) {
+		root := layout.Layout(createRow(), surface.NewSurface())
+
+		one := spec.FirstByKey(root, "one")
+		two := spec.FirstByKey(root, "two")
+		three := spec.FirstByKey(root, "three")
+		four := spec.FirstByKey(root, "four")
+
+		assert.Equal(one.X(), 5)
+		assert.Equal(one.Y(), 5)
+		assert.Equal(two.X(), 55)
+		assert.Equal(two.Y(), 5)
+		assert.Equal(three.X(), 5)
+		assert.Equal(three.Y(), 35)
+		assert.Equal(four.X(), 75)
+		assert.Equal(four.Y(), 35)
+
+		assert.Equal(root.ChildrenWidth(), 90)
+		assert.Equal(root.ChildrenHeight(), 60)
+		assert.Equal(root.Width(), 100)
+		assert.Equal(root.Height(), 70)
+	})
+
+	t.Run("Does not wrap without a width", func(t *testing.T) {
+		root := layout.Layout(createRow(opts.Width(0)), surface.NewSurface())
+		assert.Equal(spec.FirstByKey(root, "four").X(), 5+40+10+40+10+60+10)
+		assert.Equal(spec.FirstByKey(root, "four").Y(), 5)
+		assert.Equal(root.ChildrenWidth(), 190)
+		assert.Equal(root.ChildrenHeight(), 30)
+	})
+
+	t.Run("HAlign center", func(t *testing.T) {
+		root := layout.Layout(createRow(opts.HAlign(spec.AlignCenter)), surface.NewSurface())
+		// Second row is 90px wide in 90px of space.
+		assert.Equal(spec.FirstByKey(root, "three").X(), 5)
+		assert.Equal(spec.FirstByKey(root, "one").X(), 5)
+	})
+
+	t.Run("HAlign right", func(t *testing.T) {
+		root := layout.Layout(createRow(opts.Width(150), opts.HAlign(spec.AlignRight)), surface.NewSurface())
+		// First row holds one, two and three (160px wide) which exceeds 140.
+		assert.Equal(spec.FirstByKey(root, "two").X(), 145-40)
+		assert.Equal(spec.FirstByKey(root, "one").X(), 145-40-10-40)
+		assert.Equal(spec.FirstByKey(root, "four").X(), 145-20)
+	})
+
+	t.Run("VAlign positions children within the row", func(t *testing.T) {
+		root := layout.Layout(createRow(opts.VAlign(spec.AlignBottom)), surface.NewSurface())
+		assert.Equal(spec.FirstByKey(root, "one").Y(), 15)
+		assert.Equal(spec.FirstByKey(root, "two").Y(), 5)
+		assert.Equal(spec.FirstByKey(root, "four").Y(), 55)
+
+		root = layout.Layout(createRow(opts.VAlign(spec.AlignCenter)), surface.NewSurface())
+		assert.Equal(spec.FirstByKey(root, "one").Y(), 10)
+	})
+
+	t.Run("Excluded children are not positioned", func(t *testing.T) {
+		root := layout.Layout(createRow(
+			opts.Child(fakes.Fake(opts.Key("excluded"), opts.Width(500), opts.ExcludeFromLayout(true))),
+		), surface.NewSurface())
+		assert.Equal(spec.FirstByKey(root, "excluded").X(), 0)
+		assert.Equal(root.ChildrenHeight(), 60)
+	})
+}
@@ -0,0 +1,62 @@
+package layout
+
+import (
+	"math"
+
+	"github.com/waybeams/waybeams/pkg/spec"
+)
+
+// ScrollOnAxis performs a Stack layout on the axes that the provided Spec
+// does not scroll (see spec.Scroller). On the axes that it scrolls, children
+// are not limited to the space inside of the padding, and are moved by the
+// ScrollX or ScrollY of the Spec, which is kept within the size of the
+// children. A scrolling Spec does not grow to fit its children.
+func ScrollOnAxis(delegate Delegate, d spec.ReadWriter) (childrenSize float64) {
+	scroller, isScroller := d.(spec.Scroller)
+	if isScroller && !scroller.IsScrollable(delegate.Axis()) {
+		childrenSize = StackOnAxis(delegate, d)
+		scroller.SetScrollSize(delegate.Axis(), childrenSize)
+		return childrenSize
+	}
+
+	availablePixels := getAvailablePixels(delegate, d)
+	for _, child := range getLayoutableChildren(d) {
+		// Flexible children fill the space that is shown, and may still grow
+		// beyond it to fit their own children.
+		if delegate.IsFlexible(child) {
+			delegate.SetSize(child, availablePixels-delegate.Margin(child))
+		} else {
+			scalePreferredChild(delegate, d, child)
+		}
+	}
+
+	scrollSize := layoutStackChildren(d, delegate)
+	scroll := math.Max(0, math.Min(scrollGetPosition(delegate, d), scrollSize-availablePixels))
+	scrollSetPosition(delegate, d, scroll)
+	for _, child := range getLayoutableChildren(d) {
+		delegate.SetPosition(child, delegate.PaddingFirst(d)+delegate.MarginFirst(child)-scroll)
+	}
+
+	if isScroller {
+		scroller.SetScrollSize(delegate.Axis(), scrollSize)
+	}
+	delegate.SetChildrenSize(d, 0)
+	return 0
+}
+
+// scrollGetPosition returns the ScrollX or ScrollY of the provided Spec,
+// depending on the axis of the provided delegate.
+func scrollGetPosition(delegate Delegate, d spec.Reader) float64 {
+	if delegate.Axis() == spec.LayoutHorizontal {
+		return d.ScrollX()
+	}
+	return d.ScrollY()
+}
+
+func scrollSetPosition(delegate Delegate, d spec.Writer, scroll float64) {
+	if delegate.Axis() == spec.LayoutHorizontal {
+		d.SetScrollX(scroll)
+	} else {
+		d.SetScrollY(scroll)
+	}
+}
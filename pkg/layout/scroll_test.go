@@ -0,0 +1,58 @@
+package layout_test
+
+import (
+	"testing"
+
+	"github.com/waybeams/assert"
+	"github.com/waybeams/waybeams/pkg/ctrl"
+	surface "github.com/waybeams/waybeams/pkg/env/fake"
+	"github.com/waybeams/waybeams/pkg/fakes"
+	"github.com/waybeams/waybeams/pkg/layout"
+	"github.com/waybeams/waybeams/pkg/opts"
+	"github.com/waybeams/waybeams/pkg/spec"
+)
+
+func TestScrollLayout(t *testing.T) {
+	var createScroller = func(options ...spec.Option) spec.ReadWriter {
+		defaults := []spec.Option{
+			opts.LayoutType(spec.ScrollLayoutType),
+			opts.Width(100),
+			opts.Height(80),
+			opts.Padding(10),
+			opts.Child(ctrl.VBox(
+				opts.Key("content"),
+				opts.FlexWidth(1),
+				opts.Child(fakes.Fake(opts.Width(50), opts.Height(100))),
+				opts.Child(fakes.Fake(opts.Width(150), opts.Height(100))),
+			)),
+		}
+		return ctrl.Box(append(defaults, options...)...)
+	}
+
+	t.Run("Does not limit or grow to fit children", func(t *testing.T) {
+		root := layout.Layout(createScroller(), surface.NewSurface())
+		content := spec.FirstByKey(root, "content")
+		assert.Equal(root.Width(), 100)
+		assert.Equal(root.Height(), 80)
+		assert.Equal(content.X(), 10)
+		assert.Equal(content.Y(), 10)
+		assert.Equal(content.Width(), 150)
+		assert.Equal(content.Height(), 200)
+	})
+
+	t.Run("Moves children by the scroll", func(t *testing.T) {
+		root := layout.Layout(createScroller(opts.ScrollX(20), opts.ScrollY(30)), surface.NewSurface())
+		content := spec.FirstByKey(root, "content")
+		assert.Equal(content.X(), -10)
+		assert.Equal(content.Y(), -20)
+	})
+
+	t.Run("Keeps the scroll within the children", func(t *testing.T) {
+		root := layout.Layout(createScroller(opts.ScrollX(500), opts.ScrollY(-5)), surface.NewSurface())
+		content := spec.FirstByKey(root, "content")
+		assert.Equal(root.ScrollX(), 70)
+		assert.Equal(root.ScrollY(), 0)
+		assert.Equal(content.X(), -60)
+		assert.Equal(content.Y(), 10)
+	})
+}
@@ -1,8 +1,6 @@
 package layout
 
 import (
-	"strconv"
-
 	"github.com/waybeams/waybeams/pkg/spec"
 )
 
@@ -10,18 +8,7 @@ import (
 type verticalDelegate struct{}
 
 func (v *verticalDelegate) LayoutSpec(c spec.ReadWriter) (updatedSize float64) {
-	switch c.LayoutType() {
-	case spec.VerticalFlowLayoutType:
-		return FlowOnAxis(v, c)
-	case spec.HorizontalFlowLayoutType:
-		fallthrough
-	case spec.StackLayoutType:
-		return StackOnAxis(v, c)
-	case spec.NoLayoutType:
-		return None(v, c)
-	default:
-		panic("ERROR: Requested LayoutTypeValue (" + strconv.Itoa(int(c.LayoutType())) + ") is not supported:")
-	}
+	return layoutSpec(v, c)
 }
 
 func (v *verticalDelegate) ActualSize(d spec.Reader) float64 {
@@ -36,8 +23,17 @@ func (v *verticalDelegate) Axis() spec.LayoutAxis {
 	return spec.LayoutVertical
 }
 
+// AspectSize returns the height implied by the width and AspectRatio of
+// the provided Spec, or zero if it has no AspectRatio.
+func (v *verticalDelegate) AspectSize(d spec.Reader) float64 {
+	if d.AspectRatio() <= 0 {
+		return 0
+	}
+	return d.Width() / d.AspectRatio()
+}
+
 func (v *verticalDelegate) ChildrenSize(d spec.Reader) float64 {
-	return 0
+	return d.ChildrenHeight()
 }
 
 func (v *verticalDelegate) Flex(d spec.Reader) float64 {
@@ -52,6 +48,18 @@ func (v *verticalDelegate) IsFlexible(d spec.Reader) bool {
 	return d.FlexHeight() > 0.0
 }
 
+func (v *verticalDelegate) Margin(d spec.Reader) float64 {
+	return d.VerticalMargin()
+}
+
+func (v *verticalDelegate) MarginFirst(d spec.Reader) float64 {
+	return d.MarginTop()
+}
+
+func (v *verticalDelegate) MarginLast(d spec.Reader) float64 {
+	return d.MarginBottom()
+}
+
 func (v *verticalDelegate) MaxSize(d spec.Reader) float64 {
 	return d.MaxHeight()
 }
@@ -5,12 +5,89 @@ import (
 	. "github.com/waybeams/waybeams/pkg/spec"
 )
 
+// AlignSelf will override the alignment of the parent for the Spec.
+func AlignSelf(align Alignment) Option {
+	return func(r ReadWriter) {
+		r.SetAlignSelf(align)
+	}
+}
+
+// AnchorBottom will pin the bottom edge of the Spec to the same edge of a parent
+// Dock layout, at the provided offset.
+func AnchorBottom(offset float64) Option {
+	return func(r ReadWriter) {
+		r.SetAnchors(r.Anchors().With(EdgeBottom, offset))
+	}
+}
+
+// AnchorLeft will pin the left edge of the Spec to the same edge of a parent
+// Dock layout, at the provided offset.
+func AnchorLeft(offset float64) Option {
+	return func(r ReadWriter) {
+		r.SetAnchors(r.Anchors().With(EdgeLeft, offset))
+	}
+}
+
+// AnchorRight will pin the right edge of the Spec to the same edge of a parent
+// Dock layout, at the provided offset.
+func AnchorRight(offset float64) Option {
+	return func(r ReadWriter) {
+		r.SetAnchors(r.Anchors().With(EdgeRight, offset))
+	}
+}
+
+// AnchorTop will pin the top edge of the Spec to the same edge of a parent
+// Dock layout, at the provided offset.
+func AnchorTop(offset float64) Option {
+	return func(r ReadWriter) {
+		r.SetAnchors(r.Anchors().With(EdgeTop, offset))
+	}
+}
+
+// AspectRatio will set the ratio of width to height that layouts maintain
+// for the Spec.
+func AspectRatio(ratio float64) Option {
+	return func(r ReadWriter) {
+		r.SetAspectRatio(ratio)
+	}
+}
+
 func BgColor(color uint) Option {
 	return func(r ReadWriter) {
 		r.SetBgColor(color)
 	}
 }
 
+// ClipChildren will configure Spec.ClipChildren, which only draws children
+// within the bounds of their parent.
+func ClipChildren(value bool) Option {
+	return func(r ReadWriter) {
+		r.SetClipChildren(value)
+	}
+}
+
+// ColumnGutter will set the space between Grid columns.
+func ColumnGutter(value float64) Option {
+	return func(r ReadWriter) {
+		r.SetColumnGutter(value)
+	}
+}
+
+// Constraints will set the constraints that position the Spec within a
+// parent that uses the ConstraintLayoutType.
+func Constraints(constraints ...string) Option {
+	return func(r ReadWriter) {
+		r.SetConstraints(constraints...)
+	}
+}
+
+// Dock will attach the Spec to an edge of a parent Dock layout.
+func Dock(dock DockValue) Option {
+	return func(r ReadWriter) {
+		r.SetDock(dock)
+	}
+}
+
 // ExcludeFromLayout will configure Spec.ExcludeFromLayout.
 func ExcludeFromLayout(value bool) Option {
 	return func(r ReadWriter) {
@@ -50,6 +127,50 @@ func FontSize(size float64) Option {
 	}
 }
 
+// GridColumn will place the Spec in the provided one-based column of a
+// parent Grid.
+func GridColumn(column int) Option {
+	return func(r ReadWriter) {
+		r.SetGridColumn(column)
+	}
+}
+
+// GridColumns will set the column tracks of a Grid layout.
+func GridColumns(tracks ...GridTrack) Option {
+	return func(r ReadWriter) {
+		r.SetGridColumns(tracks)
+	}
+}
+
+// GridColumnSpan will set the number of columns the Spec covers in a parent
+// Grid.
+func GridColumnSpan(span int) Option {
+	return func(r ReadWriter) {
+		r.SetGridColumnSpan(span)
+	}
+}
+
+// GridRow will place the Spec in the provided one-based row of a parent Grid.
+func GridRow(row int) Option {
+	return func(r ReadWriter) {
+		r.SetGridRow(row)
+	}
+}
+
+// GridRows will set the row tracks of a Grid layout.
+func GridRows(tracks ...GridTrack) Option {
+	return func(r ReadWriter) {
+		r.SetGridRows(tracks)
+	}
+}
+
+// GridRowSpan will set the number of rows the Spec covers in a parent Grid.
+func GridRowSpan(span int) Option {
+	return func(r ReadWriter) {
+		r.SetGridRowSpan(span)
+	}
+}
+
 func Gutter(value float64) Option {
 	return func(r ReadWriter) {
 		r.SetGutter(value)
@@ -64,18 +185,50 @@ func IsDisabled(isDisabled bool) Option {
 	}
 }
 
+// IsDraggable will configure Spec.IsDraggable. Handlers of DragStarted can
+// provide the Data of the DragPayload.
+func IsDraggable(value bool) Option {
+	return func(r ReadWriter) {
+		r.SetIsDraggable(value)
+	}
+}
+
+// IsDropTarget will configure Spec.IsDropTarget, which receives DragEntered,
+// DragExited and Dropped.
+func IsDropTarget(value bool) Option {
+	return func(r ReadWriter) {
+		r.SetIsDropTarget(value)
+	}
+}
+
 func IsFocusable(value bool) Option {
 	return func(r ReadWriter) {
 		r.SetIsFocusable(value)
 	}
 }
 
+// IsFocusScope will configure Spec.IsFocusScope, which keeps Tab traversal
+// within a node like a modal dialog.
+func IsFocusScope(value bool) Option {
+	return func(r ReadWriter) {
+		r.SetIsFocusScope(value)
+	}
+}
+
 func IsMeasured(measured bool) Option {
 	return func(r ReadWriter) {
 		r.SetIsMeasured(measured)
 	}
 }
 
+// IsOverlay will configure Spec.IsOverlay, which draws a node (e.g., a popup
+// or tooltip) above the rest of the tree.
+func IsOverlay(value bool) Option {
+	return func(r ReadWriter) {
+		r.SetIsOverlay(value)
+	}
+}
+
 func IsText(value bool) Option {
 	return func(r ReadWriter) {
 		r.SetIsText(value)
@@ -102,6 +255,14 @@ func Height(value float64) Option {
 	}
 }
 
+// Justify will set how a Flow layout distributes the remaining space
+// between the children of the Spec.
+func Justify(justify JustifyValue) Option {
+	return func(r ReadWriter) {
+		r.SetJustify(justify)
+	}
+}
+
 func Key(value string) Option {
 	return func(r ReadWriter) {
 		r.SetKey(value)
@@ -115,6 +276,42 @@ func LayoutType(layoutType LayoutTypeValue) Option {
 	}
 }
 
+// Margin will set the space that layouts keep clear around all four sides
+// of the Spec.
+func Margin(value float64) Option {
+	return func(r ReadWriter) {
+		r.SetMargin(value)
+	}
+}
+
+// MarginBottom will set Spec.MarginBottom.
+func MarginBottom(value float64) Option {
+	return func(r ReadWriter) {
+		r.SetMarginBottom(value)
+	}
+}
+
+// MarginLeft will set Spec.MarginLeft.
+func MarginLeft(value float64) Option {
+	return func(r ReadWriter) {
+		r.SetMarginLeft(value)
+	}
+}
+
+// MarginRight will set Spec.MarginRight.
+func MarginRight(value float64) Option {
+	return func(r ReadWriter) {
+		r.SetMarginRight(value)
+	}
+}
+
+// MarginTop will set Spec.MarginTop.
+func MarginTop(value float64) Option {
+	return func(r ReadWriter) {
+		r.SetMarginTop(value)
+	}
+}
+
 // MaxHeight will set Spec.MaxHeight.
 func MaxHeight(value float64) Option {
 	return func(r ReadWriter) {
@@ -143,6 +340,14 @@ func MinWidth(value float64) Option {
 	}
 }
 
+// Neighbor will set the Key of the node that arrow keys move focus to in
+// the provided direction (see Spec.Neighbor).
+func Neighbor(direction FocusDirection, key string) Option {
+	return func(r ReadWriter) {
+		r.SetNeighbor(direction, key)
+	}
+}
+
 // Padding will set Spec.Padding, which will effectively set padding for
 // all four sides as well (bottom, top, left, right, horizontal and vertical).
 func Padding(value float64) Option {
@@ -179,14 +384,16 @@ func PaddingTop(value float64) Option {
 	}
 }
 
-// PrefHeight will set Spec.PrefHeight.
+// PrefHeight will set Spec.PrefHeight, as a percentage of the height inside
+// the padding of the parent.
 func PrefHeight(value float64) Option {
 	return func(r ReadWriter) {
 		r.SetPrefHeight(value)
 	}
 }
 
-// PrefWidth will set Spec.PrefWidth.
+// PrefWidth will set Spec.PrefWidth, as a percentage of the width inside
+// the padding of the parent.
 func PrefWidth(value float64) Option {
 	return func(r ReadWriter) {
 		r.SetPrefWidth(value)
@@ -201,6 +408,29 @@ func Size(width, height float64) Option {
 	}
 }
 
+// RowGutter will set the space between Grid rows.
+func RowGutter(value float64) Option {
+	return func(r ReadWriter) {
+		r.SetRowGutter(value)
+	}
+}
+
+// ScrollX will set the initial Spec.ScrollX of a Spec with a Scroll
+// LayoutType. Once rendered, the scroll is retained from the previous render.
+func ScrollX(value float64) Option {
+	return func(r ReadWriter) {
+		r.SetScrollX(value)
+	}
+}
+
+// ScrollY will set the initial Spec.ScrollY of a Spec with a Scroll
+// LayoutType. Once rendered, the scroll is retained from the previous render.
+func ScrollY(value float64) Option {
+	return func(r ReadWriter) {
+		r.SetScrollY(value)
+	}
+}
+
 func SpecName(name string) Option {
 	return func(r ReadWriter) {
 		r.SetSpecName(name)
@@ -219,6 +449,13 @@ func StrokeSize(size float64) Option {
 	}
 }
 
+// TabIndex will configure Spec.TabIndex.
+func TabIndex(value int) Option {
+	return func(r ReadWriter) {
+		r.SetTabIndex(value)
+	}
+}
+
 func Text(value string) Option {
 	return func(r ReadWriter) {
 		// TODO(lbayes): Sanitize text as user input values can be placed in here.
@@ -240,6 +477,14 @@ func View(view RenderHandler) Option {
 	}
 }
 
+// Visibility will configure Spec.Visibility as VisibilityVisible,
+// VisibilityHidden or VisibilityCollapsed.
+func Visibility(visibility VisibilityValue) Option {
+	return func(r ReadWriter) {
+		r.SetVisibility(visibility)
+	}
+}
+
 func Visible(visible bool) Option {
 	return func(r ReadWriter) {
 		r.SetVisible(visible)
@@ -267,6 +512,13 @@ func Y(pos float64) Option {
 	}
 }
 
+// ZIndex will set Spec.ZIndex.
+func ZIndex(index int) Option {
+	return func(r ReadWriter) {
+		r.SetZIndex(index)
+	}
+}
+
 //-------------------------------------------
 // Special Adapters
 //-------------------------------------------
@@ -148,6 +148,11 @@ func TestOptions(t *testing.T) {
 		assert.Equal(f.FontFace(), "abcd")
 	})
 
+	t.Run("AspectRatio", func(t *testing.T) {
+		f := fakes.Fake(opts.AspectRatio(1.5))
+		assert.Equal(f.AspectRatio(), 1.5)
+	})
+
 	t.Run("FontSize", func(t *testing.T) {
 		f := fakes.Fake(opts.FontSize(23))
 		assert.Equal(f.FontSize(), 23)
@@ -168,6 +173,16 @@ func TestOptions(t *testing.T) {
 		assert.Equal(f.IsMeasured(), true)
 	})
 
+	t.Run("Margin", func(t *testing.T) {
+		f := fakes.Fake(opts.Margin(10), opts.MarginLeft(5))
+		assert.Equal(f.MarginBottom(), 10)
+		assert.Equal(f.MarginLeft(), 5)
+		assert.Equal(f.MarginRight(), 10)
+		assert.Equal(f.MarginTop(), 10)
+		assert.Equal(f.HorizontalMargin(), 15)
+		assert.Equal(f.VerticalMargin(), 20)
+	})
+
 	t.Run("Padding", func(t *testing.T) {
 		f := fakes.Fake(opts.Padding(10))
 		assert.Equal(f.PaddingBottom(), 10)
@@ -0,0 +1,12 @@
+package scheduler
+
+// Start prepares the Scheduler like Listen, without entering the frame loop.
+func (s *Scheduler) Start() {
+	s.init()
+}
+
+// Frame runs a single frame of the Listen loop in the calling goroutine, so
+// that tests can inspect the Scheduler between frames without racing it.
+func (s *Scheduler) Frame() {
+	s.frameHandler(false)
+}
@@ -1,6 +1,8 @@
 package scheduler
 
 import (
+	"log"
+
 	"github.com/waybeams/waybeams/pkg/clock"
 	"github.com/waybeams/waybeams/pkg/events"
 	"github.com/waybeams/waybeams/pkg/layout"
@@ -9,18 +11,29 @@ import (
 
 const shouldPollEvents = true
 
+// maxBufferAge is the oldest frame that a spec.PartialWindow can begin with
+// and still only redraw what changed since then.
+const maxBufferAge = 3
+
 // Scheduler manages Specification lifecycle and rendering interactions with
 // the host environment.
 type Scheduler struct {
 	clock            clock.Clock
+	damage           *layout.Damage
+	errorHandler     func(err error)
 	factory          spec.Factory
 	isClosed         bool
+	lastDrawnHeight  float64
+	lastDrawnWidth   float64
+	lastRegions      []spec.BoundingBox
 	lastWindowHeight float64
 	lastWindowWidth  float64
+	reconciler       *spec.Reconciler
 	root             spec.ReadWriter
 	shouldRender     bool
 	shouldLayout     bool
 	shouldDraw       bool
+	stylesheet       *spec.Stylesheet
 	surface          spec.Surface
 	window           spec.Window
 }
@@ -38,8 +51,12 @@ func (s *Scheduler) renderSpecs() {
 
 		// Create a new Spec tree and store it.
 		root = s.factory()
-		s.root = root
 		root.On(events.Invalidated, s.specInvalidatedHandler)
+		if s.stylesheet != nil {
+			s.stylesheet.Apply(root)
+		}
+		// Carry runtime state (e.g., hover, focus) over from the previous tree.
+		s.root = s.reconciler.Reconcile(root)
 	}
 }
 
@@ -48,22 +65,71 @@ func (s *Scheduler) layoutSpecs() {
 		s.root.SetWidth(s.window.Width())
 		s.root.SetHeight(s.window.Height())
 
-		layout.Layout(s.root, s.surface)
+		for _, err := range layout.LayoutWithErrors(s.root, s.surface) {
+			s.errorHandler(err)
+		}
 	}
 }
 
-func (s *Scheduler) drawSpecs() {
-	if s.shouldRender || s.shouldLayout {
-		layout.Draw(s.root, s.surface)
+// damagedRegion returns the region of the window that must be redrawn for
+// the current tree, or an empty region if nothing has changed.
+func (s *Scheduler) damagedRegion() spec.BoundingBox {
+	region := s.damage.Update(s.root)
+	w, h := s.window.Width(), s.window.Height()
+	window := spec.BoundingBox{Width: w, Height: h}
+	if w != s.lastDrawnWidth || h != s.lastDrawnHeight {
+		// Windows that were just opened or resized are drawn entirely.
+		s.lastDrawnWidth = w
+		s.lastDrawnHeight = h
+		return window
 	}
+	return region.Intersect(window)
 }
 
-func (s *Scheduler) Listen() {
-	s.window.Init()
-	s.window.OnResize(s.windowResizedHandler)
+// drawSpecs draws the provided region of the current tree.
+func (s *Scheduler) drawSpecs(region spec.BoundingBox) {
+	s.lastRegions = append(s.lastRegions, region)
+	if len(s.lastRegions) > maxBufferAge {
+		s.lastRegions = s.lastRegions[1:]
+	}
 
-	s.surface.Init()
+	drawRegion, isPartial := s.partialRegion()
+	if isPartial {
+		s.window.(spec.PartialWindow).BeginPartialFrame(drawRegion)
+	} else {
+		s.window.BeginFrame()
+		drawRegion = spec.BoundingBox{Width: s.window.Width(), Height: s.window.Height()}
+	}
 
+	s.surface.BeginFrame()
+	s.damage.Draw(s.root, s.surface, drawRegion)
+	s.surface.EndFrame()
+
+	s.window.EndFrame()
+}
+
+// partialRegion returns the region that a partial frame must redraw, or
+// false if the window must be redrawn entirely.
+func (s *Scheduler) partialRegion() (spec.BoundingBox, bool) {
+	partial, ok := s.window.(spec.PartialWindow)
+	if !ok {
+		return spec.BoundingBox{}, false
+	}
+	age := partial.BufferAge()
+	if age < 1 || age > len(s.lastRegions) {
+		return spec.BoundingBox{}, false
+	}
+	// The window begins with the frame that was drawn age frames ago, so
+	// redraw what changed in each frame since then.
+	region := spec.BoundingBox{}
+	for _, previous := range s.lastRegions[len(s.lastRegions)-age:] {
+		region = region.Union(previous)
+	}
+	return region, true
+}
+
+func (s *Scheduler) Listen() {
+	s.init()
 	defer s.Close()
 
 	s.clock.OnFrame(func() bool {
@@ -71,29 +137,32 @@ func (s *Scheduler) Listen() {
 	}, s.window.FrameRate())
 }
 
+// init prepares the window and surface for the first frame.
+func (s *Scheduler) init() {
+	s.window.Init()
+	s.window.OnResize(s.windowResizedHandler)
+
+	s.surface.Init()
+}
+
 func (s *Scheduler) windowResizedHandler(e events.Event) {
 	s.shouldLayout = true
 }
 
 func (s *Scheduler) frameHandler(pollEvents bool) bool {
 	if s.shouldRender || s.shouldLayout {
-		// BeginFrame on the Window.
-		s.window.BeginFrame()
-
-		// BeginFrame on the Surface.
 		s.surface.SetWidth(s.window.Width())
 		s.surface.SetHeight(s.window.Height())
-		s.surface.BeginFrame()
 
 		// Render the Specs.
 		s.renderSpecs()
 		s.layoutSpecs()
-		s.drawSpecs()
-
-		// EndFrame on Surface and then Window.
-		s.surface.EndFrame()
 
-		s.window.EndFrame()
+		// Only begin a frame when something needs to be redrawn.
+		region := s.damagedRegion()
+		if !region.IsEmpty() {
+			s.drawSpecs(region)
+		}
 
 		s.shouldRender = false
 		s.shouldLayout = false
@@ -118,10 +187,33 @@ func (s *Scheduler) Root() spec.ReadWriter {
 	return s.root
 }
 
+// SetErrorHandler configures a function that is called with each error that
+// is reported while laying out a frame, like a layout.ConstraintError. These
+// errors are written to the standard logger by default.
+func (s *Scheduler) SetErrorHandler(handler func(err error)) {
+	s.errorHandler = handler
+}
+
+// SetStylesheet configures a Stylesheet that will be applied to each newly
+// rendered Spec tree, before it is laid out.
+func (s *Scheduler) SetStylesheet(stylesheet *spec.Stylesheet) {
+	s.stylesheet = stylesheet
+	s.shouldRender = true
+}
+
+func (s *Scheduler) Stylesheet() *spec.Stylesheet {
+	return s.stylesheet
+}
+
 func (s *Scheduler) Window() spec.Window {
 	return s.window
 }
 
+// logError writes the provided error to the standard logger.
+func logError(err error) {
+	log.Println("waybeams:", err)
+}
+
 func New(w spec.Window, s spec.Surface, f spec.Factory, c clock.Clock) *Scheduler {
 	return &Scheduler{
 		shouldRender: true,
@@ -130,5 +222,8 @@ func New(w spec.Window, s spec.Surface, f spec.Factory, c clock.Clock) *Schedule
 		surface:      s,
 		factory:      f,
 		clock:        c,
+		damage:       layout.NewDamage(),
+		errorHandler: logError,
+		reconciler:   spec.NewReconciler(),
 	}
 }
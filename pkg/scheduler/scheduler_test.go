@@ -7,6 +7,7 @@ import (
 	"github.com/waybeams/waybeams/pkg/clock"
 
 	"github.com/waybeams/waybeams/pkg/ctrl"
+	"github.com/waybeams/waybeams/pkg/opts"
 	"github.com/waybeams/waybeams/pkg/spec"
 
 	"github.com/waybeams/assert"
@@ -17,23 +18,169 @@ import (
 func TestScheduler(t *testing.T) {
 
 	t.Run("Surface", func(t *testing.T) {
-		factoryCalled := false
+		factoryCalled := make(chan bool, 1)
 		fakeWindow := fake.NewWindow()
 		fakeSurface := fake.NewSurface()
 		fakeAppFactory := func() spec.ReadWriter {
-			factoryCalled = true
+			select {
+			case factoryCalled <- true:
+			default:
+			}
 			return ctrl.VBox()
 		}
 		fakeClock := clock.NewFake()
 
 		b := scheduler.New(fakeWindow, fakeSurface, fakeAppFactory, fakeClock)
 
-		// Ensure we close the blocked goroutine.
+		listened := make(chan bool)
+		go func() {
+			b.Listen()
+			close(listened)
+		}()
+		// The first frame calls our factory.
+		assert.True(<-factoryCalled)
+
+		// Listen returns once the window should close.
+		fakeWindow.SetShouldClose(true)
+		for {
+			select {
+			case <-listened:
+				return
+			default:
+				fakeClock.Add(100 * time.Millisecond)
+			}
+		}
+	})
+
+	t.Run("Frame", func(t *testing.T) {
+		factoryCalled := false
+		fakeAppFactory := func() spec.ReadWriter {
+			factoryCalled = true
+			return ctrl.VBox()
+		}
+
+		b := scheduler.New(fake.NewWindow(), fake.NewSurface(), fakeAppFactory, clock.NewFake())
+
 		defer b.Close()
-		// Listen in a goroutine.
-		go b.Listen()
-		// Move time forward and ensure our factory was called.
-		fakeClock.Add(100 * time.Millisecond)
+		b.Start()
+		assert.False(factoryCalled, "before the first frame")
+		b.Frame()
 		assert.True(factoryCalled)
 	})
+	t.Run("Stylesheet", func(t *testing.T) {
+		fakeAppFactory := func() spec.ReadWriter {
+			return ctrl.VBox(opts.Child(ctrl.Button(opts.Key("one"))))
+		}
+		fakeClock := clock.NewFake()
+
+		b := scheduler.New(fake.NewWindow(), fake.NewSurface(), fakeAppFactory, fakeClock)
+		b.SetStylesheet(spec.NewStylesheet(
+			spec.Rule("VBox > Button", opts.FontSize(40), opts.Padding(12)),
+		))
+
+		defer b.Close()
+		b.Start()
+		b.Frame()
+
+		button := spec.FirstByKey(b.Root(), "one")
+		assert.Equal(button.FontSize(), 40)
+		assert.Equal(button.PaddingLeft(), 12)
+	})
+
+	t.Run("Reports layout errors", func(t *testing.T) {
+		fakeAppFactory := func() spec.ReadWriter {
+			return ctrl.Canvas(opts.Child(ctrl.Box(
+				opts.Key("one"),
+				opts.Constraints("width >= 200", "width <= 100"),
+			)))
+		}
+		b := scheduler.New(fake.NewWindow(), fake.NewSurface(), fakeAppFactory, clock.NewFake())
+		failures := []error{}
+		b.SetErrorHandler(func(err error) {
+			failures = append(failures, err)
+		})
+
+		defer b.Close()
+		b.Start()
+		b.Frame()
+
+		assert.Equal(len(failures), 1)
+		assert.Equal(failures[0].Error(), "/Canvas/one: width <= 100: unsatisfiable constraint")
+	})
+
+	var drawFrames = func(bufferAge int) (*fake.FakeWindow, *fake.Fake) {
+		color := uint(0xff0000ff)
+		fakeAppFactory := func() spec.ReadWriter {
+			return ctrl.VBox(
+				opts.Child(ctrl.Box(opts.Key("one"), opts.Width(100), opts.Height(50), opts.BgColor(color))),
+				opts.Child(ctrl.Box(opts.Key("two"), opts.Width(100), opts.Height(50))),
+			)
+		}
+		fakeWindow := fake.NewWindow()
+		fakeWindow.SetWidth(800)
+		fakeWindow.SetHeight(600)
+		fakeWindow.SetBufferAge(bufferAge)
+		fakeSurface := fake.NewSurface()
+
+		b := scheduler.New(fakeWindow, fakeSurface, fakeAppFactory, clock.NewFake())
+		defer b.Close()
+		b.Start()
+		b.Frame()
+
+		// Nothing has changed, so no frame is drawn.
+		b.Root().Invalidate()
+		b.Frame()
+
+		color = 0x00ff00ff
+		b.Root().Invalidate()
+		b.Frame()
+
+		color = 0x0000ffff
+		b.Root().Invalidate()
+		b.Frame()
+		return fakeWindow, fakeSurface
+	}
+
+	full := spec.BoundingBox{Width: 800, Height: 600}
+	changed := spec.BoundingBox{X: 699, Y: 0, Width: 101, Height: 51}
+
+	t.Run("Redraws damaged regions", func(t *testing.T) {
+		fakeWindow, fakeSurface := drawFrames(2)
+
+		regions := fakeWindow.FrameRegions()
+		assert.Equal(len(regions), 3)
+		assert.Equal(regions[0], full)
+		// The frame before the previous one is also redrawn, as the window
+		// begins with it.
+		assert.Equal(regions[1], full)
+		assert.Equal(regions[2], changed)
+
+		scissors := []fake.Command{}
+		for _, cmd := range fakeSurface.GetCommands() {
+			if cmd.Name == "Scissor" {
+				scissors = append(scissors, cmd)
+			}
+		}
+		assert.Equal(len(scissors), 3)
+		assert.Equal(scissors[2].Args[3], 51.0)
+	})
+
+	t.Run("Redraws damaged regions of the previous frame", func(t *testing.T) {
+		fakeWindow, _ := drawFrames(1)
+
+		regions := fakeWindow.FrameRegions()
+		assert.Equal(len(regions), 3)
+		assert.Equal(regions[0], full)
+		assert.Equal(regions[1], changed)
+		assert.Equal(regions[2], changed)
+	})
+
+	t.Run("Redraws entirely without a buffer age", func(t *testing.T) {
+		fakeWindow, _ := drawFrames(0)
+
+		regions := fakeWindow.FrameRegions()
+		assert.Equal(len(regions), 3)
+		assert.Equal(regions[1], full)
+		assert.Equal(regions[2], full)
+	})
 }
@@ -1,8 +1,59 @@
 package spec
 
+import "math"
+
 type BoundingBox struct {
 	X      float64
 	Y      float64
 	Width  float64
 	Height float64
 }
+
+// IsEmpty returns true if this box does not cover any area.
+func (b BoundingBox) IsEmpty() bool {
+	return b.Width <= 0 || b.Height <= 0
+}
+
+// Intersects returns true if this box overlaps the provided box.
+func (b BoundingBox) Intersects(other BoundingBox) bool {
+	if b.IsEmpty() || other.IsEmpty() {
+		return false
+	}
+	return b.X < other.X+other.Width && other.X < b.X+b.Width &&
+		b.Y < other.Y+other.Height && other.Y < b.Y+b.Height
+}
+
+// Intersect returns the area that this box and the provided box have in
+// common, which is empty if they do not overlap.
+func (b BoundingBox) Intersect(other BoundingBox) BoundingBox {
+	if !b.Intersects(other) {
+		return BoundingBox{}
+	}
+	x := math.Max(b.X, other.X)
+	y := math.Max(b.Y, other.Y)
+	return BoundingBox{
+		X:      x,
+		Y:      y,
+		Width:  math.Min(b.X+b.Width, other.X+other.Width) - x,
+		Height: math.Min(b.Y+b.Height, other.Y+other.Height) - y,
+	}
+}
+
+// Union returns the smallest box that contains both this box and the
+// provided box. Empty boxes are ignored.
+func (b BoundingBox) Union(other BoundingBox) BoundingBox {
+	if other.IsEmpty() {
+		return b
+	}
+	if b.IsEmpty() {
+		return other
+	}
+	x := math.Min(b.X, other.X)
+	y := math.Min(b.Y, other.Y)
+	return BoundingBox{
+		X:      x,
+		Y:      y,
+		Width:  math.Max(b.X+b.Width, other.X+other.Width) - x,
+		Height: math.Max(b.Y+b.Height, other.Y+other.Height) - y,
+	}
+}
@@ -0,0 +1,37 @@
+package spec_test
+
+import (
+	"testing"
+
+	"github.com/waybeams/assert"
+	"github.com/waybeams/waybeams/pkg/spec"
+)
+
+func TestBoundingBox(t *testing.T) {
+	t.Run("IsEmpty", func(t *testing.T) {
+		assert.True(spec.BoundingBox{}.IsEmpty())
+		assert.True(spec.BoundingBox{Width: 10}.IsEmpty())
+		assert.False(spec.BoundingBox{Width: 10, Height: 1}.IsEmpty())
+	})
+
+	t.Run("Intersects", func(t *testing.T) {
+		box := spec.BoundingBox{X: 10, Y: 10, Width: 20, Height: 20}
+		assert.True(box.Intersects(spec.BoundingBox{X: 25, Y: 25, Width: 10, Height: 10}))
+		assert.False(box.Intersects(spec.BoundingBox{X: 30, Y: 10, Width: 10, Height: 10}))
+		assert.False(box.Intersects(spec.BoundingBox{X: 15, Y: 15}))
+	})
+
+	t.Run("Intersect", func(t *testing.T) {
+		box := spec.BoundingBox{X: 10, Y: 10, Width: 20, Height: 20}
+		assert.Equal(box.Intersect(spec.BoundingBox{X: 25, Y: 0, Width: 10, Height: 15}), spec.BoundingBox{X: 25, Y: 10, Width: 5, Height: 5})
+		assert.True(box.Intersect(spec.BoundingBox{X: 40, Y: 40, Width: 1, Height: 1}).IsEmpty())
+	})
+
+	t.Run("Union", func(t *testing.T) {
+		box := spec.BoundingBox{X: 10, Y: 10, Width: 20, Height: 20}
+		union := box.Union(spec.BoundingBox{X: 0, Y: 25, Width: 5, Height: 10})
+		assert.Equal(union, spec.BoundingBox{X: 0, Y: 10, Width: 30, Height: 25})
+		assert.Equal(box.Union(spec.BoundingBox{}), box)
+		assert.Equal(spec.BoundingBox{}.Union(box), box)
+	})
+}
@@ -24,11 +24,6 @@ type ComposableReadWriter interface {
 	ComposableWriter
 }
 
-// GetFilteredChildren(DisplayableFilter) []Displayable
-// IsContainedBy(d Displayable) bool
-// QuerySelector(selector string) Displayable
-// QuerySelectorAll(selector string) []Displayable
-
 func (c *Spec) ChildAt(index int) ReadWriter {
 	return c.children[index]
 }
@@ -63,6 +58,7 @@ func (c *Spec) Parent() ReadWriter {
 
 func (c *Spec) SetChildren(children []ReadWriter) {
 	c.children = children
+	c.InvalidateLayout()
 }
 
 func (c *Spec) SetKey(key string) {
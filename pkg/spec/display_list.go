@@ -0,0 +1,232 @@
+package spec
+
+// Command stores the name and arguments of a call to a Surface method.
+type Command struct {
+	Name string
+	Args []interface{}
+}
+
+// DisplayList holds the drawing Commands that were recorded for a node and
+// the DisplayLists of its children, so that an unchanged subtree can be drawn
+// again without calling its RenderHandlers.
+type DisplayList struct {
+	// Children are the DisplayLists of the children of the node, in order.
+	Children []*DisplayList
+
+	// Clip, when not empty, limits the drawing of Children to a rectangle
+	// that is relative to the origin of the node.
+	Clip BoundingBox
+
+	// Commands are relative to the origin of the node.
+	Commands []Command
+
+	// Key describes the properties of the node that the Commands were
+	// recorded from. It must be comparable.
+	Key interface{}
+
+	// X and Y are the position of the node within its parent.
+	X float64
+	Y float64
+}
+
+// Replay draws the Commands of this list and all of its children onto the
+// provided Surface, with the origin of the node at the provided coordinates.
+func (l *DisplayList) Replay(s Surface, x, y float64) {
+	l.ReplayCommands(s, x, y)
+	if len(l.Children) == 0 {
+		return
+	}
+	if !l.Clip.IsEmpty() {
+		s.Save()
+		s.IntersectScissor(x+l.Clip.X, y+l.Clip.Y, l.Clip.Width, l.Clip.Height)
+		defer s.Restore()
+	}
+	for _, child := range l.Children {
+		child.Replay(s, x+child.X, y+child.Y)
+	}
+}
+
+// ReplayCommands draws only the Commands of this list onto the provided
+// Surface, with the origin of the node at the provided coordinates.
+func (l *DisplayList) ReplayCommands(s Surface, x, y float64) {
+	for _, command := range l.Commands {
+		args := command.Args
+		switch command.Name {
+		case "Arc":
+			s.Arc(args[0].(float64)+x, args[1].(float64)+y, args[2].(float64), args[3].(float64), args[4].(float64))
+		case "BeginPath":
+			s.BeginPath()
+		case "Fill":
+			s.Fill()
+		case "IntersectScissor":
+			s.IntersectScissor(args[0].(float64)+x, args[1].(float64)+y, args[2].(float64), args[3].(float64))
+		case "Rect":
+			s.Rect(args[0].(float64)+x, args[1].(float64)+y, args[2].(float64), args[3].(float64))
+		case "ResetScissor":
+			s.ResetScissor()
+		case "Restore":
+			s.Restore()
+		case "RoundedRect":
+			s.RoundedRect(args[0].(float64)+x, args[1].(float64)+y, args[2].(float64), args[3].(float64), args[4].(float64))
+		case "Save":
+			s.Save()
+		case "Scissor":
+			s.Scissor(args[0].(float64)+x, args[1].(float64)+y, args[2].(float64), args[3].(float64))
+		case "SetFillColor":
+			s.SetFillColor(args[0].(uint))
+		case "SetFontFace":
+			s.SetFontFace(args[0].(string))
+		case "SetFontSize":
+			s.SetFontSize(args[0].(float64))
+		case "SetStrokeColor":
+			s.SetStrokeColor(args[0].(uint))
+		case "SetStrokeWidth":
+			s.SetStrokeWidth(args[0].(float64))
+		case "Stroke":
+			s.Stroke()
+		case "Text":
+			s.Text(args[0].(float64)+x, args[1].(float64)+y, args[2].(string))
+		}
+	}
+}
+
+// Recorder is a Surface that records drawing calls as Commands instead of
+// drawing them. Other calls (e.g., TextBounds) are forwarded to the Surface it
+// was created with.
+type Recorder struct {
+	commands   []Command
+	delegateTo Surface
+	offsetX    float64
+	offsetY    float64
+}
+
+// Commands returns the Commands that have been recorded.
+func (s *Recorder) Commands() []Command {
+	return s.commands
+}
+
+func (s *Recorder) record(name string, args ...interface{}) {
+	s.commands = append(s.commands, Command{Name: name, Args: args})
+}
+
+func (s *Recorder) Init() {
+	s.delegateTo.Init()
+}
+
+func (s *Recorder) Arc(xc, yc, radius, angle1, angle2 float64) {
+	s.record("Arc", xc+s.offsetX, yc+s.offsetY, radius, angle1, angle2)
+}
+
+func (s *Recorder) BeginPath() {
+	s.record("BeginPath")
+}
+
+func (s *Recorder) BeginFrame() {
+	s.delegateTo.BeginFrame()
+}
+
+func (s *Recorder) EndFrame() {
+	s.delegateTo.EndFrame()
+}
+
+func (s *Recorder) Close() {
+	s.delegateTo.Close()
+}
+
+func (s *Recorder) DebugDumpPathCache() {
+	s.delegateTo.DebugDumpPathCache()
+}
+
+func (s *Recorder) Fill() {
+	s.record("Fill")
+}
+
+func (s *Recorder) Rect(x, y, width, height float64) {
+	s.record("Rect", x+s.offsetX, y+s.offsetY, width, height)
+}
+
+func (s *Recorder) RoundedRect(x, y, width, height, radius float64) {
+	s.record("RoundedRect", x+s.offsetX, y+s.offsetY, width, height, radius)
+}
+
+func (s *Recorder) SetStrokeWidth(width float64) {
+	s.record("SetStrokeWidth", width)
+}
+
+func (s *Recorder) SetFillColor(color uint) {
+	s.record("SetFillColor", color)
+}
+
+func (s *Recorder) SetStrokeColor(color uint) {
+	s.record("SetStrokeColor", color)
+}
+
+func (s *Recorder) Stroke() {
+	s.record("Stroke")
+}
+
+func (s *Recorder) Scissor(x, y, width, height float64) {
+	s.record("Scissor", x+s.offsetX, y+s.offsetY, width, height)
+}
+
+func (s *Recorder) ResetScissor() {
+	s.record("ResetScissor")
+}
+
+func (s *Recorder) IntersectScissor(x, y, width, height float64) {
+	s.record("IntersectScissor", x+s.offsetX, y+s.offsetY, width, height)
+}
+
+func (s *Recorder) Save() {
+	s.record("Save")
+}
+
+func (s *Recorder) Restore() {
+	s.record("Restore")
+}
+
+func (s *Recorder) AddFont(name string, path string) {
+	s.delegateTo.AddFont(name, path)
+}
+
+func (s *Recorder) SetFontSize(size float64) {
+	s.record("SetFontSize", size)
+}
+
+func (s *Recorder) SetFontFace(face string) {
+	s.record("SetFontFace", face)
+}
+
+func (s *Recorder) Text(x float64, y float64, text string) {
+	s.record("Text", x+s.offsetX, y+s.offsetY, text)
+}
+
+func (s *Recorder) TextBounds(face string, size float64, text string) (x, y, w, h float64) {
+	return s.delegateTo.TextBounds(face, size, text)
+}
+
+func (s *Recorder) SetWidth(w float64) {
+	s.delegateTo.SetWidth(w)
+}
+
+func (s *Recorder) Width() float64 {
+	return s.delegateTo.Width()
+}
+
+func (s *Recorder) SetHeight(h float64) {
+	s.delegateTo.SetHeight(h)
+}
+
+func (s *Recorder) Height() float64 {
+	return s.delegateTo.Height()
+}
+
+// NewRecorder creates a Recorder that adds the provided offset to the
+// coordinates of each Command.
+func NewRecorder(delegateTo Surface, offsetX, offsetY float64) *Recorder {
+	return &Recorder{
+		delegateTo: delegateTo,
+		offsetX:    offsetX,
+		offsetY:    offsetY,
+	}
+}
@@ -0,0 +1,80 @@
+package spec_test
+
+import (
+	"testing"
+
+	"github.com/waybeams/assert"
+	surface "github.com/waybeams/waybeams/pkg/env/fake"
+	"github.com/waybeams/waybeams/pkg/spec"
+)
+
+func TestDisplayList(t *testing.T) {
+	t.Run("Recorder offsets coordinates", func(t *testing.T) {
+		delegate := surface.NewSurface()
+		recorder := spec.NewRecorder(delegate, -10, -20)
+		recorder.BeginPath()
+		recorder.Rect(10, 20, 30, 40)
+		recorder.Text(15, 25, "abc")
+
+		commands := recorder.Commands()
+		assert.Equal(len(commands), 3)
+		assert.Equal(commands[1].Name, "Rect")
+		assert.Equal(commands[1].Args[0], 0.0)
+		assert.Equal(commands[1].Args[1], 0.0)
+		assert.Equal(commands[1].Args[2], 30.0)
+		assert.Equal(commands[2].Args[0], 5.0)
+		assert.Equal(commands[2].Args[1], 5.0)
+		assert.Equal(len(delegate.GetCommands()), 0)
+	})
+
+	t.Run("Recorder forwards TextBounds", func(t *testing.T) {
+		recorder := spec.NewRecorder(surface.NewSurface(), 0, 0)
+		_, _, w, h := recorder.TextBounds("Roboto", 10, "abc")
+		assert.Equal(w, 12.0)
+		assert.Equal(h, 10.0)
+	})
+
+	t.Run("Replay clips children", func(t *testing.T) {
+		list := &spec.DisplayList{
+			Clip: spec.BoundingBox{Width: 10, Height: 20},
+			Children: []*spec.DisplayList{{
+				Commands: []spec.Command{{Name: "Fill"}},
+			}},
+		}
+		s := surface.NewSurface()
+		list.Replay(s, 5, 6)
+
+		commands := s.GetCommands()
+		assert.Equal(len(commands), 4)
+		assert.Equal(commands[0].Name, "Save")
+		assert.Equal(commands[1].Name, "IntersectScissor")
+		assert.Equal(commands[1].Args[0], 5.0)
+		assert.Equal(commands[1].Args[1], 6.0)
+		assert.Equal(commands[1].Args[2], 10.0)
+		assert.Equal(commands[1].Args[3], 20.0)
+		assert.Equal(commands[2].Name, "Fill")
+		assert.Equal(commands[3].Name, "Restore")
+	})
+
+	t.Run("Replay offsets children", func(t *testing.T) {
+		list := &spec.DisplayList{
+			Commands: []spec.Command{{Name: "Rect", Args: []interface{}{0.0, 0.0, 10.0, 10.0}}},
+			Children: []*spec.DisplayList{{
+				Commands: []spec.Command{{Name: "Text", Args: []interface{}{1.0, 2.0, "abc"}}},
+				X:        5,
+				Y:        6,
+			}},
+		}
+		s := surface.NewSurface()
+		list.Replay(s, 100, 200)
+
+		commands := s.GetCommands()
+		assert.Equal(len(commands), 2)
+		assert.Equal(commands[0].Args[0], 100.0)
+		assert.Equal(commands[0].Args[1], 200.0)
+		assert.Equal(commands[1].Name, "Text")
+		assert.Equal(commands[1].Args[0], 106.0)
+		assert.Equal(commands[1].Args[1], 208.0)
+		assert.Equal(commands[1].Args[2], "abc")
+	})
+}
@@ -0,0 +1,90 @@
+package spec
+
+// DockValue is the edge of a parent with the DockLayoutType that a child is
+// attached to.
+type DockValue int
+
+const (
+	DockNone DockValue = iota
+	DockTop
+	DockBottom
+	DockLeft
+	DockRight
+	DockFill
+)
+
+// AnchorEdge is a set of edges that a child pins to the matching edges of a
+// parent with the DockLayoutType.
+type AnchorEdge int
+
+const (
+	EdgeLeft AnchorEdge = 1 << iota
+	EdgeTop
+	EdgeRight
+	EdgeBottom
+)
+
+// Anchors pins the Edges of a child to the matching edges of its parent,
+// each at the provided offset from the inside of the parent padding.
+type Anchors struct {
+	Edges  AnchorEdge `json:"edges,omitempty"`
+	Left   float64    `json:"left,omitempty"`
+	Top    float64    `json:"top,omitempty"`
+	Right  float64    `json:"right,omitempty"`
+	Bottom float64    `json:"bottom,omitempty"`
+}
+
+// Has returns true if the provided edge is pinned.
+func (a Anchors) Has(edge AnchorEdge) bool {
+	return a.Edges&edge != 0
+}
+
+// With returns a copy of these Anchors with the provided edge pinned at the
+// provided offset.
+func (a Anchors) With(edge AnchorEdge, offset float64) Anchors {
+	a.Edges |= edge
+	switch edge {
+	case EdgeLeft:
+		a.Left = offset
+	case EdgeTop:
+		a.Top = offset
+	case EdgeRight:
+		a.Right = offset
+	case EdgeBottom:
+		a.Bottom = offset
+	}
+	return a
+}
+
+// DockReader provides read-only access to Dock layout features.
+type DockReader interface {
+	Anchors() Anchors
+	Dock() DockValue
+}
+
+// DockWriter provides write-only access to Dock layout features.
+type DockWriter interface {
+	SetAnchors(anchors Anchors)
+	SetDock(dock DockValue)
+}
+
+// Anchors returns the edges that this node pins to a parent Dock layout.
+func (c *Spec) Anchors() Anchors {
+	return c.anchors
+}
+
+// Dock returns the edge of a parent Dock layout that this node is attached
+// to.
+func (c *Spec) Dock() DockValue {
+	return c.dock
+}
+
+func (c *Spec) SetAnchors(anchors Anchors) {
+	c.anchors = anchors
+	c.InvalidateLayoutFor("anchors", anchors)
+}
+
+func (c *Spec) SetDock(dock DockValue) {
+	c.dock = dock
+	c.InvalidateLayoutFor("dock", dock)
+}
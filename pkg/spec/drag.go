@@ -0,0 +1,78 @@
+package spec
+
+// DragPayload is the Payload of DragStarted, Dragging, DragEntered,
+// DragExited, Dropped and DragEnded. The same DragPayload is sent with each
+// event of a drag, so the Data that a handler of DragStarted provides is
+// available to the drop targets.
+type DragPayload struct {
+	// Data is provided by handlers of DragStarted and read by drop targets.
+	Data interface{}
+
+	// Source is the draggable node (see Spec.IsDraggable) that is dragged.
+	Source ReadWriter
+
+	// Target is the drop target (see Spec.IsDropTarget) under the pointer, or
+	// nil if there is none.
+	Target ReadWriter
+
+	// StartX and StartY are the global position where the drag began.
+	StartX float64
+	StartY float64
+
+	// X and Y are the global position of the pointer.
+	X float64
+	Y float64
+}
+
+// Position returns the global position of the pointer.
+func (p *DragPayload) Position() (x, y float64) {
+	return p.X, p.Y
+}
+
+// DraggableAt returns the deepest draggable node (see Spec.IsDraggable) that
+// contains the provided global coordinate, or nil if there is none. Nodes
+// are searched in the same order as CoordToControl.
+func DraggableAt(r ReadWriter, globalX, globalY float64) ReadWriter {
+	return deepestAt(r, nil, globalX, globalY, Reader.IsDraggable)
+}
+
+// DropTargetAt returns the deepest drop target (see Spec.IsDropTarget) that
+// contains the provided global coordinate, or nil if there is none. The
+// provided source (which can be nil) and its descendants are left out, so
+// that a dragged node is not dropped onto itself.
+func DropTargetAt(r ReadWriter, source Reader, globalX, globalY float64) ReadWriter {
+	return deepestAt(r, source, globalX, globalY, Reader.IsDropTarget)
+}
+
+// deepestAt returns the deepest node that matches and contains the provided
+// global coordinate, leaving out the provided subtree. The Overlays of the
+// provided node are searched first, from the topmost down, like
+// CoordToControl.
+func deepestAt(r ReadWriter, skip Reader, globalX, globalY float64, matches func(Reader) bool) ReadWriter {
+	overlays := Overlays(r)
+	for index := len(overlays) - 1; index >= 0; index-- {
+		overlay := overlays[index]
+		isSkipped := overlay == skip || Contains(skip, overlay)
+		if !isSkipped && ContainsCoordinate(overlay, globalX, globalY) {
+			return deepestWithin(overlay, skip, globalX, globalY, matches)
+		}
+	}
+	return deepestWithin(r, skip, globalX, globalY, matches)
+}
+
+func deepestWithin(r ReadWriter, skip Reader, globalX, globalY float64, matches func(Reader) bool) ReadWriter {
+	children := DrawOrder(r)
+	for index := len(children) - 1; index >= 0; index-- {
+		child := children[index]
+		if child != skip && ContainsCoordinate(child, globalX, globalY) {
+			if found := deepestWithin(child, skip, globalX, globalY, matches); found != nil {
+				return found
+			}
+			break
+		}
+	}
+	if matches(r) {
+		return r
+	}
+	return nil
+}
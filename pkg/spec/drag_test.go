@@ -0,0 +1,66 @@
+package spec_test
+
+import (
+	"testing"
+
+	"github.com/waybeams/assert"
+	"github.com/waybeams/waybeams/pkg/ctrl"
+	surface "github.com/waybeams/waybeams/pkg/env/fake"
+	"github.com/waybeams/waybeams/pkg/layout"
+	"github.com/waybeams/waybeams/pkg/opts"
+	"github.com/waybeams/waybeams/pkg/spec"
+)
+
+func TestDrag(t *testing.T) {
+	var create = func() spec.ReadWriter {
+		return layout.Layout(ctrl.VBox(
+			opts.Width(100),
+			opts.Height(100),
+			opts.HAlign(spec.AlignLeft),
+			opts.Gutter(0),
+			opts.Child(ctrl.VBox(
+				opts.Key("list"),
+				opts.IsDropTarget(true),
+				opts.Gutter(0),
+				opts.Width(100),
+				opts.Height(60),
+				opts.Child(ctrl.Box(
+					opts.Key("first"),
+					opts.IsDraggable(true),
+					opts.Width(100),
+					opts.Height(20),
+					opts.Child(ctrl.Box(opts.Key("label"), opts.Width(50), opts.Height(20))),
+				)),
+				opts.Child(ctrl.Box(
+					opts.Key("second"),
+					opts.IsDraggable(true),
+					opts.IsDropTarget(true),
+					opts.Width(100),
+					opts.Height(20),
+				)),
+			)),
+			opts.Child(ctrl.Box(opts.Key("trash"), opts.IsDropTarget(true), opts.Width(100), opts.Height(40))),
+		), surface.NewSurface())
+	}
+
+	t.Run("Finds the draggable under a coordinate", func(t *testing.T) {
+		root := create()
+		assert.Equal(spec.DraggableAt(root, 10, 5).Key(), "first")
+		assert.Equal(spec.DraggableAt(root, 10, 25).Key(), "second")
+		assert.Nil(spec.DraggableAt(root, 10, 80))
+	})
+
+	t.Run("Finds the deepest drop target under a coordinate", func(t *testing.T) {
+		root := create()
+		assert.Equal(spec.DropTargetAt(root, nil, 10, 25).Key(), "second")
+		assert.Equal(spec.DropTargetAt(root, nil, 10, 5).Key(), "list")
+		assert.Equal(spec.DropTargetAt(root, nil, 10, 80).Key(), "trash")
+		assert.Nil(spec.DropTargetAt(root, nil, 200, 200))
+	})
+
+	t.Run("Leaves out the dragged node", func(t *testing.T) {
+		root := create()
+		second := spec.FirstByKey(root, "second")
+		assert.Equal(spec.DropTargetAt(root, second, 10, 25).Key(), "list")
+	})
+}
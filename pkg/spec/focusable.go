@@ -1,17 +1,30 @@
 package spec
 
+import "sort"
+
 type FocusableReader interface {
 	FocusedSpec() ReadWriter
+	IsDraggable() bool
+	IsDropTarget() bool
 	IsFocusable() bool
+	IsFocusScope() bool
 	IsText() bool
 	IsTextInput() bool
+	Neighbor(direction FocusDirection) string
+	Neighbors() map[FocusDirection]string
+	TabIndex() int
 }
 
 type FocusableWriter interface {
 	SetFocusedSpec(spec ReadWriter)
+	SetIsDraggable(value bool)
+	SetIsDropTarget(value bool)
 	SetIsFocusable(value bool)
+	SetIsFocusScope(value bool)
 	SetIsText(value bool)
 	SetIsTextInput(value bool)
+	SetNeighbor(direction FocusDirection, key string)
+	SetTabIndex(value int)
 }
 
 type FocusableReadWriter interface {
@@ -34,10 +47,28 @@ func (c *Spec) SetFocusedSpec(spec ReadWriter) {
 	Root(c).SetFocusedSpec(spec)
 }
 
+// IsDraggable returns true if this node can be dragged. Drags begin when
+// the pointer is pressed on this node, or on one of its descendants, and
+// moved (see DragPayload).
+func (c *Spec) IsDraggable() bool {
+	return c.isDraggable
+}
+
+// IsDropTarget returns true if dragged nodes can be dropped onto this node.
+func (c *Spec) IsDropTarget() bool {
+	return c.isDropTarget
+}
+
 func (c *Spec) IsFocusable() bool {
 	return c.isFocusable
 }
 
+// IsFocusScope returns true if Tab traversal that begins within this node
+// stays within it (see FocusScope).
+func (c *Spec) IsFocusScope() bool {
+	return c.isFocusScope
+}
+
 func (c *Spec) IsText() bool {
 	return c.isText
 }
@@ -46,10 +77,22 @@ func (c *Spec) IsTextInput() bool {
 	return c.isTextInput
 }
 
+func (c *Spec) SetIsDraggable(value bool) {
+	c.isDraggable = value
+}
+
+func (c *Spec) SetIsDropTarget(value bool) {
+	c.isDropTarget = value
+}
+
 func (c *Spec) SetIsFocusable(value bool) {
 	c.isFocusable = value
 }
 
+func (c *Spec) SetIsFocusScope(value bool) {
+	c.isFocusScope = value
+}
+
 func (c *Spec) SetIsText(value bool) {
 	c.isText = value
 }
@@ -57,3 +100,106 @@ func (c *Spec) SetIsText(value bool) {
 func (c *Spec) SetIsTextInput(value bool) {
 	c.isTextInput = value
 }
+
+// Neighbor returns the Key of the node that arrow keys move focus to from
+// this node in the provided direction, or an empty string if the nearest
+// node in that direction is focused (see NeighborFocusable).
+func (c *Spec) Neighbor(direction FocusDirection) string {
+	return c.neighbors[direction]
+}
+
+func (c *Spec) SetNeighbor(direction FocusDirection, key string) {
+	if c.neighbors == nil {
+		c.neighbors = make(map[FocusDirection]string)
+	}
+	c.neighbors[direction] = key
+}
+
+// Neighbors returns the Neighbor of each direction that was configured.
+func (c *Spec) Neighbors() map[FocusDirection]string {
+	return c.neighbors
+}
+
+// TabIndex orders this node for Tab traversal (see FocusOrder). Nodes with a
+// positive TabIndex come first, from the lowest, followed by those with the
+// default of zero in document order. Nodes with a negative TabIndex are left
+// out, and can only be focused with the pointer.
+func (c *Spec) TabIndex() int {
+	return c.tabIndex
+}
+
+func (c *Spec) SetTabIndex(value int) {
+	c.tabIndex = value
+}
+
+// FocusScope returns the node that Tab traversal from the provided focused
+// node (which can be nil) stays within. This is the nearest focus scope (see
+// Spec.IsFocusScope) that contains the focused node, or the root. While the
+// topmost shown Overlay is a focus scope, like a modal dialog, focus is kept
+// within that Overlay.
+func FocusScope(root, focused ReadWriter) ReadWriter {
+	scope := root
+	for candidate := focused; candidate != nil; candidate = candidate.Parent() {
+		if candidate.IsFocusScope() {
+			scope = candidate
+			break
+		}
+	}
+	overlays := Overlays(root)
+	if len(overlays) > 0 {
+		modal := overlays[len(overlays)-1]
+		if modal.IsFocusScope() && modal != scope && !Contains(modal, scope) {
+			return modal
+		}
+	}
+	return scope
+}
+
+// FocusOrder returns the shown, focusable nodes within the provided scope, in
+// the order that Tab moves focus between them (see Spec.TabIndex).
+func FocusOrder(scope ReadWriter) []ReadWriter {
+	var result []ReadWriter
+	var collect func(node ReadWriter)
+	collect = func(node ReadWriter) {
+		if !node.Visible() {
+			return
+		}
+		if node.IsFocusable() && node.TabIndex() >= 0 {
+			result = append(result, node)
+		}
+		for _, child := range node.Children() {
+			collect(child)
+		}
+	}
+	if IsShown(scope) {
+		collect(scope)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		first, second := result[i].TabIndex(), result[j].TabIndex()
+		return first > 0 && (second == 0 || first < second)
+	})
+	return result
+}
+
+// NextFocusable returns the node that Tab (or Shift+Tab, when backward)
+// moves focus to from the provided focused node, which can be nil. Focus
+// wraps around within the FocusScope, and nil is returned if there is
+// nothing to focus.
+func NextFocusable(root, focused ReadWriter, backward bool) ReadWriter {
+	order := FocusOrder(FocusScope(root, focused))
+	if len(order) == 0 {
+		return nil
+	}
+	for index, candidate := range order {
+		if candidate == focused {
+			if backward {
+				return order[(index+len(order)-1)%len(order)]
+			}
+			return order[(index+1)%len(order)]
+		}
+	}
+	if backward {
+		return order[len(order)-1]
+	}
+	return order[0]
+}
@@ -55,4 +55,78 @@ func TestFocusable(t *testing.T) {
 		assert.Equal(two.FocusedSpec().Key(), "two")
 		assert.Equal(root.FocusedSpec().Key(), "two")
 	})
+
+	t.Run("Traversal", func(t *testing.T) {
+		var createForm = func(options ...spec.Option) spec.ReadWriter {
+			defaults := []spec.Option{
+				opts.Key("root"),
+				opts.Child(ctrl.Box(opts.Key("name"), opts.IsFocusable(true))),
+				opts.Child(ctrl.Box(opts.Key("email"), opts.IsFocusable(true), opts.TabIndex(2))),
+				opts.Child(ctrl.Box(
+					opts.Key("group"),
+					opts.IsFocusScope(true),
+					opts.Child(ctrl.Box(opts.Key("street"), opts.IsFocusable(true))),
+					opts.Child(ctrl.Box(opts.Key("city"), opts.IsFocusable(true))),
+				)),
+				opts.Child(ctrl.Box(opts.Key("submit"), opts.IsFocusable(true), opts.TabIndex(1))),
+			}
+			return ctrl.Box(append(defaults, options...)...)
+		}
+
+		var keys = func(nodes []spec.ReadWriter) string {
+			result := ""
+			for _, node := range nodes {
+				result += "/" + node.Key()
+			}
+			return result
+		}
+
+		t.Run("Orders by TabIndex, then by document", func(t *testing.T) {
+			root := createForm()
+			assert.Equal(keys(spec.FocusOrder(root)), "/submit/email/name/street/city")
+		})
+
+		t.Run("Leaves out negative TabIndex and hidden nodes", func(t *testing.T) {
+			root := createForm()
+			spec.FirstByKey(root, "email").SetTabIndex(-1)
+			spec.FirstByKey(root, "group").SetVisible(false)
+			assert.Equal(keys(spec.FocusOrder(root)), "/submit/name")
+		})
+
+		t.Run("Stays within the focus scope", func(t *testing.T) {
+			root := createForm()
+			street := spec.FirstByKey(root, "street")
+			city := spec.FirstByKey(root, "city")
+			assert.Equal(spec.FocusScope(root, street).Key(), "group")
+			assert.Equal(spec.NextFocusable(root, street, false), city)
+			assert.Equal(spec.NextFocusable(root, city, false), street)
+			assert.Equal(spec.NextFocusable(root, street, true), city)
+		})
+
+		t.Run("Wraps around from the root scope", func(t *testing.T) {
+			root := createForm()
+			name := spec.FirstByKey(root, "name")
+			assert.Equal(spec.NextFocusable(root, nil, false).Key(), "submit")
+			assert.Equal(spec.NextFocusable(root, nil, true).Key(), "city")
+			assert.Equal(spec.NextFocusable(root, name, false).Key(), "street")
+			assert.Equal(spec.NextFocusable(root, spec.FirstByKey(root, "submit"), true).Key(), "city")
+		})
+
+		t.Run("Traps focus in a modal overlay", func(t *testing.T) {
+			root := createForm(opts.Child(ctrl.Box(
+				opts.Key("dialog"),
+				opts.IsOverlay(true),
+				opts.IsFocusScope(true),
+				opts.Child(ctrl.Box(opts.Key("ok"), opts.IsFocusable(true))),
+				opts.Child(ctrl.Box(opts.Key("cancel"), opts.IsFocusable(true))),
+			)))
+			name := spec.FirstByKey(root, "name")
+			assert.Equal(spec.FocusScope(root, name).Key(), "dialog")
+			assert.Equal(spec.NextFocusable(root, name, false).Key(), "ok")
+			assert.Equal(spec.NextFocusable(root, spec.FirstByKey(root, "cancel"), false).Key(), "ok")
+
+			spec.FirstByKey(root, "dialog").SetVisible(false)
+			assert.Equal(spec.NextFocusable(root, name, false).Key(), "street")
+		})
+	})
 }
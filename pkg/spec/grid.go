@@ -0,0 +1,171 @@
+package spec
+
+import (
+	"strconv"
+	"strings"
+)
+
+// GridTrack describes the size of a single row or column in a Grid layout.
+// Tracks with a Size are fixed, tracks with a Flex share the remaining space
+// in proportion to their Flex value (like the CSS "fr" unit), and tracks
+// with neither are sized to fit their content.
+type GridTrack struct {
+	Flex float64 `json:"flex,omitempty"`
+	Size float64 `json:"size,omitempty"`
+}
+
+// IsAuto returns true if the track is sized to fit its content.
+func (t GridTrack) IsAuto() bool {
+	return t.Size == 0 && t.Flex == 0
+}
+
+// IsFlexible returns true if the track shares the remaining space.
+func (t GridTrack) IsFlexible() bool {
+	return t.Size == 0 && t.Flex > 0
+}
+
+// FixedTrack creates a GridTrack of the provided size.
+func FixedTrack(size float64) GridTrack {
+	return GridTrack{Size: size}
+}
+
+// FlexTrack creates a GridTrack that shares the remaining space with other
+// flexible tracks in proportion to the provided value.
+func FlexTrack(flex float64) GridTrack {
+	return GridTrack{Flex: flex}
+}
+
+// AutoTrack creates a GridTrack that is sized to fit the largest child that
+// is placed in it.
+func AutoTrack() GridTrack {
+	return GridTrack{}
+}
+
+// GridReader provides read-only access to Grid layout features.
+type GridReader interface {
+	ColumnGutter() float64
+	GridColumn() int
+	GridColumnSpan() int
+	GridColumns() []GridTrack
+	GridRow() int
+	GridRowSpan() int
+	GridRows() []GridTrack
+	RowGutter() float64
+}
+
+// GridWriter provides write-only access to Grid layout features.
+type GridWriter interface {
+	SetColumnGutter(value float64)
+	SetGridColumn(column int)
+	SetGridColumnSpan(span int)
+	SetGridColumns(tracks []GridTrack)
+	SetGridRow(row int)
+	SetGridRowSpan(span int)
+	SetGridRows(tracks []GridTrack)
+	SetRowGutter(value float64)
+}
+
+// ColumnGutter returns the space between Grid columns, which defaults to
+// Gutter.
+func (c *Spec) ColumnGutter() float64 {
+	if c.columnGutter == 0 {
+		return c.Gutter()
+	}
+	return c.columnGutter
+}
+
+// GridColumn returns the one-based column where this node is placed in a
+// parent Grid, or zero if it should be placed automatically.
+func (c *Spec) GridColumn() int {
+	return c.gridColumn
+}
+
+// GridColumnSpan returns the number of columns this node covers in a parent
+// Grid.
+func (c *Spec) GridColumnSpan() int {
+	if c.gridColumnSpan < 1 {
+		return 1
+	}
+	return c.gridColumnSpan
+}
+
+// GridColumns returns the column tracks for a Grid layout.
+func (c *Spec) GridColumns() []GridTrack {
+	return c.gridColumns
+}
+
+// GridRow returns the one-based row where this node is placed in a parent
+// Grid, or zero if it should be placed automatically.
+func (c *Spec) GridRow() int {
+	return c.gridRow
+}
+
+// GridRowSpan returns the number of rows this node covers in a parent Grid.
+func (c *Spec) GridRowSpan() int {
+	if c.gridRowSpan < 1 {
+		return 1
+	}
+	return c.gridRowSpan
+}
+
+// GridRows returns the row tracks for a Grid layout.
+func (c *Spec) GridRows() []GridTrack {
+	return c.gridRows
+}
+
+// RowGutter returns the space between Grid rows, which defaults to Gutter.
+func (c *Spec) RowGutter() float64 {
+	if c.rowGutter == 0 {
+		return c.Gutter()
+	}
+	return c.rowGutter
+}
+
+func (c *Spec) SetColumnGutter(value float64) {
+	c.columnGutter = value
+	c.InvalidateLayoutFor("columnGutter", value)
+}
+
+func (c *Spec) SetGridColumn(column int) {
+	c.gridColumn = column
+	c.InvalidateLayoutFor("gridColumn", column)
+}
+
+func (c *Spec) SetGridColumnSpan(span int) {
+	c.gridColumnSpan = span
+	c.InvalidateLayoutFor("gridColumnSpan", span)
+}
+
+func (c *Spec) SetGridColumns(tracks []GridTrack) {
+	c.gridColumns = tracks
+	c.InvalidateLayoutFor("gridColumns", encodeGridTracks(tracks))
+}
+
+func (c *Spec) SetGridRow(row int) {
+	c.gridRow = row
+	c.InvalidateLayoutFor("gridRow", row)
+}
+
+func (c *Spec) SetGridRowSpan(span int) {
+	c.gridRowSpan = span
+	c.InvalidateLayoutFor("gridRowSpan", span)
+}
+
+func (c *Spec) SetGridRows(tracks []GridTrack) {
+	c.gridRows = tracks
+	c.InvalidateLayoutFor("gridRows", encodeGridTracks(tracks))
+}
+
+func (c *Spec) SetRowGutter(value float64) {
+	c.rowGutter = value
+	c.InvalidateLayoutFor("rowGutter", value)
+}
+
+// encodeGridTracks returns a comparable form of the provided tracks.
+func encodeGridTracks(tracks []GridTrack) string {
+	encoded := make([]string, len(tracks))
+	for index, track := range tracks {
+		encoded[index] = strconv.FormatFloat(track.Flex, 'g', -1, 64) + ":" + strconv.FormatFloat(track.Size, 'g', -1, 64)
+	}
+	return strings.Join(encoded, ",")
+}
@@ -1,6 +1,10 @@
 package spec
 
-import "strconv"
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
 
 func applyOptionsForState(rw ReadWriter) ReadWriter {
 	options := rw.OptionsForState(rw.State())
@@ -76,6 +80,25 @@ func FirstByKey(rw ReadWriter, key string) ReadWriter {
 	return nil
 }
 
+// FirstByPath returns the first node, beginning with the provided node, whose
+// Path matches the provided path, or nil if none is found.
+func FirstByPath(rw ReadWriter, path string) ReadWriter {
+	nodePath := Path(rw)
+	if nodePath == path {
+		return rw
+	}
+	if !strings.HasPrefix(path, nodePath+"/") {
+		return nil
+	}
+	for _, child := range rw.Children() {
+		result := FirstByPath(child, path)
+		if result != nil {
+			return result
+		}
+	}
+	return nil
+}
+
 func Path(r Reader) string {
 	parent := r.Parent()
 	localPath := "/" + pathPart(r)
@@ -130,6 +153,17 @@ func NearestFocusable(r ReadWriter) ReadWriter {
 	return nil
 }
 
+// IsShown returns true if the provided node and all of its ancestors are
+// Visible. Nodes that are not shown are not drawn, hit tested or focused.
+func IsShown(r Reader) bool {
+	for current := r; current != nil; current = current.Parent() {
+		if !current.Visible() {
+			return false
+		}
+	}
+	return true
+}
+
 // ContainsCoordinate returns true if the provided global coordinate falls
 // within the boundaries of the provided spec.Reader.
 func ContainsCoordinate(r Reader, globalX, globalY float64) bool {
@@ -142,27 +176,81 @@ func ContainsCoordinate(r Reader, globalX, globalY float64) bool {
 // CoordToControl will return the deepest Focusable node that contains the
 // provided global coordinate.
 //
-// The search will begin at the provided node (usually root), and at each level,
-// will step forward only along the child that contains the coordinate. Once a
-// leaf is found, the code will walk back up until the nearest Focusable node
-// is returned.
+// The Overlays of the provided node (usually root) are searched first, from
+// the topmost down, and the node itself after them. At each level, the search
+// will step forward only along the topmost child (see DrawOrder) that contains
+// the coordinate. Once a leaf is found, the code will walk back up until the
+// nearest Focusable node is returned.
 func CoordToControl(r ReadWriter, globalX, globalY float64) ReadWriter {
-	result := r
+	overlays := Overlays(r)
+	for index := len(overlays) - 1; index >= 0; index-- {
+		if ContainsCoordinate(overlays[index], globalX, globalY) {
+			return coordToControl(overlays[index], globalX, globalY)
+		}
+	}
+	return coordToControl(r, globalX, globalY)
+}
 
-	children := r.Children()
+func coordToControl(r ReadWriter, globalX, globalY float64) ReadWriter {
+	children := DrawOrder(r)
 	if len(children) == 0 {
 		// We have reached a leaf, now walk back toward root and return the
 		// first focusable element we find.
 		return NearestFocusable(r)
 	}
 
-	for _, child := range children {
+	for index := len(children) - 1; index >= 0; index-- {
+		child := children[index]
 		if ContainsCoordinate(child, globalX, globalY) {
-			result = CoordToControl(child, globalX, globalY)
-			break
+			return coordToControl(child, globalX, globalY)
 		}
 	}
+	return r
+}
 
+// DrawOrder returns the children of the provided node that are drawn with it,
+// from the bottom-most to the topmost. Children are ordered by ZIndex, then by
+// their order in Children. Children that are not Visible, and Overlays, are
+// left out.
+func DrawOrder(r Reader) []ReadWriter {
+	children := r.Children()
+	for index, child := range children {
+		if !child.Visible() || child.IsOverlay() ||
+			(index > 0 && child.ZIndex() < children[index-1].ZIndex()) {
+			result := FilteredChildren(r, func(child Reader) bool {
+				return child.Visible() && !child.IsOverlay()
+			})
+			sort.SliceStable(result, func(i, j int) bool {
+				return result[i].ZIndex() < result[j].ZIndex()
+			})
+			return result
+		}
+	}
+	return children
+}
+
+// Overlays returns the stack of overlay nodes (see Spec.IsOverlay) that are
+// shown within the provided tree, from the bottom-most to the topmost.
+// Overlays are ordered by ZIndex, then by their order in the tree, so nested
+// Overlays are above their ancestors.
+func Overlays(r Reader) []ReadWriter {
+	var result []ReadWriter
+	var collect func(node Reader)
+	collect = func(node Reader) {
+		for _, child := range node.Children() {
+			if !child.Visible() {
+				continue
+			}
+			if child.IsOverlay() {
+				result = append(result, child)
+			}
+			collect(child)
+		}
+	}
+	collect(r)
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].ZIndex() < result[j].ZIndex()
+	})
 	return result
 }
 
@@ -1,5 +1,7 @@
 package spec
 
+import "strings"
+
 type LayoutAxis int
 
 const (
@@ -7,21 +9,22 @@ const (
 	LayoutVertical
 )
 
-// LayoutTypeValue is a serializable enum for selecting a layout scheme.
-// This pattern is probably not the way to go, but I'm having trouble finding a
-// reasonable alternative. The problem here is that LayoutHandler types will not be
-// user-extensible. Box definitions will only be able to refer to the
-// Layouts that have been enumerated here. The benefit is that Model objects
-// will remain serializable and simply be a bag of scalars. I'm definitely
-// open to suggestions.
-type LayoutTypeValue int
+// LayoutTypeValue is a serializable name for selecting a layout scheme. Specs
+// only refer to a layout by this value, which keeps Model objects serializable
+// as a simple bag of scalars. The layout package maps each value to the
+// handler that implements it, and applications can register their own.
+type LayoutTypeValue string
 
 const (
-	NoLayoutType = iota
-	StackLayoutType
-	VerticalFlowLayoutType
-	HorizontalFlowLayoutType
-	RowLayoutType
+	NoLayoutType             LayoutTypeValue = ""
+	StackLayoutType          LayoutTypeValue = "Stack"
+	VerticalFlowLayoutType   LayoutTypeValue = "VerticalFlow"
+	HorizontalFlowLayoutType LayoutTypeValue = "HorizontalFlow"
+	RowLayoutType            LayoutTypeValue = "Row"
+	GridLayoutType           LayoutTypeValue = "Grid"
+	ConstraintLayoutType     LayoutTypeValue = "Constraint"
+	DockLayoutType           LayoutTypeValue = "Dock"
+	ScrollLayoutType         LayoutTypeValue = "Scroll"
 )
 
 // Alignment is used represent alignment of Spec children, text or any other
@@ -37,6 +40,19 @@ const (
 	AlignMiddle // DO NOT USE EXCEPT FOR COMPAT w/fontstashmini alignment api
 )
 
+// JustifyValue determines how a Flow layout distributes the space that
+// remains along its axis after children have been sized.
+type JustifyValue int
+
+const (
+	JustifyStart JustifyValue = iota
+	JustifyEnd
+	JustifyCenter
+	JustifySpaceBetween
+	JustifySpaceAround
+	JustifySpaceEvenly
+)
+
 // LayoutHandler is a concrete implementation of a given layout. These handlers
 // are pure functions that accept a Displayable and manage the scale and
 // position of the children for that element.
@@ -55,10 +71,15 @@ type ResizableReader interface {
 type LayoutableWriter interface {
 	ResizableWriter
 
+	InvalidateLayout()
+	InvalidateLayoutFor(property string, value interface{})
 	SetActualHeight(value float64)
 	SetActualWidth(value float64)
+	SetAlignSelf(align Alignment)
+	SetAspectRatio(ratio float64)
 	SetChildrenHeight(height float64)
 	SetChildrenWidth(width float64)
+	SetConstraints(constraints ...string)
 	SetContentHeight(height float64)
 	SetContentWidth(width float64)
 	SetExcludeFromLayout(bool)
@@ -66,8 +87,15 @@ type LayoutableWriter interface {
 	SetFlexWidth(int float64)
 	SetGutter(value float64)
 	SetHAlign(align Alignment)
+	SetIsLayoutDirty(dirty bool)
 	SetIsMeasured(measured bool)
+	SetJustify(justify JustifyValue)
 	SetLayoutType(layoutType LayoutTypeValue)
+	SetMargin(value float64)
+	SetMarginBottom(value float64)
+	SetMarginLeft(value float64)
+	SetMarginRight(value float64)
+	SetMarginTop(value float64)
 	SetMaxHeight(h float64)
 	SetMaxWidth(w float64)
 	SetMinHeight(h float64)
@@ -79,6 +107,8 @@ type LayoutableWriter interface {
 	SetPaddingTop(value float64)
 	SetPrefHeight(value float64)
 	SetPrefWidth(value float64)
+	SetScrollX(value float64)
+	SetScrollY(value float64)
 	SetTextX(value float64)
 	SetTextY(value float64)
 	SetVAlign(align Alignment)
@@ -91,8 +121,11 @@ type LayoutableReader interface {
 
 	ActualHeight() float64
 	ActualWidth() float64
+	AlignSelf() Alignment
+	AspectRatio() float64
 	ChildrenHeight() float64
 	ChildrenWidth() float64
+	Constraints() []string
 	ContentHeight() float64
 	ContentWidth() float64
 	ExcludeFromLayout() bool
@@ -100,23 +133,36 @@ type LayoutableReader interface {
 	FlexWidth() float64
 	Gutter() float64
 	HAlign() Alignment
+	HasAlignSelf() bool
+	IsLayoutDirty() bool
 	IsMeasured() bool
+	HorizontalMargin() float64
 	HorizontalPadding() float64
+	Justify() JustifyValue
 	LayoutType() LayoutTypeValue
+	MarginBottom() float64
+	MarginLeft() float64
+	MarginRight() float64
+	MarginTop() float64
 	MaxHeight() float64
 	MaxWidth() float64
 	Measure(s Surface)
 	MinHeight() float64
 	MinWidth() float64
+	NeedsLayout() bool
 	PaddingBottom() float64
 	PaddingLeft() float64
 	PaddingRight() float64
 	PaddingTop() float64
 	PrefHeight() float64
 	PrefWidth() float64
+	ScrollX() float64
+	ScrollY() float64
+	TextOffset() (x, y float64)
 	TextX() float64
 	TextY() float64
 	VAlign() Alignment
+	VerticalMargin() float64
 	VerticalPadding() float64
 	X() float64
 	XOffset() float64
@@ -129,6 +175,24 @@ type LayoutableReadWriter interface {
 	LayoutableWriter
 }
 
+// HeightForWidthMeasurer is implemented by measured Specs whose height
+// depends on the width they are given, like wrapped text. Layout calls
+// MeasureHeightForWidth once widths are known, with the width that is
+// available inside of the padding, and uses the returned value as the
+// ContentHeight before laying out the vertical axis.
+type HeightForWidthMeasurer interface {
+	MeasureHeightForWidth(s Surface, width float64) float64
+}
+
+// Scroller is implemented by Specs with a Scroll LayoutType that only scroll
+// on some axes. Specs that do not implement it scroll on both axes. Layout
+// calls SetScrollSize on every axis with the size of the children on that
+// axis, which may be larger than the space inside of the padding.
+type Scroller interface {
+	IsScrollable(axis LayoutAxis) bool
+	SetScrollSize(axis LayoutAxis, size float64)
+}
+
 func (c *Spec) ActualHeight() float64 {
 	return c.actualHeight
 }
@@ -139,12 +203,102 @@ func (c *Spec) ActualWidth() float64 {
 
 func (c *Spec) SetLayoutType(layoutType LayoutTypeValue) {
 	c.layoutType = layoutType
+	c.InvalidateLayoutFor("layoutType", layoutType)
 }
 
 func (c *Spec) LayoutType() LayoutTypeValue {
 	return c.layoutType
 }
 
+// SetConstraints replaces the constraints that position this Spec within a
+// parent that uses the ConstraintLayoutType.
+func (c *Spec) SetConstraints(constraints ...string) {
+	c.constraints = constraints
+	c.InvalidateLayoutFor("constraints", strings.Join(constraints, "\n"))
+}
+
+// Constraints returns the constraints that position this Spec within a
+// parent that uses the ConstraintLayoutType (e.g., "left == sibling.right + 8").
+func (c *Spec) Constraints() []string {
+	return c.constraints
+}
+
+// IsLayoutDirty returns true if this node, or any of its descendants, has
+// changed since it was last laid out. New nodes are always dirty.
+func (c *Spec) IsLayoutDirty() bool {
+	return !c.isLayoutClean
+}
+
+// NeedsLayout returns true if this node must be laid out again, rather than
+// only some of its descendants. New nodes always need layout.
+func (c *Spec) NeedsLayout() bool {
+	return !c.isArranged
+}
+
+// SetIsLayoutDirty updates whether this node needs to be laid out, without
+// notifying any ancestors. Layout clears this flag once a node is laid out.
+func (c *Spec) SetIsLayoutDirty(dirty bool) {
+	c.isArranged = !dirty
+	c.isLayoutClean = !dirty
+	if !dirty {
+		c.isLaidOut = true
+	}
+}
+
+// InvalidateLayout marks this node as needing layout. Its parent also needs
+// layout, because it arranges this node, but the ancestors above it are only
+// marked as containing a dirty node. Layout only lays those ancestors out
+// again if the size of what they contain has changed (see layout.Layout).
+func (c *Spec) InvalidateLayout() {
+	if !c.isArranged {
+		// The ancestors of a node that needs layout are already marked.
+		return
+	}
+	c.isArranged = false
+	c.isLayoutClean = false
+	if c.parent == nil {
+		return
+	}
+	parent, ok := specOf(c.parent)
+	if !ok {
+		c.parent.InvalidateLayout()
+		return
+	}
+	parent.isArranged = false
+	parent.isLayoutClean = false
+	for ancestor := parent.parent; ancestor != nil; ancestor = ancestor.Parent() {
+		s, ok := specOf(ancestor)
+		if !ok {
+			ancestor.InvalidateLayout()
+			return
+		}
+		if !s.isLayoutClean {
+			// The ancestors of a dirty node are already marked.
+			return
+		}
+		s.isLayoutClean = false
+	}
+}
+
+// layoutSetting is a value that was given to a property that affects layout.
+type layoutSetting struct {
+	property string
+	value    interface{}
+}
+
+// InvalidateLayoutFor is called by the setters of properties that affect
+// layout with the property name and its new value, which must be
+// comparable. Until this node is first laid out, each value is recorded,
+// so that the Reconciler can tell whether a newly rendered node declares
+// the same layout as the node that it replaces. It then calls
+// InvalidateLayout.
+func (c *Spec) InvalidateLayoutFor(property string, value interface{}) {
+	if !c.isLaidOut {
+		c.layoutSettings = append(c.layoutSettings, layoutSetting{property, value})
+	}
+	c.InvalidateLayout()
+}
+
 func (c *Spec) IsMeasured() bool {
 	return c.isMeasured
 }
@@ -159,6 +313,7 @@ func (c *Spec) Gutter() float64 {
 
 func (c *Spec) SetIsMeasured(measured bool) {
 	c.isMeasured = measured
+	c.InvalidateLayoutFor("isMeasured", measured)
 }
 
 func (c *Spec) SetX(x float64) {
@@ -177,6 +332,12 @@ func (c *Spec) SetTextY(y float64) {
 	c.textY = y
 }
 
+// TextOffset returns the values that were provided to SetTextX and
+// SetTextY, which TextX and TextY subtract from the padded position.
+func (c *Spec) TextOffset() (x, y float64) {
+	return c.textX, c.textY
+}
+
 func (c *Spec) TextX() float64 {
 	return (c.X() + c.PaddingLeft()) - c.textX
 }
@@ -195,30 +356,72 @@ func (c *Spec) Y() float64 {
 
 func (c *Spec) SetHAlign(value Alignment) {
 	c.hAlign = value
+	c.InvalidateLayoutFor("hAlign", value)
 }
 
 func (c *Spec) HAlign() Alignment {
 	return c.hAlign
 }
 
+// SetAlignSelf overrides the alignment of the parent for this node. AlignLeft
+// and AlignRight apply horizontally, AlignTop and AlignBottom apply
+// vertically, and AlignCenter applies on both axes.
+func (c *Spec) SetAlignSelf(value Alignment) {
+	c.alignSelf = value
+	c.hasAlignSelf = true
+	c.InvalidateLayoutFor("alignSelf", value)
+}
+
+// AlignSelf returns the alignment that overrides the alignment of the parent
+// for this node. It is only meaningful when HasAlignSelf returns true.
+func (c *Spec) AlignSelf() Alignment {
+	return c.alignSelf
+}
+
+// HasAlignSelf returns true if SetAlignSelf was called for this node.
+func (c *Spec) HasAlignSelf() bool {
+	return c.hasAlignSelf
+}
+
+// SetJustify sets how a Flow layout distributes the remaining space between
+// the children of this node.
+func (c *Spec) SetJustify(value JustifyValue) {
+	c.justify = value
+	c.InvalidateLayoutFor("justify", value)
+}
+
+// Justify returns how a Flow layout distributes the remaining space between
+// the children of this node.
+func (c *Spec) Justify() JustifyValue {
+	return c.justify
+}
+
 func (c *Spec) VAlign() Alignment {
 	return c.vAlign
 }
 
 func (c *Spec) SetVAlign(value Alignment) {
 	c.vAlign = value
+	c.InvalidateLayoutFor("vAlign", value)
 }
 
 func (c *Spec) SetGutter(gutter float64) {
 	c.gutter = gutter
+	c.InvalidateLayoutFor("gutter", gutter)
 }
 
 func (c *Spec) SetWidth(w float64) {
-	c.width = w
+	if c.width != w {
+		c.width = w
+		c.InvalidateLayoutFor("width", w)
+	}
 }
 
 func (c *Spec) SetHeight(h float64) {
-	c.height = h
+	if c.height != h {
+		c.height = h
+		c.InvalidateLayoutFor("height", h)
+	}
 }
 
 func (c *Spec) ChildrenWidth() float64 {
@@ -293,20 +496,48 @@ func (c *Spec) Height() float64 {
 
 func (c *Spec) SetPrefWidth(value float64) {
 	c.prefWidth = value
+	c.InvalidateLayoutFor("prefWidth", value)
 }
 
 func (c *Spec) SetPrefHeight(value float64) {
 	c.prefHeight = value
+	c.InvalidateLayoutFor("prefHeight", value)
 }
 
+// PrefWidth returns the preferred width of this node, as a percentage of
+// the width inside the padding of its parent. Flow and Stack layouts apply
+// it to children that are not flexible horizontally.
 func (c *Spec) PrefWidth() float64 {
 	return c.prefWidth
 }
 
+// PrefHeight returns the preferred height of this node, as a percentage of
+// the height inside the padding of its parent. Flow and Stack layouts apply
+// it to children that are not flexible vertically.
 func (c *Spec) PrefHeight() float64 {
 	return c.prefHeight
 }
 
+// ScrollX is the horizontal distance that a scrolling container has moved
+// its content. This is runtime state and will be retained across renders.
+func (c *Spec) ScrollX() float64 {
+	return c.scrollX
+}
+
+// ScrollY is the vertical distance that a scrolling container has moved its
+// content. This is runtime state and will be retained across renders.
+func (c *Spec) ScrollY() float64 {
+	return c.scrollY
+}
+
+func (c *Spec) SetScrollX(value float64) {
+	c.scrollX = value
+}
+
+func (c *Spec) SetScrollY(value float64) {
+	c.scrollY = value
+}
+
 func (c *Spec) SetActualWidth(width float64) {
 	c.actualWidth = width
 }
@@ -317,14 +548,17 @@ func (c *Spec) SetActualHeight(height float64) {
 
 func (c *Spec) SetExcludeFromLayout(value bool) {
 	c.excludeFromLayout = value
+	c.InvalidateLayoutFor("excludeFromLayout", value)
 }
 
 func (c *Spec) SetMinWidth(min float64) {
 	c.minWidth = min
+	c.InvalidateLayoutFor("minWidth", min)
 }
 
 func (c *Spec) SetMinHeight(min float64) {
 	c.minHeight = min
+	c.InvalidateLayoutFor("minHeight", min)
 }
 
 func (c *Spec) MinWidth() float64 {
@@ -337,10 +571,12 @@ func (c *Spec) MinHeight() float64 {
 
 func (c *Spec) SetMaxWidth(max float64) {
 	c.maxWidth = max
+	c.InvalidateLayoutFor("maxWidth", max)
 }
 
 func (c *Spec) SetMaxHeight(max float64) {
 	c.maxHeight = max
+	c.InvalidateLayoutFor("maxHeight", max)
 }
 
 func (c *Spec) MaxWidth() float64 {
@@ -357,10 +593,12 @@ func (c *Spec) ExcludeFromLayout() bool {
 
 func (c *Spec) SetFlexWidth(value float64) {
 	c.flexWidth = value
+	c.InvalidateLayoutFor("flexWidth", value)
 }
 
 func (c *Spec) SetFlexHeight(value float64) {
 	c.flexHeight = value
+	c.InvalidateLayoutFor("flexHeight", value)
 }
 
 func (c *Spec) FlexWidth() float64 {
@@ -376,22 +614,27 @@ func (c *Spec) SetPadding(value float64) {
 	c.paddingLeft = value
 	c.paddingRight = value
 	c.paddingTop = value
+	c.InvalidateLayoutFor("padding", value)
 }
 
 func (c *Spec) SetPaddingBottom(value float64) {
 	c.paddingBottom = value
+	c.InvalidateLayoutFor("paddingBottom", value)
 }
 
 func (c *Spec) SetPaddingLeft(value float64) {
 	c.paddingLeft = value
+	c.InvalidateLayoutFor("paddingLeft", value)
 }
 
 func (c *Spec) SetPaddingRight(value float64) {
 	c.paddingRight = value
+	c.InvalidateLayoutFor("paddingRight", value)
 }
 
 func (c *Spec) SetPaddingTop(value float64) {
 	c.paddingTop = value
+	c.InvalidateLayoutFor("paddingTop", value)
 }
 
 func (c *Spec) HorizontalPadding() float64 {
@@ -418,6 +661,77 @@ func (c *Spec) PaddingTop() float64 {
 	return c.paddingTop
 }
 
+// SetAspectRatio sets the ratio of width to height that layouts will
+// maintain for this node.
+func (c *Spec) SetAspectRatio(ratio float64) {
+	c.aspectRatio = ratio
+	c.InvalidateLayoutFor("aspectRatio", ratio)
+}
+
+// AspectRatio returns the ratio of width to height that layouts will
+// maintain for this node, or zero if there is none.
+//
+// Unless this node is flexible vertically, layouts derive its height from
+// its width. The width is only derived from the height when the node has
+// no width.
+func (c *Spec) AspectRatio() float64 {
+	return c.aspectRatio
+}
+
+// SetMargin sets the space that layouts will keep clear around all four
+// sides of this node.
+func (c *Spec) SetMargin(value float64) {
+	c.marginBottom = value
+	c.marginLeft = value
+	c.marginRight = value
+	c.marginTop = value
+	c.InvalidateLayoutFor("margin", value)
+}
+
+func (c *Spec) SetMarginBottom(value float64) {
+	c.marginBottom = value
+	c.InvalidateLayoutFor("marginBottom", value)
+}
+
+func (c *Spec) SetMarginLeft(value float64) {
+	c.marginLeft = value
+	c.InvalidateLayoutFor("marginLeft", value)
+}
+
+func (c *Spec) SetMarginRight(value float64) {
+	c.marginRight = value
+	c.InvalidateLayoutFor("marginRight", value)
+}
+
+func (c *Spec) SetMarginTop(value float64) {
+	c.marginTop = value
+	c.InvalidateLayoutFor("marginTop", value)
+}
+
+func (c *Spec) HorizontalMargin() float64 {
+	return c.MarginLeft() + c.MarginRight()
+}
+
+func (c *Spec) VerticalMargin() float64 {
+	return c.MarginTop() + c.MarginBottom()
+}
+
+func (c *Spec) MarginBottom() float64 {
+	return c.marginBottom
+}
+
+func (c *Spec) MarginLeft() float64 {
+	return c.marginLeft
+}
+
+func (c *Spec) MarginRight() float64 {
+	return c.marginRight
+}
+
+func (c *Spec) MarginTop() float64 {
+	return c.marginTop
+}
+
 func (c *Spec) YOffset() float64 {
 	offset := c.Y()
 	parent := c.Parent()
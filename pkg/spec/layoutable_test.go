@@ -4,7 +4,9 @@ import (
 	"testing"
 
 	"github.com/waybeams/assert"
+	surface "github.com/waybeams/waybeams/pkg/env/fake"
 	"github.com/waybeams/waybeams/pkg/fakes"
+	"github.com/waybeams/waybeams/pkg/layout"
 	"github.com/waybeams/waybeams/pkg/opts"
 	"github.com/waybeams/waybeams/pkg/spec"
 )
@@ -73,6 +75,59 @@ func TestLayoutable(t *testing.T) {
 		assert.Equal(ctrl.PrefWidth(), 200)
 	})
 
+	t.Run("Layout dirtiness", func(t *testing.T) {
+		var createTree = func() spec.ReadWriter {
+			root := fakes.Fake(opts.Key("root"),
+				opts.Child(fakes.Fake(opts.Key("parent"),
+					opts.Child(fakes.Fake(opts.Key("child"), opts.Width(10))),
+				)),
+				opts.Child(fakes.Fake(opts.Key("sibling"))),
+			)
+			return layout.Layout(root, surface.NewSurface())
+		}
+
+		t.Run("New nodes are dirty", func(t *testing.T) {
+			assert.True(fakes.Fake().IsLayoutDirty())
+		})
+
+		t.Run("Setters mark ancestors dirty", func(t *testing.T) {
+			root := createTree()
+			spec.FirstByKey(root, "child").SetPaddingLeft(5)
+			assert.True(spec.FirstByKey(root, "child").IsLayoutDirty())
+			assert.True(spec.FirstByKey(root, "parent").IsLayoutDirty())
+			assert.True(root.IsLayoutDirty())
+			assert.False(spec.FirstByKey(root, "sibling").IsLayoutDirty())
+		})
+
+		t.Run("Setters only mark the parent as needing layout", func(t *testing.T) {
+			root := createTree()
+			spec.FirstByKey(root, "child").SetPaddingLeft(5)
+			assert.True(spec.FirstByKey(root, "child").NeedsLayout())
+			assert.True(spec.FirstByKey(root, "parent").NeedsLayout())
+			assert.False(root.NeedsLayout())
+		})
+
+		t.Run("Unchanged sizes do not mark dirty", func(t *testing.T) {
+			root := createTree()
+			child := spec.FirstByKey(root, "child")
+			child.SetWidth(10)
+			child.SetText("")
+			assert.False(root.IsLayoutDirty())
+			child.SetWidth(20)
+			assert.True(root.IsLayoutDirty())
+		})
+
+		t.Run("Output properties do not mark dirty", func(t *testing.T) {
+			root := createTree()
+			child := spec.FirstByKey(root, "child")
+			child.SetX(10)
+			child.SetContentWidth(30)
+			child.SetChildrenHeight(40)
+			child.SetBgColor(0xff0000ff)
+			assert.False(root.IsLayoutDirty())
+		})
+	})
+
 	/*
 		// These should only work after applying Stack or Flow Layouts!
 		// Specs should not be this smart.
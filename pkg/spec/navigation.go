@@ -0,0 +1,99 @@
+package spec
+
+import "math"
+
+// FocusDirection is a direction that arrow keys move focus in.
+type FocusDirection string
+
+const (
+	FocusDown  FocusDirection = "Down"
+	FocusLeft  FocusDirection = "Left"
+	FocusRight FocusDirection = "Right"
+	FocusUp    FocusDirection = "Up"
+)
+
+// neighborCrossWeight is how much more distance across the direction of a
+// move counts than distance along it, so that nodes in the same row or
+// column are preferred over nearer diagonal nodes.
+const neighborCrossWeight = 2.0
+
+// NeighborFocusable returns the node that arrow keys move focus to from the
+// provided focused node in the provided direction, or nil if there is none.
+// The Neighbor of the focused node is returned if it names a shown, focusable
+// node. Otherwise the nearest node of the FocusOrder that lies in the
+// direction is returned, comparing the global bounds of the laid out nodes.
+// With nothing focused, the first node of the FocusOrder is returned.
+func NeighborFocusable(root, focused ReadWriter, direction FocusDirection) ReadWriter {
+	order := FocusOrder(FocusScope(root, focused))
+	if focused == nil {
+		if len(order) == 0 {
+			return nil
+		}
+		return order[0]
+	}
+	if key := focused.Neighbor(direction); key != "" {
+		neighbor := FirstByKey(root, key)
+		if neighbor != nil && neighbor.IsFocusable() && IsShown(neighbor) {
+			return neighbor
+		}
+	}
+
+	var result ReadWriter
+	bestScore := math.Inf(1)
+	from := globalBounds(focused)
+	for _, candidate := range order {
+		if candidate == focused {
+			continue
+		}
+		score, ok := neighborScore(from, globalBounds(candidate), direction)
+		if ok && score < bestScore {
+			result = candidate
+			bestScore = score
+		}
+	}
+	return result
+}
+
+// bounds is a rectangle in global coordinates.
+type bounds struct {
+	left, top, right, bottom float64
+}
+
+func globalBounds(r Reader) bounds {
+	x, y := LocalToGlobal(r, 0, 0)
+	return bounds{left: x, top: y, right: x + r.Width(), bottom: y + r.Height()}
+}
+
+// neighborScore returns how far the provided candidate is from the provided
+// focused bounds in the provided direction, and false if the center of the
+// candidate is not beyond the center of the focused bounds in that
+// direction.
+func neighborScore(from, to bounds, direction FocusDirection) (float64, bool) {
+	var along, beyond, cross float64
+	switch direction {
+	case FocusDown:
+		along, beyond = to.top-from.bottom, (to.top+to.bottom)-(from.top+from.bottom)
+		cross = rangeGap(from.left, from.right, to.left, to.right)
+	case FocusUp:
+		along, beyond = from.top-to.bottom, (from.top+from.bottom)-(to.top+to.bottom)
+		cross = rangeGap(from.left, from.right, to.left, to.right)
+	case FocusRight:
+		along, beyond = to.left-from.right, (to.left+to.right)-(from.left+from.right)
+		cross = rangeGap(from.top, from.bottom, to.top, to.bottom)
+	case FocusLeft:
+		along, beyond = from.left-to.right, (from.left+from.right)-(to.left+to.right)
+		cross = rangeGap(from.top, from.bottom, to.top, to.bottom)
+	default:
+		return 0, false
+	}
+	if beyond <= 0 {
+		return 0, false
+	}
+	return math.Max(0, along) + cross*neighborCrossWeight, true
+}
+
+// rangeGap returns the distance between two ranges on one axis, or zero if
+// they overlap.
+func rangeGap(firstStart, firstEnd, secondStart, secondEnd float64) float64 {
+	return math.Max(0, math.Max(secondStart-firstEnd, firstStart-secondEnd))
+}
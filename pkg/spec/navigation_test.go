@@ -0,0 +1,86 @@
+package spec_test
+
+import (
+	"testing"
+
+	"github.com/waybeams/assert"
+	"github.com/waybeams/waybeams/pkg/ctrl"
+	surface "github.com/waybeams/waybeams/pkg/env/fake"
+	"github.com/waybeams/waybeams/pkg/layout"
+	"github.com/waybeams/waybeams/pkg/opts"
+	"github.com/waybeams/waybeams/pkg/spec"
+)
+
+func TestNavigation(t *testing.T) {
+	var tile = func(key string, options ...spec.Option) spec.Option {
+		defaults := []spec.Option{
+			opts.Key(key),
+			opts.IsFocusable(true),
+			opts.Width(20),
+			opts.Height(20),
+		}
+		return opts.Child(ctrl.Box(append(defaults, options...)...))
+	}
+
+	// Lays out a grid of tiles, where "wide" spans the first two columns:
+	//
+	//   a b c
+	//   wide  d
+	var create = func(options ...spec.Option) spec.ReadWriter {
+		return layout.Layout(ctrl.VBox(
+			opts.Width(100),
+			opts.Height(100),
+			opts.HAlign(spec.AlignLeft),
+			opts.Gutter(10),
+			opts.Child(ctrl.HBox(
+				opts.Gutter(10),
+				tile("a", options...),
+				tile("b"),
+				tile("c"),
+			)),
+			opts.Child(ctrl.HBox(
+				opts.Gutter(10),
+				tile("wide", opts.Width(50)),
+				tile("d"),
+			)),
+		), surface.NewSurface())
+	}
+
+	var move = func(root spec.ReadWriter, key string, direction spec.FocusDirection) string {
+		next := spec.NeighborFocusable(root, spec.FirstByKey(root, key), direction)
+		if next == nil {
+			return ""
+		}
+		return next.Key()
+	}
+
+	t.Run("Moves to the nearest node in a direction", func(t *testing.T) {
+		root := create()
+		assert.Equal(move(root, "a", spec.FocusRight), "b")
+		assert.Equal(move(root, "b", spec.FocusRight), "c")
+		assert.Equal(move(root, "c", spec.FocusLeft), "b")
+		assert.Equal(move(root, "a", spec.FocusDown), "wide")
+		assert.Equal(move(root, "b", spec.FocusDown), "wide")
+		assert.Equal(move(root, "c", spec.FocusDown), "d")
+		assert.Equal(move(root, "d", spec.FocusUp), "c")
+		assert.Equal(move(root, "d", spec.FocusLeft), "wide")
+	})
+
+	t.Run("Does not move beyond the edge", func(t *testing.T) {
+		root := create()
+		assert.Equal(move(root, "a", spec.FocusLeft), "")
+		assert.Equal(move(root, "a", spec.FocusUp), "")
+		assert.Equal(move(root, "d", spec.FocusDown), "")
+	})
+
+	t.Run("Moves to the first node with nothing focused", func(t *testing.T) {
+		root := create()
+		assert.Equal(spec.NeighborFocusable(root, nil, spec.FocusDown).Key(), "a")
+	})
+
+	t.Run("Moves to an explicit neighbor", func(t *testing.T) {
+		root := create(opts.Neighbor(spec.FocusLeft, "d"), opts.Neighbor(spec.FocusDown, "missing"))
+		assert.Equal(move(root, "a", spec.FocusLeft), "d")
+		assert.Equal(move(root, "a", spec.FocusDown), "wide", "falls back to the nearest")
+	})
+}
@@ -82,6 +82,36 @@ func (s *OffsetSurface) Stroke() {
 	s.delegateTo.Stroke()
 }
 
+// Scissor limits all further drawing to the provided local rectangle.
+func (s *OffsetSurface) Scissor(x, y, width, height float64) {
+	x += s.offsetX
+	y += s.offsetY
+	s.delegateTo.Scissor(x, y, width, height)
+}
+
+// ResetScissor removes the scissor so that drawing is no longer limited.
+func (s *OffsetSurface) ResetScissor() {
+	s.delegateTo.ResetScissor()
+}
+
+// IntersectScissor limits all further drawing to the intersection of the
+// current scissor and the provided local rectangle.
+func (s *OffsetSurface) IntersectScissor(x, y, width, height float64) {
+	x += s.offsetX
+	y += s.offsetY
+	s.delegateTo.IntersectScissor(x, y, width, height)
+}
+
+// Save pushes the current drawing state onto a stack.
+func (s *OffsetSurface) Save() {
+	s.delegateTo.Save()
+}
+
+// Restore pops the drawing state that was pushed by the matching Save.
+func (s *OffsetSurface) Restore() {
+	s.delegateTo.Restore()
+}
+
 // GetOffsetSurfaceFor provides offset surface for nested control so that
 // they can use local coordinates for positioning.
 func (s *OffsetSurface) GetOffsetSurfaceFor(r Reader) Surface {
@@ -0,0 +1,292 @@
+package spec
+
+import "github.com/waybeams/waybeams/pkg/events"
+
+// Retainer is implemented by Specs that hold runtime state beyond what the
+// Spec struct tracks (e.g., a text caret). RetainFrom is called on each node
+// of a new tree with the node from the previous tree that it replaced.
+type Retainer interface {
+	RetainFrom(previous ReadWriter)
+}
+
+// declaration is what a factory produced for a node, before any runtime
+// state was carried over from the previous tree.
+type declaration struct {
+	fontFace string
+	fontSize float64
+	handlers map[int64]bool
+	layout   []layoutSetting
+	state    string
+}
+
+// hasSameLayout returns true if both declarations set the same properties
+// that affect layout, to the same values and in the same order. Inherited
+// font properties are compared by the values that the nodes resolved.
+func (d declaration) hasSameLayout(other declaration) bool {
+	if d.fontFace != other.fontFace || d.fontSize != other.fontSize || len(d.layout) != len(other.layout) {
+		return false
+	}
+	for index, setting := range d.layout {
+		if setting != other.layout[index] {
+			return false
+		}
+	}
+	return true
+}
+
+// specOf returns the Spec that the provided node embeds.
+func specOf(node Reader) (*Spec, bool) {
+	embedder, ok := node.(interface{ embeddedSpec() *Spec })
+	if !ok {
+		return nil, false
+	}
+	return embedder.embeddedSpec(), true
+}
+
+func (c *Spec) embeddedSpec() *Spec {
+	return c
+}
+
+// retainLayoutFrom copies the results of the previous layout of a node that
+// has the same layout declaration.
+func (c *Spec) retainLayoutFrom(previous *Spec) {
+	c.actualHeight = previous.actualHeight
+	c.actualWidth = previous.actualWidth
+	c.childrenHeight = previous.childrenHeight
+	c.childrenWidth = previous.childrenWidth
+	c.contentHeight = previous.contentHeight
+	c.contentWidth = previous.contentWidth
+	c.height = previous.height
+	c.textX = previous.textX
+	c.textY = previous.textY
+	c.width = previous.width
+	c.x = previous.x
+	c.y = previous.y
+	c.SetIsLayoutDirty(false)
+}
+
+// Reconciler matches the nodes of each newly rendered Spec tree to the nodes
+// of the previous tree and carries runtime state forward.
+//
+// Nodes are matched to their previous instance when they share a parent
+// match, a SpecName and either a Key or, for nodes without a Key, a position
+// among their unkeyed siblings. Matched nodes receive the previous current
+// state (unless the factory declared a different one), scroll offsets,
+// focus and any event handlers that were subscribed after render. Nodes
+// without a match receive an events.Added event, and previous nodes that
+// were not matched receive an events.Removed event.
+//
+// Matched subtrees that declare the same layout properties, and that were
+// not changed since they were laid out, also keep their previous layout, so
+// that the next layout.Layout only visits what has changed.
+type Reconciler struct {
+	declarations map[Reader]declaration
+	previous     ReadWriter
+}
+
+// Previous returns the most recently reconciled tree.
+func (r *Reconciler) Previous() ReadWriter {
+	return r.previous
+}
+
+// Reconcile carries runtime state from the previously reconciled tree into
+// the provided tree, and returns the provided tree.
+func (r *Reconciler) Reconcile(next ReadWriter) ReadWriter {
+	previous := r.previous
+	declarations := make(map[Reader]declaration)
+	matches := make(map[Reader]ReadWriter)
+
+	if previous != nil && previous.SpecName() == next.SpecName() {
+		r.reconcileNode(previous, next, declarations, matches)
+	} else {
+		if previous != nil {
+			removeNode(previous)
+		}
+		r.addNode(next, declarations)
+	}
+
+	if previous != nil {
+		focused := previous.FocusedSpec()
+		if focused != nil {
+			if match, ok := matches[focused]; ok && IsShown(match) {
+				next.SetFocusedSpec(match)
+			}
+		}
+	}
+
+	r.declarations = declarations
+	r.previous = next
+	return next
+}
+
+func (r *Reconciler) declarationFor(node ReadWriter) declaration {
+	result := declaration{
+		fontFace: node.FontFace(),
+		fontSize: node.FontSize(),
+		handlers: make(map[int64]bool),
+		state:    node.State(),
+	}
+	for _, subscription := range node.Subscriptions() {
+		result.handlers[subscription.ID] = true
+	}
+	if c, ok := specOf(node); ok {
+		result.layout = c.layoutSettings
+	}
+	return result
+}
+
+func (r *Reconciler) declare(node ReadWriter, declarations map[Reader]declaration) {
+	declarations[node] = r.declarationFor(node)
+}
+
+func (r *Reconciler) addNode(node ReadWriter, declarations map[Reader]declaration) {
+	r.declare(node, declarations)
+	node.Emit(events.New(events.Added, node, nil))
+	for _, child := range node.Children() {
+		r.addNode(child, declarations)
+	}
+}
+
+func removeNode(node ReadWriter) {
+	for _, child := range node.Children() {
+		removeNode(child)
+	}
+	node.Emit(events.New(events.Removed, node, nil))
+	node.UnsubAll()
+}
+
+func (r *Reconciler) reconcileNode(previous, next ReadWriter, declarations map[Reader]declaration, matches map[Reader]ReadWriter) {
+	r.declare(next, declarations)
+	matches[previous] = next
+	r.retain(previous, next, declarations)
+
+	previousChildren := previous.Children()
+	matched := make([]bool, len(previousChildren))
+	unkeyedIndex := 0
+	// A node keeps its arrangement when each of its children was matched at
+	// the same index and kept its own layout. Children that only contain
+	// changes further down are laid out in place (see layout.Layout), and
+	// only rearranged if their size changes.
+	isArranged := !next.NeedsLayout() && len(previousChildren) == next.ChildCount()
+	isClean := isArranged
+
+	for position, child := range next.Children() {
+		index := -1
+		if child.Key() != "" {
+			index = indexByKey(previousChildren, matched, child.Key())
+		} else {
+			index = indexByUnkeyedPosition(previousChildren, matched, unkeyedIndex)
+			unkeyedIndex++
+		}
+
+		if index > -1 && previousChildren[index].SpecName() == child.SpecName() {
+			matched[index] = true
+			r.reconcileNode(previousChildren[index], child, declarations, matches)
+			isArranged = isArranged && index == position && !child.NeedsLayout()
+			isClean = isClean && !child.IsLayoutDirty()
+		} else {
+			r.addNode(child, declarations)
+			isArranged = false
+		}
+	}
+
+	if !isArranged {
+		next.SetIsLayoutDirty(true)
+	} else if !isClean {
+		containsDirtyLayout(next)
+	}
+
+	for index, child := range previousChildren {
+		if !matched[index] {
+			removeNode(child)
+		}
+	}
+}
+
+// containsDirtyLayout marks the provided node as containing nodes that need
+// layout, without marking it as needing layout itself.
+func containsDirtyLayout(node ReadWriter) {
+	if c, ok := specOf(node); ok {
+		c.isLayoutClean = false
+	} else {
+		node.SetIsLayoutDirty(true)
+	}
+}
+
+// retain copies runtime state from the previous instance of a node.
+func (r *Reconciler) retain(previous, next ReadWriter, declarations map[Reader]declaration) {
+	nextDeclaration := declarations[next]
+	previousDeclaration, ok := r.declarations[previous]
+	if !ok {
+		previousDeclaration = r.declarationFor(previous)
+	}
+
+	// Only carry the previous state forward if the factory declared the same
+	// state both times. Otherwise, the model has changed and wins.
+	previousState := previous.State()
+	if previousDeclaration.state == nextDeclaration.state && previousState != next.State() {
+		if previousState == "" || next.HasState(previousState) {
+			next.SetState(previousState)
+			applyOptionsForState(next)
+		}
+	}
+
+	// Compare layout properties once the retained state has been applied,
+	// which is also how the previous node was laid out.
+	nextSpec, isNextSpec := specOf(next)
+	previousSpec, isPreviousSpec := specOf(previous)
+	if isNextSpec && isPreviousSpec {
+		nextDeclaration.layout = nextSpec.layoutSettings
+		declarations[next] = nextDeclaration
+		if ok && !previous.IsLayoutDirty() && previousDeclaration.hasSameLayout(nextDeclaration) {
+			nextSpec.retainLayoutFrom(previousSpec)
+		}
+	}
+
+	// The DisplayList is only replayed if the node still looks the same.
+	next.SetDisplayList(previous.DisplayList())
+	next.SetScrollX(previous.ScrollX())
+	next.SetScrollY(previous.ScrollY())
+
+	// Handlers that the factory did not declare were added at runtime.
+	for _, subscription := range previous.Subscriptions() {
+		if !previousDeclaration.handlers[subscription.ID] {
+			next.On(subscription.EventName, subscription.Handler)
+		}
+	}
+
+	if retainer, ok := next.(Retainer); ok {
+		retainer.RetainFrom(previous)
+	}
+}
+
+func indexByKey(children []ReadWriter, matched []bool, key string) int {
+	for index, child := range children {
+		if !matched[index] && child.Key() == key {
+			return index
+		}
+	}
+	return -1
+}
+
+func indexByUnkeyedPosition(children []ReadWriter, matched []bool, position int) int {
+	unkeyedIndex := 0
+	for index, child := range children {
+		if child.Key() != "" {
+			continue
+		}
+		if unkeyedIndex == position {
+			if matched[index] {
+				return -1
+			}
+			return index
+		}
+		unkeyedIndex++
+	}
+	return -1
+}
+
+// NewReconciler creates a Reconciler without a previous tree.
+func NewReconciler() *Reconciler {
+	return &Reconciler{}
+}
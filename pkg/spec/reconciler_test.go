@@ -0,0 +1,227 @@
+package spec_test
+
+import (
+	"testing"
+
+	"github.com/waybeams/assert"
+	"github.com/waybeams/waybeams/pkg/ctrl"
+	surface "github.com/waybeams/waybeams/pkg/env/fake"
+	"github.com/waybeams/waybeams/pkg/events"
+	"github.com/waybeams/waybeams/pkg/layout"
+	"github.com/waybeams/waybeams/pkg/opts"
+	"github.com/waybeams/waybeams/pkg/spec"
+)
+
+func TestReconciler(t *testing.T) {
+	var createTree = func(children ...spec.Option) spec.ReadWriter {
+		return ctrl.VBox(append([]spec.Option{opts.Key("root")}, children...)...)
+	}
+
+	t.Run("Instantiable", func(t *testing.T) {
+		r := spec.NewReconciler()
+		assert.Nil(r.Previous())
+	})
+
+	t.Run("Returns the provided tree", func(t *testing.T) {
+		r := spec.NewReconciler()
+		tree := createTree()
+		assert.Equal(r.Reconcile(tree), tree)
+		assert.Equal(r.Previous(), tree)
+	})
+
+	t.Run("Retains current state", func(t *testing.T) {
+		r := spec.NewReconciler()
+		first := r.Reconcile(createTree(opts.Child(ctrl.Button(opts.Key("one")))))
+		spec.FirstByKey(first, "one").SetState("hovered")
+
+		second := r.Reconcile(createTree(opts.Child(ctrl.Button(opts.Key("one")))))
+		button := spec.FirstByKey(second, "one")
+		assert.Equal(button.State(), "hovered")
+		assert.Equal(button.BgColor(), uint(0x00acd7ff))
+	})
+
+	t.Run("Declared state changes win", func(t *testing.T) {
+		r := spec.NewReconciler()
+		first := r.Reconcile(createTree(opts.Child(ctrl.Button(opts.Key("one")))))
+		spec.FirstByKey(first, "one").SetState("hovered")
+
+		second := r.Reconcile(createTree(opts.Child(ctrl.Button(opts.Key("one"), opts.IsDisabled(true)))))
+		assert.Equal(spec.FirstByKey(second, "one").State(), "disabled")
+	})
+
+	t.Run("Retains scroll offsets", func(t *testing.T) {
+		r := spec.NewReconciler()
+		first := r.Reconcile(createTree())
+		first.SetScrollX(10)
+		first.SetScrollY(20)
+
+		second := r.Reconcile(createTree())
+		assert.Equal(second.ScrollX(), 10.0)
+		assert.Equal(second.ScrollY(), 20.0)
+	})
+
+	t.Run("Retains focus", func(t *testing.T) {
+		r := spec.NewReconciler()
+		first := r.Reconcile(createTree(
+			opts.Child(ctrl.TextInput(opts.Key("name"))),
+			opts.Child(ctrl.TextInput(opts.Key("email"))),
+		))
+		email := spec.FirstByKey(first, "email")
+		email.SetFocusedSpec(email)
+
+		second := r.Reconcile(createTree(
+			opts.Child(ctrl.TextInput(opts.Key("name"))),
+			opts.Child(ctrl.TextInput(opts.Key("email"))),
+		))
+		focused := second.FocusedSpec()
+		assert.NotNil(focused)
+		assert.Equal(focused, spec.FirstByKey(second, "email"))
+	})
+
+	t.Run("Matches keyed children after reorder", func(t *testing.T) {
+		r := spec.NewReconciler()
+		first := r.Reconcile(createTree(
+			opts.Child(ctrl.Button(opts.Key("one"))),
+			opts.Child(ctrl.Button(opts.Key("two"))),
+		))
+		spec.FirstByKey(first, "two").SetState("pressed")
+
+		second := r.Reconcile(createTree(
+			opts.Child(ctrl.Button(opts.Key("two"))),
+			opts.Child(ctrl.Button(opts.Key("one"))),
+		))
+		assert.Equal(spec.FirstByKey(second, "two").State(), "pressed")
+		assert.Equal(spec.FirstByKey(second, "one").State(), "active")
+	})
+
+	t.Run("Matches unkeyed children by position and SpecName", func(t *testing.T) {
+		r := spec.NewReconciler()
+		first := r.Reconcile(createTree(opts.Child(ctrl.Button())))
+		first.ChildAt(0).SetState("hovered")
+
+		second := r.Reconcile(createTree(opts.Child(ctrl.Label())))
+		assert.Equal(second.ChildAt(0).State(), "")
+	})
+
+	t.Run("Carries handlers added after render", func(t *testing.T) {
+		r := spec.NewReconciler()
+		first := r.Reconcile(createTree())
+		calls := 0
+		first.On("Custom", func(e events.Event) {
+			calls++
+		})
+
+		second := r.Reconcile(createTree())
+		third := r.Reconcile(createTree())
+		third.Emit(events.New("Custom", third, nil))
+		assert.Equal(calls, 1)
+		assert.Equal(len(second.Subscriptions()), len(third.Subscriptions()))
+	})
+
+	t.Run("Carries handlers added after a declared handler was removed", func(t *testing.T) {
+		r := spec.NewReconciler()
+		var unsubscribe events.Unsubscriber
+		var createSubscribedTree = func() spec.ReadWriter {
+			tree := createTree()
+			unsubscribe = tree.On("Declared", func(e events.Event) {})
+			return tree
+		}
+		first := r.Reconcile(createSubscribedTree())
+		unsubscribe()
+		calls := 0
+		first.On("Custom", func(e events.Event) {
+			calls++
+		})
+
+		second := r.Reconcile(createSubscribedTree())
+		second.Emit(events.New("Custom", second, nil))
+		assert.Equal(calls, 1)
+	})
+
+	t.Run("Emits Added and Removed", func(t *testing.T) {
+		r := spec.NewReconciler()
+		first := r.Reconcile(createTree(opts.Child(ctrl.Button(opts.Key("one")))))
+		removed := []string{}
+		spec.FirstByKey(first, "one").On(events.Removed, func(e events.Event) {
+			removed = append(removed, e.Target().(spec.Reader).Key())
+		})
+
+		added := []string{}
+		next := createTree(opts.Child(ctrl.Button(opts.Key("two"))))
+		spec.FirstByKey(next, "two").On(events.Added, func(e events.Event) {
+			added = append(added, e.Target().(spec.Reader).Key())
+		})
+		next.On(events.Added, func(e events.Event) {
+			added = append(added, "root")
+		})
+
+		r.Reconcile(next)
+		assert.Equal(len(removed), 1)
+		assert.Equal(removed[0], "one")
+		assert.Equal(len(added), 1)
+		assert.Equal(added[0], "two")
+	})
+
+	t.Run("Retains layout of unchanged nodes", func(t *testing.T) {
+		var create = func(text string) spec.ReadWriter {
+			return createTree(
+				opts.Width(200),
+				opts.Child(ctrl.HBox(
+					opts.Key("toolbar"),
+					opts.FlexWidth(1),
+					opts.Child(ctrl.Label(opts.Key("title"), opts.Text(text))),
+				)),
+				opts.Child(ctrl.Box(opts.Key("body"), opts.FlexWidth(1), opts.Height(50))),
+			)
+		}
+
+		r := spec.NewReconciler()
+		first := layout.Layout(r.Reconcile(create("abcd")), surface.NewSurface())
+		second := r.Reconcile(create("abcd"))
+		assert.False(second.IsLayoutDirty())
+		body := spec.FirstByKey(second, "body")
+		assert.Equal(body.Y(), spec.FirstByKey(first, "body").Y())
+		assert.Equal(body.Width(), 200)
+
+		layout.Layout(second, surface.NewSurface())
+		third := r.Reconcile(create("abcdef"))
+		assert.True(third.IsLayoutDirty())
+		assert.True(spec.FirstByKey(third, "toolbar").IsLayoutDirty())
+		assert.True(spec.FirstByKey(third, "title").IsLayoutDirty())
+		assert.False(spec.FirstByKey(third, "body").IsLayoutDirty())
+	})
+
+	t.Run("Does not retain layout of changed nodes", func(t *testing.T) {
+		r := spec.NewReconciler()
+		first := layout.Layout(r.Reconcile(createTree(opts.Child(ctrl.Button(opts.Key("one"))))), surface.NewSurface())
+		spec.FirstByKey(first, "one").SetPadding(20)
+
+		second := r.Reconcile(createTree(opts.Child(ctrl.Button(opts.Key("one")))))
+		assert.True(second.IsLayoutDirty())
+		assert.True(spec.FirstByKey(second, "one").IsLayoutDirty())
+	})
+
+	t.Run("Does not retain layout when an inherited font changes", func(t *testing.T) {
+		var create = func(size float64) spec.ReadWriter {
+			return createTree(
+				opts.FontSize(size),
+				opts.Child(ctrl.HBox(opts.Child(ctrl.Label(opts.Key("title"), opts.Text("abc"))))),
+			)
+		}
+		r := spec.NewReconciler()
+		layout.Layout(r.Reconcile(create(10)), surface.NewSurface())
+		second := r.Reconcile(create(40))
+		assert.True(spec.FirstByKey(second, "title").IsLayoutDirty())
+	})
+
+	t.Run("FirstByPath", func(t *testing.T) {
+		tree := createTree(opts.Child(ctrl.HBox(
+			opts.Key("toolbar"),
+			opts.Child(ctrl.Button(opts.Key("one"))),
+		)))
+		result := spec.FirstByPath(tree, "/root/toolbar/one")
+		assert.NotNil(result)
+		assert.Equal(result.Key(), "one")
+		assert.Nil(spec.FirstByPath(tree, "/root/other/one"))
+	})
+}
@@ -0,0 +1,472 @@
+package spec
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Specificity weights, applied per compound entry in a selector. These follow
+// the CSS ordering where a key (#id) outranks a state or structural pseudo
+// class, which in turn outranks a SpecName.
+const (
+	keySpecificity      = 10000
+	pseudoSpecificity   = 100
+	specNameSpecificity = 1
+)
+
+type combinator int
+
+const (
+	descendantCombinator combinator = iota
+	childCombinator
+)
+
+// nthExpression is the parsed form of an :nth-child(an+b) argument.
+type nthExpression struct {
+	a int
+	b int
+}
+
+func (n nthExpression) matches(index int) bool {
+	if n.a == 0 {
+		return index == n.b
+	}
+	diff := index - n.b
+	return diff%n.a == 0 && diff/n.a >= 0
+}
+
+// compoundSelector is a run of simple selectors without whitespace, like
+// "Button#save:hovered". The combinator describes how this compound relates
+// to the compound on its left.
+type compoundSelector struct {
+	combinator combinator
+	key        string
+	nthChild   []nthExpression
+	specName   string
+	states     []string
+}
+
+func (c *compoundSelector) specificity() int {
+	result := 0
+	if c.key != "" {
+		result += keySpecificity
+	}
+	result += (len(c.states) + len(c.nthChild)) * pseudoSpecificity
+	if c.specName != "" {
+		result += specNameSpecificity
+	}
+	return result
+}
+
+func (c *compoundSelector) matches(r Reader) bool {
+	if c.specName != "" && c.specName != r.SpecName() {
+		return false
+	}
+	if c.key != "" && c.key != r.Key() {
+		return false
+	}
+	for _, state := range c.states {
+		if !matchesState(r, state) {
+			return false
+		}
+	}
+	if len(c.nthChild) > 0 {
+		index := childIndex(r) + 1
+		if index == 0 {
+			return false
+		}
+		for _, nth := range c.nthChild {
+			if !nth.matches(index) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// complexSelector is a chain of compound selectors joined by combinators.
+type complexSelector struct {
+	parts []*compoundSelector
+}
+
+func (c *complexSelector) specificity() int {
+	result := 0
+	for _, part := range c.parts {
+		result += part.specificity()
+	}
+	return result
+}
+
+func (c *complexSelector) matches(r Reader) bool {
+	return matchParts(c.parts, len(c.parts)-1, r)
+}
+
+// Selector is a parsed, CSS-like query that can be matched against the nodes
+// of a Spec tree.
+//
+// Supported syntax includes SpecName type selectors (Button), the universal
+// selector (*), keys (#save), state pseudo classes (:hovered, :disabled, or
+// any other name given to Spec.OnState), :nth-child(an+b|odd|even),
+// descendant (A B) and child (A > B) combinators and selector lists (A, B).
+type Selector struct {
+	alternatives []*complexSelector
+	source       string
+}
+
+// Matches returns true if the provided node satisfies the selector.
+func (s *Selector) Matches(r Reader) bool {
+	return s.Specificity(r) > -1
+}
+
+// Specificity returns the specificity of the most specific alternative in
+// this selector that matches the provided node, or -1 if nothing matches.
+func (s *Selector) Specificity(r Reader) int {
+	result := -1
+	for _, alternative := range s.alternatives {
+		if alternative.matches(r) {
+			specificity := alternative.specificity()
+			if specificity > result {
+				result = specificity
+			}
+		}
+	}
+	return result
+}
+
+// String returns the source text that this selector was parsed from.
+func (s *Selector) String() string {
+	return s.source
+}
+
+// ParseSelector parses the provided source into a Selector, or returns an
+// error if the source is not a supported selector.
+func ParseSelector(source string) (*Selector, error) {
+	p := &selectorParser{input: []rune(source), source: source}
+	alternatives, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	return &Selector{alternatives: alternatives, source: source}, nil
+}
+
+// QuerySelector returns the first node, in depth-first order beginning with
+// the provided node, that matches the provided selector. Nil is returned
+// when nothing matches.
+//
+// Selectors are expected to be authored alongside the code that uses them,
+// so this function will panic if the selector cannot be parsed.
+func QuerySelector(r ReadWriter, selector string) ReadWriter {
+	return querySelector(r, mustParseSelector(selector))
+}
+
+// QuerySelectorAll returns every node, in depth-first order beginning with
+// the provided node, that matches the provided selector.
+//
+// This function will panic if the selector cannot be parsed.
+func QuerySelectorAll(r ReadWriter, selector string) []ReadWriter {
+	return querySelectorAll(r, mustParseSelector(selector), []ReadWriter{})
+}
+
+func mustParseSelector(source string) *Selector {
+	selector, err := ParseSelector(source)
+	if err != nil {
+		panic(err)
+	}
+	return selector
+}
+
+func querySelector(r ReadWriter, selector *Selector) ReadWriter {
+	if selector.Matches(r) {
+		return r
+	}
+	for _, child := range r.Children() {
+		result := querySelector(child, selector)
+		if result != nil {
+			return result
+		}
+	}
+	return nil
+}
+
+func querySelectorAll(r ReadWriter, selector *Selector, result []ReadWriter) []ReadWriter {
+	if selector.Matches(r) {
+		result = append(result, r)
+	}
+	for _, child := range r.Children() {
+		result = querySelectorAll(child, selector, result)
+	}
+	return result
+}
+
+func matchParts(parts []*compoundSelector, index int, r Reader) bool {
+	part := parts[index]
+	if !part.matches(r) {
+		return false
+	}
+	if index == 0 {
+		return true
+	}
+
+	var ancestor Reader = r.Parent()
+	if part.combinator == childCombinator {
+		return ancestor != nil && matchParts(parts, index-1, ancestor)
+	}
+
+	for ancestor != nil {
+		if matchParts(parts, index-1, ancestor) {
+			return true
+		}
+		ancestor = ancestor.Parent()
+	}
+	return false
+}
+
+func matchesState(r Reader, state string) bool {
+	if r.State() == state {
+		return true
+	}
+	// Focus is tracked on the root rather than as a state on the focused
+	// node, so we check for it explicitly.
+	if state == "focused" {
+		focused := r.FocusedSpec()
+		return focused != nil && Reader(focused) == r
+	}
+	return false
+}
+
+// childIndex returns the zero-based index of the provided node within its
+// parent's children, or -1 if it has no parent.
+func childIndex(r Reader) int {
+	parent := r.Parent()
+	if parent == nil {
+		return -1
+	}
+	for index, child := range parent.Children() {
+		if Reader(child) == r {
+			return index
+		}
+	}
+	return -1
+}
+
+type selectorParser struct {
+	input    []rune
+	position int
+	source   string
+}
+
+func (p *selectorParser) errorf(reason string) error {
+	return errors.New("invalid selector \"" + p.source + "\": " + reason)
+}
+
+func (p *selectorParser) done() bool {
+	return p.position >= len(p.input)
+}
+
+func (p *selectorParser) peek() rune {
+	return p.input[p.position]
+}
+
+func (p *selectorParser) skipWhitespace() bool {
+	skipped := false
+	for !p.done() && isSelectorWhitespace(p.peek()) {
+		p.position++
+		skipped = true
+	}
+	return skipped
+}
+
+func (p *selectorParser) parse() ([]*complexSelector, error) {
+	alternatives := []*complexSelector{}
+	for {
+		complex, err := p.parseComplex()
+		if err != nil {
+			return nil, err
+		}
+		alternatives = append(alternatives, complex)
+		if p.done() {
+			return alternatives, nil
+		}
+		// parseComplex only stops early on a comma.
+		p.position++
+	}
+}
+
+func (p *selectorParser) parseComplex() (*complexSelector, error) {
+	result := &complexSelector{}
+	nextCombinator := descendantCombinator
+
+	p.skipWhitespace()
+	for {
+		compound, err := p.parseCompound()
+		if err != nil {
+			return nil, err
+		}
+		compound.combinator = nextCombinator
+		result.parts = append(result.parts, compound)
+
+		hadWhitespace := p.skipWhitespace()
+		if p.done() || p.peek() == ',' {
+			return result, nil
+		}
+
+		if p.peek() == '>' {
+			p.position++
+			p.skipWhitespace()
+			nextCombinator = childCombinator
+		} else if hadWhitespace {
+			nextCombinator = descendantCombinator
+		} else {
+			return nil, p.errorf("unexpected '" + string(p.peek()) + "'")
+		}
+	}
+}
+
+func (p *selectorParser) parseCompound() (*compoundSelector, error) {
+	result := &compoundSelector{}
+	start := p.position
+
+	if !p.done() && p.peek() == '*' {
+		p.position++
+	} else if !p.done() && !isSelectorDelimiter(p.peek()) {
+		result.specName = p.parseIdent()
+	}
+
+	for !p.done() {
+		switch p.peek() {
+		case '#':
+			p.position++
+			key := p.parseIdent()
+			if key == "" {
+				return nil, p.errorf("expected key after '#'")
+			}
+			result.key = key
+		case ':':
+			p.position++
+			name := p.parseIdent()
+			if name == "" {
+				return nil, p.errorf("expected name after ':'")
+			}
+			if name == "nth-child" {
+				nth, err := p.parseNthArgument()
+				if err != nil {
+					return nil, err
+				}
+				result.nthChild = append(result.nthChild, nth)
+			} else {
+				result.states = append(result.states, name)
+			}
+		default:
+			if p.position == start {
+				return nil, p.errorf("expected selector at '" + string(p.peek()) + "'")
+			}
+			return result, nil
+		}
+	}
+
+	if p.position == start {
+		return nil, p.errorf("expected selector")
+	}
+	return result, nil
+}
+
+// parseIdent reads a SpecName, key or pseudo class name. A backslash will
+// escape the following character, which allows keys that contain spaces or
+// delimiters (e.g., "#Todo\ Items").
+func (p *selectorParser) parseIdent() string {
+	var builder strings.Builder
+	for !p.done() {
+		char := p.peek()
+		if char == '\\' && p.position+1 < len(p.input) {
+			builder.WriteRune(p.input[p.position+1])
+			p.position += 2
+			continue
+		}
+		if isSelectorDelimiter(char) {
+			break
+		}
+		builder.WriteRune(char)
+		p.position++
+	}
+	return builder.String()
+}
+
+func (p *selectorParser) parseNthArgument() (nthExpression, error) {
+	if p.done() || p.peek() != '(' {
+		return nthExpression{}, p.errorf("expected '(' after :nth-child")
+	}
+	p.position++
+	start := p.position
+	for !p.done() && p.peek() != ')' {
+		p.position++
+	}
+	if p.done() {
+		return nthExpression{}, p.errorf("expected ')' after :nth-child argument")
+	}
+	argument := string(p.input[start:p.position])
+	p.position++
+
+	nth, ok := parseNth(argument)
+	if !ok {
+		return nthExpression{}, p.errorf("unsupported :nth-child argument \"" + argument + "\"")
+	}
+	return nth, nil
+}
+
+// parseNth parses the an+b microsyntax, including the odd and even keywords.
+func parseNth(argument string) (nthExpression, bool) {
+	argument = strings.ToLower(strings.Join(strings.Fields(argument), ""))
+	switch argument {
+	case "":
+		return nthExpression{}, false
+	case "odd":
+		return nthExpression{a: 2, b: 1}, true
+	case "even":
+		return nthExpression{a: 2, b: 0}, true
+	}
+
+	nIndex := strings.IndexRune(argument, 'n')
+	if nIndex == -1 {
+		b, err := strconv.Atoi(argument)
+		return nthExpression{b: b}, err == nil
+	}
+
+	result := nthExpression{}
+	switch coefficient := argument[:nIndex]; coefficient {
+	case "", "+":
+		result.a = 1
+	case "-":
+		result.a = -1
+	default:
+		a, err := strconv.Atoi(coefficient)
+		if err != nil {
+			return nthExpression{}, false
+		}
+		result.a = a
+	}
+
+	if offset := argument[nIndex+1:]; offset != "" {
+		if offset[0] != '+' && offset[0] != '-' {
+			return nthExpression{}, false
+		}
+		b, err := strconv.Atoi(offset)
+		if err != nil {
+			return nthExpression{}, false
+		}
+		result.b = b
+	}
+	return result, true
+}
+
+func isSelectorWhitespace(char rune) bool {
+	return char == ' ' || char == '\t' || char == '\n' || char == '\r'
+}
+
+func isSelectorDelimiter(char rune) bool {
+	switch char {
+	case '#', ':', '>', ',', '*', '(', ')':
+		return true
+	}
+	return isSelectorWhitespace(char)
+}
@@ -0,0 +1,157 @@
+package spec_test
+
+import (
+	"testing"
+
+	"github.com/waybeams/assert"
+	"github.com/waybeams/waybeams/pkg/ctrl"
+	"github.com/waybeams/waybeams/pkg/opts"
+	"github.com/waybeams/waybeams/pkg/spec"
+)
+
+func TestSelector(t *testing.T) {
+	var createTree = func() spec.ReadWriter {
+		return ctrl.VBox(
+			opts.Key("root"),
+			opts.Child(ctrl.Form(
+				opts.Key("form"),
+				opts.Child(ctrl.TextInput(opts.Key("name"))),
+				opts.Child(ctrl.TextInput(opts.Key("email"))),
+				opts.Child(ctrl.HBox(
+					opts.Key("actions"),
+					opts.Child(ctrl.Button(opts.Key("cancel"))),
+					opts.Child(ctrl.Button(opts.Key("save"), opts.IsDisabled(true))),
+				)),
+			)),
+			opts.Child(ctrl.Label(opts.Key("Todo Items"), opts.Text("abcd"))),
+		)
+	}
+
+	var keysFor = func(results []spec.ReadWriter) []string {
+		keys := []string{}
+		for _, result := range results {
+			keys = append(keys, result.Key())
+		}
+		return keys
+	}
+
+	t.Run("SpecName", func(t *testing.T) {
+		results := spec.QuerySelectorAll(createTree(), "TextInput")
+		assert.Equal(len(results), 2)
+		assert.Equal(results[0].Key(), "name")
+		assert.Equal(results[1].Key(), "email")
+	})
+
+	t.Run("Universal selector includes the provided node", func(t *testing.T) {
+		results := spec.QuerySelectorAll(createTree(), "*")
+		assert.Equal(len(results), 8)
+		assert.Equal(results[0].Key(), "root")
+	})
+
+	t.Run("Key", func(t *testing.T) {
+		result := spec.QuerySelector(createTree(), "#save")
+		assert.NotNil(result)
+		assert.Equal(result.Key(), "save")
+	})
+
+	t.Run("Key with escaped whitespace", func(t *testing.T) {
+		result := spec.QuerySelector(createTree(), "Label#Todo\\ Items")
+		assert.NotNil(result)
+		assert.Equal(result.Text(), "abcd")
+	})
+
+	t.Run("SpecName and Key must both match", func(t *testing.T) {
+		assert.Nil(spec.QuerySelector(createTree(), "Label#save"))
+	})
+
+	t.Run("State", func(t *testing.T) {
+		tree := createTree()
+		result := spec.QuerySelector(tree, "Button:disabled")
+		assert.Equal(result.Key(), "save")
+
+		cancel := spec.FirstByKey(tree, "cancel")
+		cancel.SetState("hovered")
+		result = spec.QuerySelector(tree, ":hovered")
+		assert.Equal(result.Key(), "cancel")
+	})
+
+	t.Run("Focused", func(t *testing.T) {
+		tree := createTree()
+		assert.Nil(spec.QuerySelector(tree, "Form > TextInput:focused"))
+
+		email := spec.FirstByKey(tree, "email")
+		email.SetFocusedSpec(email)
+		result := spec.QuerySelector(tree, "Form > TextInput:focused")
+		assert.Equal(result.Key(), "email")
+	})
+
+	t.Run("Descendant combinator", func(t *testing.T) {
+		results := spec.QuerySelectorAll(createTree(), "#root Button")
+		assert.Equal(len(results), 2)
+	})
+
+	t.Run("Child combinator", func(t *testing.T) {
+		assert.Equal(len(spec.QuerySelectorAll(createTree(), "Form > Button")), 0)
+		assert.Equal(len(spec.QuerySelectorAll(createTree(), "Form>HBox>Button")), 2)
+		assert.Equal(len(spec.QuerySelectorAll(createTree(), "VBox > Form TextInput")), 2)
+	})
+
+	t.Run("nth-child", func(t *testing.T) {
+		tree := createTree()
+		assert.Equal(spec.QuerySelector(tree, "#form > :nth-child(2)").Key(), "email")
+
+		keys := keysFor(spec.QuerySelectorAll(tree, "#form > :nth-child(odd)"))
+		assert.Equal(len(keys), 2)
+		assert.Equal(keys[0], "name")
+		assert.Equal(keys[1], "actions")
+
+		keys = keysFor(spec.QuerySelectorAll(tree, "#form > :nth-child(-n + 2)"))
+		assert.Equal(len(keys), 2)
+		assert.Equal(keys[0], "name")
+		assert.Equal(keys[1], "email")
+
+		keys = keysFor(spec.QuerySelectorAll(tree, "Button:nth-child(2n)"))
+		assert.Equal(len(keys), 1)
+		assert.Equal(keys[0], "save")
+	})
+
+	t.Run("Selector lists", func(t *testing.T) {
+		keys := keysFor(spec.QuerySelectorAll(createTree(), "#save, Label"))
+		assert.Equal(len(keys), 2)
+		assert.Equal(keys[0], "save")
+		assert.Equal(keys[1], "Todo Items")
+	})
+
+	t.Run("No match", func(t *testing.T) {
+		assert.Nil(spec.QuerySelector(createTree(), "Slider"))
+		assert.Equal(len(spec.QuerySelectorAll(createTree(), "Slider")), 0)
+	})
+
+	t.Run("Invalid selectors", func(t *testing.T) {
+		invalid := []string{"", ">", "Button >", "Form,", "#", "Button:", ":nth-child(x)", ":nth-child(2"}
+		for _, source := range invalid {
+			_, err := spec.ParseSelector(source)
+			assert.NotNil(err, source)
+		}
+
+		assert.Panic("invalid selector", func() {
+			spec.QuerySelector(createTree(), "Button >")
+		})
+	})
+
+	t.Run("Specificity", func(t *testing.T) {
+		save := spec.FirstByKey(createTree(), "save")
+
+		var specificity = func(source string) int {
+			selector, err := spec.ParseSelector(source)
+			assert.Nil(err)
+			return selector.Specificity(save)
+		}
+
+		assert.True(specificity("Button") < specificity("Button:disabled"))
+		assert.True(specificity("Button:disabled") < specificity("#save"))
+		assert.True(specificity("HBox > Button") > specificity("Button"))
+		assert.Equal(specificity("Label"), -1)
+		assert.Equal(specificity("Label, #save"), specificity("#save"))
+	})
+}
@@ -15,10 +15,13 @@ type Reader interface {
 	StyleableReader
 	FocusableReader
 	ComposableReader
+	DockReader
+	GridReader
 	LayoutableReader
 	StatefulReader
 
 	Invalidate()
+	DisplayList() *DisplayList
 	Factory() func() ReadWriter
 	SiblingsFactory() func() []ReadWriter
 	Text() string
@@ -30,9 +33,12 @@ type Writer interface {
 	StyleableWriter
 	FocusableWriter
 	ComposableWriter
+	DockWriter
+	GridWriter
 	LayoutableWriter
 	StatefulWriter
 
+	SetDisplayList(list *DisplayList)
 	SetFactory(func() ReadWriter)
 	SetSiblingsFactory(func() []ReadWriter)
 	PushUnsub(events.Unsubscriber)
@@ -52,15 +58,23 @@ type Spec struct {
 	events.EmitterBase
 
 	actualHeight      float64
+	alignSelf         Alignment
+	anchors           Anchors
+	aspectRatio       float64
 	actualWidth       float64
 	bgColor           uint
 	children          []ReadWriter
 	childrenHeight    float64
 	childrenWidth     float64
+	clipChildren      bool
+	columnGutter      float64
 	composer          interface{}
+	constraints       []string
 	contentHeight     float64
 	contentWidth      float64
 	currentState      string
+	displayList       *DisplayList
+	dock              DockValue
 	excludeFromLayout bool
 	factory           func() ReadWriter
 	flexHeight        float64
@@ -69,21 +83,41 @@ type Spec struct {
 	fontColor         uint
 	fontFace          string
 	fontSize          float64
+	gridColumn        int
+	gridColumnSpan    int
+	gridColumns       []GridTrack
+	gridRow           int
+	gridRowSpan       int
+	gridRows          []GridTrack
 	gutter            float64
 	hAlign            Alignment
+	hasAlignSelf      bool
 	height            float64
+	isDraggable       bool
+	isDropTarget      bool
 	isFocusable       bool
-	isInvisible       bool
+	isFocusScope      bool
+	isArranged        bool
+	isLaidOut         bool
+	isLayoutClean     bool
 	isMeasured        bool
+	isOverlay         bool
 	isText            bool
 	isTextInput       bool
+	justify           JustifyValue
 	key               string
+	layoutSettings    []layoutSetting
 	layoutType        LayoutTypeValue
+	marginBottom      float64
+	marginLeft        float64
+	marginRight       float64
+	marginTop         float64
 	maxHeight         float64
 	maxWidth          float64
 	minHeight         float64
 	minWidth          float64
 	name              string
+	neighbors         map[FocusDirection]string
 	paddingBottom     float64
 	paddingLeft       float64
 	paddingRight      float64
@@ -91,20 +125,26 @@ type Spec struct {
 	parent            ReadWriter
 	prefHeight        float64
 	prefWidth         float64
+	rowGutter         float64
+	scrollX           float64
+	scrollY           float64
 	siblingsFactory   func() []ReadWriter
 	specName          string
 	states            map[string][]Option
 	strokeColor       uint
 	strokeSize        float64
+	tabIndex          int
 	text              string
 	textX             float64
 	textY             float64
 	unsubs            []events.Unsubscriber
 	vAlign            Alignment
 	view              RenderHandler
+	visibility        VisibilityValue
 	width             float64
 	x                 float64
 	y                 float64
+	zIndex            int
 }
 
 func (c *Spec) Invalidate() {
@@ -113,6 +153,16 @@ func (c *Spec) Invalidate() {
 	c.Bubble(events.New(events.Invalidated, nil, nil))
 }
 
+// DisplayList returns the Commands that were recorded the last time this node
+// was drawn, or nil if it has not been drawn.
+func (c *Spec) DisplayList() *DisplayList {
+	return c.displayList
+}
+
+func (c *Spec) SetDisplayList(list *DisplayList) {
+	c.displayList = list
+}
+
 // Factory return the factory function that created this node. This function would
 // have been sent to a Childf(fn) call on the parent node.
 func (c *Spec) Factory() func() ReadWriter {
@@ -139,7 +189,10 @@ func (c *Spec) SetSiblingsFactory(factory func() []ReadWriter) {
 }
 
 func (c *Spec) SetText(text string) {
-	c.text = text
+	if c.text != text {
+		c.text = text
+		c.InvalidateLayoutFor("text", text)
+	}
 }
 
 func (c *Spec) SetView(view RenderHandler) {
@@ -0,0 +1,51 @@
+package specjson
+
+import (
+	"github.com/waybeams/waybeams/pkg/ctrl"
+	"github.com/waybeams/waybeams/pkg/spec"
+	"github.com/waybeams/waybeams/pkg/views"
+)
+
+// NewControlRegistry creates a Registry that includes every control of the
+// ctrl package and view of the views package, so that serialized Spec trees
+// can be rebuilt with Unmarshal. Applications can Register their own
+// controls on the returned Registry.
+func NewControlRegistry() *Registry {
+	return NewRegistry().
+		Register("Box", func(options ...spec.Option) spec.ReadWriter {
+			return ctrl.Box(options...)
+		}).
+		Register("Button", ctrl.Button).
+		Register("Canvas", func(options ...spec.Option) spec.ReadWriter {
+			return ctrl.Canvas(options...)
+		}).
+		Register("DockPanel", func(options ...spec.Option) spec.ReadWriter {
+			return ctrl.DockPanel(options...)
+		}).
+		Register("Form", ctrl.Form).
+		Register("Grid", func(options ...spec.Option) spec.ReadWriter {
+			return ctrl.Grid(options...)
+		}).
+		Register("HBox", func(options ...spec.Option) spec.ReadWriter {
+			return ctrl.HBox(options...)
+		}).
+		Register("Label", func(options ...spec.Option) spec.ReadWriter {
+			return ctrl.Label(options...)
+		}).
+		Register("Row", func(options ...spec.Option) spec.ReadWriter {
+			return ctrl.Row(options...)
+		}).
+		Register("ScrollView", func(options ...spec.Option) spec.ReadWriter {
+			return ctrl.ScrollView(options...)
+		}).
+		Register("Spacer", func(options ...spec.Option) spec.ReadWriter {
+			return ctrl.Spacer(options...)
+		}).
+		Register("TextInput", ctrl.TextInput).
+		Register("VBox", func(options ...spec.Option) spec.ReadWriter {
+			return ctrl.VBox(options...)
+		}).
+		RegisterView(views.LabelView).
+		RegisterView(views.RectangleView).
+		RegisterView(views.RoundedRectView)
+}
@@ -0,0 +1,408 @@
+// Package specjson serializes Spec trees as JSON, and rebuilds them with the
+// Constructors of a Registry. It is kept apart from the spec package, so that
+// applications that do not serialize their trees do not include it, and so
+// that spec does not import encoding/json (which adds reflection to every
+// browser build). For the same reason, Marshal and Unmarshal take the place
+// of spec.MarshalJSON and spec.UnmarshalJSON, and NewControlRegistry lives
+// here rather than in spec, which cannot import the ctrl package.
+//
+// Importing this package also makes spec.JSONFormat available to spec.String.
+package specjson
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/waybeams/waybeams/pkg/spec"
+)
+
+// jsonNode is the serialized form of a single Spec node. Event handlers and
+// state Options are not serializable, and are expected to be provided again
+// by the registered Constructor.
+type jsonNode struct {
+	SpecName string   `json:"specName,omitempty"`
+	Key      string   `json:"key,omitempty"`
+	Text     string   `json:"text,omitempty"`
+	View     string   `json:"view,omitempty"`
+	State    string   `json:"state,omitempty"`
+	States   []string `json:"states,omitempty"`
+	Focused  bool     `json:"focused,omitempty"`
+
+	// Styleable
+	BgColor      uint                 `json:"bgColor,omitempty"`
+	ClipChildren bool                 `json:"clipChildren,omitempty"`
+	FontColor    uint                 `json:"fontColor,omitempty"`
+	FontFace     string               `json:"fontFace,omitempty"`
+	FontSize     float64              `json:"fontSize,omitempty"`
+	IsOverlay    bool                 `json:"isOverlay,omitempty"`
+	StrokeColor  uint                 `json:"strokeColor,omitempty"`
+	StrokeSize   float64              `json:"strokeSize,omitempty"`
+	Visibility   spec.VisibilityValue `json:"visibility,omitempty"`
+	// Visible is only read, from documents that were written before
+	// Visibility.
+	Visible *bool `json:"visible,omitempty"`
+	ZIndex  int   `json:"zIndex,omitempty"`
+
+	// Focusable
+	IsDraggable  bool                           `json:"isDraggable,omitempty"`
+	IsDropTarget bool                           `json:"isDropTarget,omitempty"`
+	IsFocusable  bool                           `json:"isFocusable,omitempty"`
+	IsFocusScope bool                           `json:"isFocusScope,omitempty"`
+	IsText       bool                           `json:"isText,omitempty"`
+	IsTextInput  bool                           `json:"isTextInput,omitempty"`
+	Neighbors    map[spec.FocusDirection]string `json:"neighbors,omitempty"`
+	TabIndex     int                            `json:"tabIndex,omitempty"`
+
+	// Layoutable
+	ActualHeight      float64              `json:"actualHeight,omitempty"`
+	AlignSelf         *spec.Alignment      `json:"alignSelf,omitempty"`
+	ActualWidth       float64              `json:"actualWidth,omitempty"`
+	Anchors           *spec.Anchors        `json:"anchors,omitempty"`
+	AspectRatio       float64              `json:"aspectRatio,omitempty"`
+	ChildrenHeight    float64              `json:"childrenHeight,omitempty"`
+	ChildrenWidth     float64              `json:"childrenWidth,omitempty"`
+	ColumnGutter      float64              `json:"columnGutter,omitempty"`
+	Constraints       []string             `json:"constraints,omitempty"`
+	ContentHeight     float64              `json:"contentHeight,omitempty"`
+	ContentWidth      float64              `json:"contentWidth,omitempty"`
+	Dock              spec.DockValue       `json:"dock,omitempty"`
+	ExcludeFromLayout bool                 `json:"excludeFromLayout,omitempty"`
+	FlexHeight        float64              `json:"flexHeight,omitempty"`
+	FlexWidth         float64              `json:"flexWidth,omitempty"`
+	GridColumn        int                  `json:"gridColumn,omitempty"`
+	GridColumnSpan    int                  `json:"gridColumnSpan,omitempty"`
+	GridColumns       []spec.GridTrack     `json:"gridColumns,omitempty"`
+	GridRow           int                  `json:"gridRow,omitempty"`
+	GridRowSpan       int                  `json:"gridRowSpan,omitempty"`
+	GridRows          []spec.GridTrack     `json:"gridRows,omitempty"`
+	Gutter            float64              `json:"gutter,omitempty"`
+	HAlign            spec.Alignment       `json:"hAlign,omitempty"`
+	Height            float64              `json:"height,omitempty"`
+	IsMeasured        bool                 `json:"isMeasured,omitempty"`
+	Justify           spec.JustifyValue    `json:"justify,omitempty"`
+	LayoutType        spec.LayoutTypeValue `json:"layoutType,omitempty"`
+	MarginBottom      float64              `json:"marginBottom,omitempty"`
+	MarginLeft        float64              `json:"marginLeft,omitempty"`
+	MarginRight       float64              `json:"marginRight,omitempty"`
+	MarginTop         float64              `json:"marginTop,omitempty"`
+	MaxHeight         float64              `json:"maxHeight,omitempty"`
+	MaxWidth          float64              `json:"maxWidth,omitempty"`
+	MinHeight         float64              `json:"minHeight,omitempty"`
+	MinWidth          float64              `json:"minWidth,omitempty"`
+	PaddingBottom     float64              `json:"paddingBottom,omitempty"`
+	PaddingLeft       float64              `json:"paddingLeft,omitempty"`
+	PaddingRight      float64              `json:"paddingRight,omitempty"`
+	PaddingTop        float64              `json:"paddingTop,omitempty"`
+	PrefHeight        float64              `json:"prefHeight,omitempty"`
+	PrefWidth         float64              `json:"prefWidth,omitempty"`
+	RowGutter         float64              `json:"rowGutter,omitempty"`
+	ScrollX           float64              `json:"scrollX,omitempty"`
+	ScrollY           float64              `json:"scrollY,omitempty"`
+	TextX             float64              `json:"textX,omitempty"`
+	TextY             float64              `json:"textY,omitempty"`
+	VAlign            spec.Alignment       `json:"vAlign,omitempty"`
+	Width             float64              `json:"width,omitempty"`
+	X                 float64              `json:"x,omitempty"`
+	Y                 float64              `json:"y,omitempty"`
+
+	Children []*jsonNode `json:"children,omitempty"`
+}
+
+func init() {
+	spec.RegisterFormat(spec.JSONFormat, specToJSON)
+}
+
+// Marshal serializes the provided Spec tree, including every Styleable,
+// Layoutable and Focusable value, keys, states and children.
+//
+// Views are serialized by ViewName, and font values are only written when
+// they differ from the value that would be inherited from the parent node.
+func Marshal(root spec.Reader) ([]byte, error) {
+	return json.Marshal(toJSONNode(root, root.FocusedSpec()))
+}
+
+// Unmarshal creates a Spec tree from data that was created by Marshal. Each
+// node is created by the Constructor that was registered for its SpecName,
+// and serialized values are then applied over whatever the Constructor
+// provided.
+func Unmarshal(data []byte, registry *Registry) (spec.ReadWriter, error) {
+	node := &jsonNode{}
+	err := json.Unmarshal(data, node)
+	if err != nil {
+		return nil, err
+	}
+
+	var focused spec.ReadWriter
+	root, err := fromJSONNode(node, registry, &focused)
+	if err != nil {
+		return nil, err
+	}
+	if focused != nil {
+		root.SetFocusedSpec(focused)
+	}
+	return root, nil
+}
+
+func specToJSON(r spec.Reader) string {
+	data, err := json.MarshalIndent(toJSONNode(r, r.FocusedSpec()), "", "\t")
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func toJSONNode(r spec.Reader, focused spec.ReadWriter) *jsonNode {
+	textX, textY := r.TextOffset()
+
+	node := &jsonNode{
+		SpecName: r.SpecName(),
+		Key:      r.Key(),
+		Text:     r.Text(),
+		View:     ViewName(r.View()),
+		State:    r.State(),
+		States:   r.States(),
+		Focused:  focused != nil && spec.Reader(focused) == r,
+
+		BgColor:      r.BgColor(),
+		ClipChildren: r.ClipChildren(),
+		IsOverlay:    r.IsOverlay(),
+		StrokeColor:  r.StrokeColor(),
+		StrokeSize:   r.StrokeSize(),
+		Visibility:   r.Visibility(),
+		ZIndex:       r.ZIndex(),
+
+		IsDraggable:  r.IsDraggable(),
+		IsDropTarget: r.IsDropTarget(),
+		IsFocusable:  r.IsFocusable(),
+		IsFocusScope: r.IsFocusScope(),
+		IsText:       r.IsText(),
+		IsTextInput:  r.IsTextInput(),
+		Neighbors:    r.Neighbors(),
+		TabIndex:     r.TabIndex(),
+
+		ActualHeight:      r.ActualHeight(),
+		ActualWidth:       r.ActualWidth(),
+		AspectRatio:       r.AspectRatio(),
+		ChildrenHeight:    r.ChildrenHeight(),
+		ChildrenWidth:     r.ChildrenWidth(),
+		Constraints:       r.Constraints(),
+		ContentHeight:     r.ContentHeight(),
+		ContentWidth:      r.ContentWidth(),
+		Dock:              r.Dock(),
+		ExcludeFromLayout: r.ExcludeFromLayout(),
+		FlexHeight:        r.FlexHeight(),
+		FlexWidth:         r.FlexWidth(),
+		GridColumn:        r.GridColumn(),
+		GridColumns:       r.GridColumns(),
+		GridRow:           r.GridRow(),
+		GridRows:          r.GridRows(),
+		Gutter:            r.Gutter(),
+		HAlign:            r.HAlign(),
+		Height:            r.Height(),
+		IsMeasured:        r.IsMeasured(),
+		Justify:           r.Justify(),
+		LayoutType:        r.LayoutType(),
+		MarginBottom:      r.MarginBottom(),
+		MarginLeft:        r.MarginLeft(),
+		MarginRight:       r.MarginRight(),
+		MarginTop:         r.MarginTop(),
+		MaxHeight:         r.MaxHeight(),
+		MaxWidth:          r.MaxWidth(),
+		MinHeight:         r.MinHeight(),
+		MinWidth:          r.MinWidth(),
+		PaddingBottom:     r.PaddingBottom(),
+		PaddingLeft:       r.PaddingLeft(),
+		PaddingRight:      r.PaddingRight(),
+		PaddingTop:        r.PaddingTop(),
+		PrefHeight:        r.PrefHeight(),
+		PrefWidth:         r.PrefWidth(),
+		ScrollX:           r.ScrollX(),
+		ScrollY:           r.ScrollY(),
+		TextX:             textX,
+		TextY:             textY,
+		VAlign:            r.VAlign(),
+		Width:             r.Width(),
+		X:                 r.X(),
+		Y:                 r.Y(),
+	}
+
+	// Font values are inherited, so we only write the ones that differ.
+	parent := r.Parent()
+	if parent == nil {
+		if r.FontColor() != spec.DefaultFontColor {
+			node.FontColor = r.FontColor()
+		}
+		if r.FontFace() != spec.DefaultFontFace {
+			node.FontFace = r.FontFace()
+		}
+		if r.FontSize() != spec.DefaultFontSize {
+			node.FontSize = r.FontSize()
+		}
+	} else {
+		if r.FontColor() != parent.FontColor() {
+			node.FontColor = r.FontColor()
+		}
+		if r.FontFace() != parent.FontFace() {
+			node.FontFace = r.FontFace()
+		}
+		if r.FontSize() != parent.FontSize() {
+			node.FontSize = r.FontSize()
+		}
+	}
+
+	// Grid values that fall back to a default are only written when they
+	// differ from it.
+	if r.ColumnGutter() != r.Gutter() {
+		node.ColumnGutter = r.ColumnGutter()
+	}
+	if r.RowGutter() != r.Gutter() {
+		node.RowGutter = r.RowGutter()
+	}
+	if r.GridColumnSpan() > 1 {
+		node.GridColumnSpan = r.GridColumnSpan()
+	}
+	if r.GridRowSpan() > 1 {
+		node.GridRowSpan = r.GridRowSpan()
+	}
+
+	if r.HasAlignSelf() {
+		alignSelf := r.AlignSelf()
+		node.AlignSelf = &alignSelf
+	}
+	if r.Anchors().Edges != 0 {
+		anchors := r.Anchors()
+		node.Anchors = &anchors
+	}
+
+	for _, child := range r.Children() {
+		node.Children = append(node.Children, toJSONNode(child, focused))
+	}
+	return node
+}
+
+func fromJSONNode(node *jsonNode, registry *Registry, focused *spec.ReadWriter) (spec.ReadWriter, error) {
+	constructor, ok := registry.Constructor(node.SpecName)
+	if !ok {
+		return nil, errors.New("unknown SpecName \"" + node.SpecName + "\"")
+	}
+	rw := constructor()
+
+	if node.View != "" {
+		view, ok := registry.View(node.View)
+		if !ok {
+			return nil, errors.New("unknown View \"" + node.View + "\"")
+		}
+		rw.SetView(view)
+	} else {
+		rw.SetView(nil)
+	}
+
+	// Children are always replaced, even if the Constructor provided some.
+	children := []spec.ReadWriter{}
+	for _, childNode := range node.Children {
+		child, err := fromJSONNode(childNode, registry, focused)
+		if err != nil {
+			return nil, err
+		}
+		child.SetParent(rw)
+		children = append(children, child)
+	}
+	rw.SetChildren(children)
+
+	// Constructors provide the Options for each state, but states that were
+	// added some other way will not have any.
+	for _, state := range node.States {
+		if !rw.HasState(state) {
+			rw.OnState(state)
+		}
+	}
+	rw.SetState(node.State)
+	spec.Apply(rw)
+
+	rw.SetKey(node.Key)
+	rw.SetText(node.Text)
+	if node.Focused {
+		*focused = rw
+	}
+
+	rw.SetBgColor(node.BgColor)
+	rw.SetClipChildren(node.ClipChildren)
+	rw.SetFontColor(node.FontColor)
+	rw.SetFontFace(node.FontFace)
+	rw.SetFontSize(node.FontSize)
+	rw.SetIsOverlay(node.IsOverlay)
+	rw.SetStrokeColor(node.StrokeColor)
+	rw.SetStrokeSize(node.StrokeSize)
+	rw.SetVisible(node.Visible == nil || *node.Visible)
+	if node.Visibility != spec.VisibilityVisible {
+		rw.SetVisibility(node.Visibility)
+	}
+	rw.SetZIndex(node.ZIndex)
+
+	rw.SetIsDraggable(node.IsDraggable)
+	rw.SetIsDropTarget(node.IsDropTarget)
+	rw.SetIsFocusable(node.IsFocusable)
+	rw.SetIsFocusScope(node.IsFocusScope)
+	rw.SetIsText(node.IsText)
+	rw.SetIsTextInput(node.IsTextInput)
+	for direction, key := range node.Neighbors {
+		rw.SetNeighbor(direction, key)
+	}
+	rw.SetTabIndex(node.TabIndex)
+
+	rw.SetActualHeight(node.ActualHeight)
+	rw.SetActualWidth(node.ActualWidth)
+	if node.AlignSelf != nil {
+		rw.SetAlignSelf(*node.AlignSelf)
+	}
+	if node.Anchors != nil {
+		rw.SetAnchors(*node.Anchors)
+	}
+	rw.SetAspectRatio(node.AspectRatio)
+	rw.SetChildrenHeight(node.ChildrenHeight)
+	rw.SetChildrenWidth(node.ChildrenWidth)
+	rw.SetColumnGutter(node.ColumnGutter)
+	rw.SetConstraints(node.Constraints...)
+	rw.SetContentHeight(node.ContentHeight)
+	rw.SetContentWidth(node.ContentWidth)
+	rw.SetDock(node.Dock)
+	rw.SetExcludeFromLayout(node.ExcludeFromLayout)
+	rw.SetFlexHeight(node.FlexHeight)
+	rw.SetFlexWidth(node.FlexWidth)
+	rw.SetGridColumn(node.GridColumn)
+	rw.SetGridColumnSpan(node.GridColumnSpan)
+	rw.SetGridColumns(node.GridColumns)
+	rw.SetGridRow(node.GridRow)
+	rw.SetGridRowSpan(node.GridRowSpan)
+	rw.SetGridRows(node.GridRows)
+	rw.SetGutter(node.Gutter)
+	rw.SetHAlign(node.HAlign)
+	rw.SetHeight(node.Height)
+	rw.SetIsMeasured(node.IsMeasured)
+	rw.SetJustify(node.Justify)
+	rw.SetLayoutType(node.LayoutType)
+	rw.SetMarginBottom(node.MarginBottom)
+	rw.SetMarginLeft(node.MarginLeft)
+	rw.SetMarginRight(node.MarginRight)
+	rw.SetMarginTop(node.MarginTop)
+	rw.SetMaxHeight(node.MaxHeight)
+	rw.SetMaxWidth(node.MaxWidth)
+	rw.SetMinHeight(node.MinHeight)
+	rw.SetMinWidth(node.MinWidth)
+	rw.SetPaddingBottom(node.PaddingBottom)
+	rw.SetPaddingLeft(node.PaddingLeft)
+	rw.SetPaddingRight(node.PaddingRight)
+	rw.SetPaddingTop(node.PaddingTop)
+	rw.SetPrefHeight(node.PrefHeight)
+	rw.SetPrefWidth(node.PrefWidth)
+	rw.SetRowGutter(node.RowGutter)
+	rw.SetScrollX(node.ScrollX)
+	rw.SetScrollY(node.ScrollY)
+	rw.SetTextX(node.TextX)
+	rw.SetTextY(node.TextY)
+	rw.SetVAlign(node.VAlign)
+	rw.SetWidth(node.Width)
+	rw.SetX(node.X)
+	rw.SetY(node.Y)
+	return rw, nil
+}
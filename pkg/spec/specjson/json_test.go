@@ -0,0 +1,196 @@
+package specjson_test
+
+import (
+	"testing"
+
+	"github.com/waybeams/assert"
+	"github.com/waybeams/waybeams/pkg/ctrl"
+	"github.com/waybeams/waybeams/pkg/events"
+	"github.com/waybeams/waybeams/pkg/opts"
+	"github.com/waybeams/waybeams/pkg/spec"
+	"github.com/waybeams/waybeams/pkg/spec/specjson"
+	"github.com/waybeams/waybeams/pkg/views"
+)
+
+func TestJSON(t *testing.T) {
+	var createTree = func() spec.ReadWriter {
+		return ctrl.VBox(
+			opts.Key("root"),
+			opts.FontSize(18),
+			opts.Padding(10),
+			opts.Width(640),
+			opts.Height(480),
+			opts.Child(ctrl.HBox(
+				opts.Key("toolbar"),
+				opts.FlexWidth(1),
+				opts.HAlign(spec.AlignRight),
+				opts.Gutter(5),
+				opts.Dock(spec.DockTop),
+				opts.Child(ctrl.Button(opts.Key("save"), opts.Text("Save"), opts.IsDisabled(true))),
+				opts.Child(ctrl.Spacer(opts.Key("spacer"), opts.Constraints("width >= 10 @ weak"), opts.AnchorRight(4))),
+			)),
+			opts.Child(ctrl.TextInput(opts.Key("name"), opts.Text("abcd"), opts.FontColor(0x333333ff))),
+			opts.Child(ctrl.Box(
+				opts.Key("invisible"),
+				opts.Visible(false),
+				opts.View(views.RoundedRectView),
+				opts.ExcludeFromLayout(true),
+			)),
+		)
+	}
+
+	var roundTrip = func(tree spec.ReadWriter) spec.ReadWriter {
+		data, err := specjson.Marshal(tree)
+		assert.Nil(err)
+		result, err := specjson.Unmarshal(data, specjson.NewControlRegistry())
+		assert.Nil(err)
+		return result
+	}
+
+	t.Run("Round trips structure", func(t *testing.T) {
+		result := roundTrip(createTree())
+		assert.Equal(result.SpecName(), "VBox")
+		assert.Equal(result.Key(), "root")
+		assert.Equal(result.ChildCount(), 3)
+		assert.Equal(spec.Path(spec.FirstByKey(result, "save")), "/root/toolbar/save")
+		assert.Equal(spec.FirstByKey(result, "spacer").Parent().Key(), "toolbar")
+	})
+
+	t.Run("Round trips values", func(t *testing.T) {
+		result := roundTrip(createTree())
+		assert.Equal(result.Width(), 640.0)
+		assert.Equal(result.Height(), 480.0)
+		assert.Equal(result.PaddingTop(), 10.0)
+		assert.Equal(result.FontSize(), 18.0)
+
+		toolbar := spec.FirstByKey(result, "toolbar")
+		assert.Equal(toolbar.FlexWidth(), 1.0)
+		assert.Equal(toolbar.Gutter(), 5.0)
+		assert.Equal(toolbar.HAlign(), spec.Alignment(spec.AlignRight))
+		assert.Equal(toolbar.LayoutType(), spec.HorizontalFlowLayoutType)
+		assert.Equal(toolbar.Dock(), spec.DockTop)
+
+		spacer := spec.FirstByKey(result, "spacer")
+		assert.Equal(spacer.Constraints()[0], "width >= 10 @ weak")
+		assert.True(spacer.Anchors().Has(spec.EdgeRight))
+		assert.Equal(spacer.Anchors().Right, 4.0)
+
+		name := spec.FirstByKey(result, "name")
+		assert.Equal(name.Text(), "abcd")
+		assert.Equal(name.FontColor(), uint(0x333333ff))
+		assert.True(name.IsTextInput())
+
+		invisible := spec.FirstByKey(result, "invisible")
+		assert.False(invisible.Visible())
+		assert.True(invisible.ExcludeFromLayout())
+		assert.Equal(invisible.Visibility(), spec.VisibilityHidden)
+	})
+
+	t.Run("Round trips text offsets", func(t *testing.T) {
+		tree := ctrl.Label(opts.Text("abcd"), opts.Padding(5))
+		tree.SetX(10)
+		tree.SetTextX(2)
+		tree.SetTextY(-3)
+
+		result := roundTrip(tree)
+		x, y := result.TextOffset()
+		assert.Equal(x, 2.0)
+		assert.Equal(y, -3.0)
+		assert.Equal(result.TextX(), 13.0)
+	})
+
+	t.Run("Round trips collapsed", func(t *testing.T) {
+		result := roundTrip(ctrl.Box(opts.Visibility(spec.VisibilityCollapsed)))
+		assert.Equal(result.Visibility(), spec.VisibilityCollapsed)
+		assert.False(result.ExcludeFromLayout())
+	})
+
+	t.Run("Round trips drag and drop", func(t *testing.T) {
+		result := roundTrip(ctrl.Box(opts.IsDraggable(true), opts.IsDropTarget(true)))
+		assert.True(result.IsDraggable())
+		assert.True(result.IsDropTarget())
+	})
+
+	t.Run("Round trips focus traversal", func(t *testing.T) {
+		result := roundTrip(ctrl.Box(
+			opts.IsFocusScope(true),
+			opts.Neighbor(spec.FocusRight, "next"),
+			opts.TabIndex(2),
+		))
+		assert.True(result.IsFocusScope())
+		assert.Equal(result.Neighbor(spec.FocusRight), "next")
+		assert.Equal(result.Neighbor(spec.FocusLeft), "")
+		assert.Equal(result.TabIndex(), 2)
+	})
+
+	t.Run("Reads visible from older documents", func(t *testing.T) {
+		result, err := specjson.Unmarshal([]byte(`{"specName":"Box","visible":false}`), specjson.NewControlRegistry())
+		assert.Nil(err)
+		assert.Equal(result.Visibility(), spec.VisibilityHidden)
+	})
+
+	t.Run("Inherited font values remain inherited", func(t *testing.T) {
+		result := roundTrip(createTree())
+		save := spec.FirstByKey(result, "save")
+		assert.Equal(save.FontSize(), 18.0)
+
+		result.SetFontSize(30)
+		assert.Equal(save.FontSize(), 30.0)
+	})
+
+	t.Run("Round trips states", func(t *testing.T) {
+		result := roundTrip(createTree())
+		save := spec.FirstByKey(result, "save")
+		assert.Equal(save.State(), "disabled")
+		assert.Equal(save.BgColor(), uint(0xdbd9d6ff))
+		assert.True(save.HasState("hovered"))
+	})
+
+	t.Run("Round trips focus", func(t *testing.T) {
+		tree := createTree()
+		name := spec.FirstByKey(tree, "name")
+		name.SetFocusedSpec(name)
+
+		result := roundTrip(tree)
+		assert.Equal(result.FocusedSpec(), spec.FirstByKey(result, "name"))
+	})
+
+	t.Run("Constructors provide handlers", func(t *testing.T) {
+		result := roundTrip(createTree())
+		name := spec.FirstByKey(result, "name")
+		name.Emit(events.New(events.CharEntered, name, "e"))
+		assert.Equal(name.Text(), "abcde")
+	})
+
+	t.Run("Round trips named views", func(t *testing.T) {
+		result := roundTrip(createTree())
+		invisible := spec.FirstByKey(result, "invisible")
+		assert.Equal(specjson.ViewName(invisible.View()), "views.RoundedRectView")
+		assert.Nil(result.View())
+	})
+
+	t.Run("Is stable", func(t *testing.T) {
+		first, err := specjson.Marshal(createTree())
+		assert.Nil(err)
+		second, err := specjson.Marshal(roundTrip(createTree()))
+		assert.Nil(err)
+		assert.Equal(string(first), string(second))
+	})
+
+	t.Run("Unknown SpecName", func(t *testing.T) {
+		_, err := specjson.Unmarshal([]byte(`{"specName":"Slider"}`), specjson.NewControlRegistry())
+		assert.NotNil(err)
+		assert.Match("unknown SpecName \"Slider\"", err.Error())
+	})
+
+	t.Run("Unknown View", func(t *testing.T) {
+		_, err := specjson.Unmarshal([]byte(`{"specName":"Box","view":"custom.View"}`), specjson.NewControlRegistry())
+		assert.NotNil(err)
+		assert.Match("unknown View \"custom.View\"", err.Error())
+	})
+
+	t.Run("Invalid data", func(t *testing.T) {
+		_, err := specjson.Unmarshal([]byte(`{`), specjson.NewControlRegistry())
+		assert.NotNil(err)
+	})
+}
@@ -0,0 +1,69 @@
+package specjson
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/waybeams/waybeams/pkg/spec"
+)
+
+// Constructor creates a new Spec node from the provided Options (e.g.,
+// ctrl.Button).
+type Constructor func(options ...spec.Option) spec.ReadWriter
+
+// Registry maps SpecNames to the Constructors that create them, and View
+// names to RenderHandlers. A Registry is used to rebuild Spec trees that were
+// serialized with Marshal.
+type Registry struct {
+	constructors map[string]Constructor
+	views        map[string]spec.RenderHandler
+}
+
+// Register associates the provided SpecName with a Constructor.
+func (r *Registry) Register(specName string, constructor Constructor) *Registry {
+	r.constructors[specName] = constructor
+	return r
+}
+
+// RegisterView makes the provided RenderHandler available by its ViewName.
+func (r *Registry) RegisterView(view spec.RenderHandler) *Registry {
+	r.views[ViewName(view)] = view
+	return r
+}
+
+// Constructor returns the Constructor for the provided SpecName. Nodes
+// without a SpecName are created with New.
+func (r *Registry) Constructor(specName string) (Constructor, bool) {
+	if specName == "" {
+		return func(options ...spec.Option) spec.ReadWriter {
+			return spec.Apply(spec.New(), options...)
+		}, true
+	}
+	constructor, ok := r.constructors[specName]
+	return constructor, ok
+}
+
+// View returns the RenderHandler that was registered with the provided name.
+func (r *Registry) View(name string) (spec.RenderHandler, bool) {
+	view, ok := r.views[name]
+	return view, ok
+}
+
+// ViewName returns the package qualified function name of the provided
+// RenderHandler (e.g., "views.LabelView"), or an empty string for nil.
+func ViewName(view spec.RenderHandler) string {
+	if view == nil {
+		return ""
+	}
+	name := runtime.FuncForPC(reflect.ValueOf(view).Pointer()).Name()
+	return name[strings.LastIndex(name, "/")+1:]
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		constructors: make(map[string]Constructor),
+		views:        make(map[string]spec.RenderHandler),
+	}
+}
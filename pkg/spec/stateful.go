@@ -1,10 +1,13 @@
 package spec
 
+import "sort"
+
 type StatefulReader interface {
 	HasState(name string) bool
 	OnState(name string, options ...Option)
 	OptionsForState(stateName string) []Option
 	State() string
+	States() []string
 }
 
 type StatefulWriter interface {
@@ -53,3 +56,14 @@ func (c *Spec) HasState(name string) bool {
 func (c *Spec) State() string {
 	return c.currentState
 }
+
+// States returns the sorted names of every state that has been configured
+// with OnState.
+func (c *Spec) States() []string {
+	names := []string{}
+	for name := range c.getStates() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -1,11 +1,30 @@
 package spec
 
 import (
+	"strconv"
 	"strings"
 )
 
 const stringIndent = "\t"
 
+// StringFormat selects the output of String.
+type StringFormat int
+
+const (
+	// TextFormat prints an indented tree with one line per node.
+	TextFormat StringFormat = iota
+	// JSONFormat prints the indented JSON form of the tree, using the
+	// formatter that pkg/spec/specjson registers when it is imported.
+	// Without that import, String returns a placeholder that says so.
+	JSONFormat
+	// DOTFormat prints a Graphviz digraph of the tree.
+	DOTFormat
+)
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
+
 func specChildrenToString(r Reader, indents ...string) string {
 	indents = append(indents, stringIndent)
 	if r.ChildCount() > 0 {
@@ -19,27 +38,117 @@ func specChildrenToString(r Reader, indents ...string) string {
 	return ""
 }
 
+func specAttrs(r Reader) []string {
+	attrs := []string{}
+	if r.Key() != "" {
+		attrs = append(attrs, "Key: "+r.Key())
+	}
+	attrs = append(attrs,
+		"Path: "+Path(r),
+		"X: "+formatFloat(r.X()),
+		"Y: "+formatFloat(r.Y()),
+		"Width: "+formatFloat(r.Width()),
+		"Height: "+formatFloat(r.Height()),
+	)
+	if r.PaddingTop() != 0 || r.PaddingRight() != 0 || r.PaddingBottom() != 0 || r.PaddingLeft() != 0 {
+		attrs = append(attrs, "Padding: "+strings.Join([]string{
+			formatFloat(r.PaddingTop()),
+			formatFloat(r.PaddingRight()),
+			formatFloat(r.PaddingBottom()),
+			formatFloat(r.PaddingLeft()),
+		}, " "))
+	}
+	if r.FlexWidth() != 0 {
+		attrs = append(attrs, "FlexWidth: "+formatFloat(r.FlexWidth()))
+	}
+	if r.FlexHeight() != 0 {
+		attrs = append(attrs, "FlexHeight: "+formatFloat(r.FlexHeight()))
+	}
+	if r.State() != "" {
+		attrs = append(attrs, "State: "+r.State())
+	}
+	if r.Text() != "" {
+		attrs = append(attrs, "Text: "+r.Text())
+	}
+	return attrs
+}
+
 func specAttrsToString(r Reader, indents ...string) string {
-	/*
-		result := fmt.Sprintf("%s(Width: %.2f, Height: %.2f", r.SpecName(), r.Width(), r.Height())
-		if r.Text() != "" {
-			result += ", Text: " + r.Text()
+	result := r.SpecName() + "(" + strings.Join(specAttrs(r), ", ")
+
+	if r.ChildCount() > 0 {
+		kidString := specChildrenToString(r, indents...)
+		return result + kidString + "\n" + strings.Join(indents, "") + ")"
+	}
+
+	return result + ")"
+}
+
+func specToDOT(r Reader) string {
+	lines := []string{"digraph {"}
+	var visit func(node Reader)
+	visit = func(node Reader) {
+		id := strconv.Quote(Path(node))
+		label := strconv.Quote(node.SpecName() + "\n" + strings.Join(specAttrs(node), "\n"))
+		lines = append(lines, stringIndent+id+" [shape=box, label="+label+"];")
+		for _, child := range node.Children() {
+			lines = append(lines, stringIndent+id+" -> "+strconv.Quote(Path(child))+";")
+			visit(child)
 		}
+	}
+	visit(r)
+	return strings.Join(append(lines, "}"), "\n")
+}
 
-		if r.ChildCount() > 0 {
-			kidString := specChildrenToString(r, indents...)
-			result += kidString
-			return fmt.Sprintf("%v\n%v)", result, strings.Join(indents, ""))
+// formatters holds the registered StringFormats that are provided by other
+// packages, so that this package does not depend on them.
+var formatters = make(map[StringFormat]func(r Reader) string)
+
+// RegisterFormat associates a formatter with the provided StringFormat,
+// replacing any formatter that was already registered with it. The returned
+// function restores the previous formatter, if any.
+func RegisterFormat(format StringFormat, formatter func(r Reader) string) func() {
+	previous, hadPrevious := formatters[format]
+	formatters[format] = formatter
+	return func() {
+		if hadPrevious {
+			formatters[format] = previous
+		} else {
+			delete(formatters, format)
 		}
+	}
+}
 
-		return fmt.Sprintf("%v)", result)
-	*/
-	return ""
+// unregisteredFormat returns the placeholder that String returns for a
+// format without a formatter.
+func unregisteredFormat(format StringFormat) string {
+	if format == JSONFormat {
+		return "<JSONFormat: import pkg/spec/specjson>"
+	}
+	return "<StringFormat " + strconv.Itoa(int(format)) + ": not registered>"
 }
 
-func String(r Reader) string {
+// String returns a description of the provided Spec tree, which is most
+// useful when printing a laid out tree from a failing test. The tree is
+// printed as indented text unless another StringFormat is provided.
+// JSONFormat requires an import of pkg/spec/specjson, and formats without a
+// registered formatter return a placeholder instead of the tree.
+func String(r Reader, format ...StringFormat) string {
 	if r == nil {
 		return ""
 	}
+	if len(format) > 0 {
+		switch format[0] {
+		case TextFormat:
+		case DOTFormat:
+			return specToDOT(r)
+		default:
+			formatter, ok := formatters[format[0]]
+			if !ok {
+				return unregisteredFormat(format[0])
+			}
+			return formatter(r)
+		}
+	}
 	return specAttrsToString(r)
 }
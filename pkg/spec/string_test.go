@@ -9,14 +9,13 @@ import (
 	"github.com/waybeams/waybeams/pkg/layout"
 	"github.com/waybeams/waybeams/pkg/opts"
 	"github.com/waybeams/waybeams/pkg/spec"
+	"github.com/waybeams/waybeams/pkg/spec/specjson"
 )
 
 func TestString(t *testing.T) {
-	t.Skip("Checking how much fmt imports hurt browser binary size")
-
 	t.Run("Callable", func(t *testing.T) {
 		str := spec.String(ctrl.HBox())
-		assert.Equal(str, "HBox(Width: 0.00, Height: 0.00)")
+		assert.Equal(str, "HBox(Path: /HBox, X: 0.00, Y: 0.00, Width: 0.00, Height: 0.00)")
 	})
 
 	t.Run("Handles nil spec", func(t *testing.T) {
@@ -28,7 +27,7 @@ func TestString(t *testing.T) {
 			opts.Width(300.12345),
 			opts.Height(200.00),
 		))
-		assert.Equal(str, "HBox(Width: 300.12, Height: 200.00)")
+		assert.Equal(str, "HBox(Path: /HBox, X: 0.00, Y: 0.00, Width: 300.12, Height: 200.00)")
 	})
 
 	t.Run("Handles Children", func(t *testing.T) {
@@ -42,12 +41,52 @@ func TestString(t *testing.T) {
 			)),
 		)
 		layout.Layout(tree, fake.NewSurface())
-		result := `VBox(Width: 46.00, Height: 34.00
-	Box(Width: 46.00, Height: 34.00
-		Button(Width: 46.00, Height: 34.00, Text: One)
+		result := `VBox(Path: /VBox, X: 0.00, Y: 0.00, Width: 40.00, Height: 34.00
+	Box(Path: /VBox/Box-0, X: 0.00, Y: 0.00, Width: 40.00, Height: 34.00
+		Button(Path: /VBox/Box-0/Button-0, X: 0.00, Y: 0.00, Width: 40.00, Height: 34.00, Padding: 5.00 5.00 5.00 5.00, State: active, Text: One)
 	)
 )`
 		str := spec.String(tree)
 		assert.Equal("\n"+str, "\n"+result)
 	})
+
+	t.Run("Handles Key and flex", func(t *testing.T) {
+		str := spec.String(ctrl.Box(opts.Key("abcd"), opts.FlexWidth(1), opts.FlexHeight(2)))
+		assert.Equal(str, "Box(Key: abcd, Path: /abcd, X: 0.00, Y: 0.00, Width: 0.00, Height: 0.00, FlexWidth: 1.00, FlexHeight: 2.00)")
+	})
+
+	t.Run("JSONFormat", func(t *testing.T) {
+		tree := ctrl.VBox(opts.Key("root"), opts.Child(ctrl.Box(opts.Key("child"))))
+		str := spec.String(tree, spec.JSONFormat)
+		data, err := specjson.Marshal(tree)
+		assert.Nil(err)
+
+		result, err := specjson.Unmarshal([]byte(str), specjson.NewControlRegistry())
+		assert.Nil(err)
+		resultData, err := specjson.Marshal(result)
+		assert.Nil(err)
+		assert.Equal(string(resultData), string(data))
+	})
+
+	t.Run("Registered formats", func(t *testing.T) {
+		custom := spec.StringFormat(100)
+		assert.Equal(spec.String(ctrl.Box(), custom), "<StringFormat 100: not registered>")
+
+		restore := spec.RegisterFormat(custom, func(r spec.Reader) string {
+			return "custom " + r.SpecName()
+		})
+		t.Cleanup(restore)
+		assert.Equal(spec.String(ctrl.Box(), custom), "custom Box")
+	})
+
+	t.Run("DOTFormat", func(t *testing.T) {
+		tree := ctrl.VBox(opts.Key("root"), opts.Child(ctrl.Box(opts.Key("child"))))
+		result := `digraph {
+	"/root" [shape=box, label="VBox\nKey: root\nPath: /root\nX: 0.00\nY: 0.00\nWidth: 0.00\nHeight: 0.00"];
+	"/root" -> "/root/child";
+	"/root/child" [shape=box, label="Box\nKey: child\nPath: /root/child\nX: 0.00\nY: 0.00\nWidth: 0.00\nHeight: 0.00"];
+}`
+		str := spec.String(tree, spec.DOTFormat)
+		assert.Equal("\n"+str, "\n"+result)
+	})
 }
@@ -7,25 +7,48 @@ const DefaultFontFace = "Roboto"
 const DefaultStrokeColor = 0xffffffff
 const DefaultStrokeSize = 1
 
+// VisibilityValue determines whether a node is drawn, and whether it takes
+// space in the layout of its parent.
+type VisibilityValue int
+
+const (
+	// VisibilityVisible nodes are drawn and receive input.
+	VisibilityVisible VisibilityValue = iota
+	// VisibilityHidden nodes keep their place in the layout, but they and
+	// their children are not drawn, hit tested or focused.
+	VisibilityHidden
+	// VisibilityCollapsed nodes are hidden and also removed from the layout,
+	// as if they were ExcludeFromLayout.
+	VisibilityCollapsed
+)
+
 // Styleable entities can have their visual styles updated.
 type StyleableReader interface {
 	BgColor() uint
+	ClipChildren() bool
 	FontColor() uint
 	FontFace() string
 	FontSize() float64
+	IsOverlay() bool
 	StrokeColor() uint
 	StrokeSize() float64
+	Visibility() VisibilityValue
 	Visible() bool
+	ZIndex() int
 }
 
 type StyleableWriter interface {
 	SetBgColor(color uint)
+	SetClipChildren(value bool)
 	SetFontColor(color uint)
 	SetFontFace(face string)
 	SetFontSize(size float64)
+	SetIsOverlay(value bool)
 	SetStrokeColor(color uint)
 	SetStrokeSize(size float64)
+	SetVisibility(visibility VisibilityValue)
 	SetVisible(visible bool)
+	SetZIndex(index int)
 }
 
 type StyleableReadWriter interface {
@@ -37,6 +60,12 @@ func (c *Spec) BgColor() uint {
 	return c.bgColor
 }
 
+// ClipChildren returns true if the children of this node are only drawn
+// within its bounds.
+func (c *Spec) ClipChildren() bool {
+	return c.clipChildren
+}
+
 func (c *Spec) FontColor() uint {
 	fontColor := c.fontColor
 	// Inherit FontColor from nearest parent.
@@ -78,12 +107,46 @@ func (c *Spec) SetBgColor(color uint) {
 	c.bgColor = color
 }
 
+func (c *Spec) SetClipChildren(value bool) {
+	c.clipChildren = value
+}
+
 func (c *Spec) SetFontFace(face string) {
 	c.fontFace = face
+	c.invalidateInheritedLayout(func(s *Spec) bool { return s.fontFace == "" })
 }
 
 func (c *Spec) SetFontSize(size float64) {
 	c.fontSize = size
+	c.invalidateInheritedLayout(func(s *Spec) bool { return s.fontSize == 0 })
+}
+
+// invalidateInheritedLayout marks this node as dirty, along with each
+// descendant that inherits the changed property from it. Descendants that
+// are not Specs are always marked.
+func (c *Spec) invalidateInheritedLayout(inherits func(s *Spec) bool) {
+	c.InvalidateLayout()
+	for _, child := range c.Children() {
+		if s, ok := specOf(child); !ok {
+			child.InvalidateLayout()
+		} else if inherits(s) {
+			s.invalidateInheritedLayout(inherits)
+		}
+	}
+}
+
+// IsOverlay returns true for nodes that are drawn above the rest of the tree,
+// in the overlay stack of the root (see Overlays), and that do not take space
+// in the layout of their parent.
+func (c *Spec) IsOverlay() bool {
+	return c.isOverlay
+}
+
+func (c *Spec) SetIsOverlay(value bool) {
+	if c.isOverlay != value {
+		c.isOverlay = value
+		c.InvalidateLayoutFor("isOverlay", value)
+	}
 }
 
 func (c *Spec) SetFontColor(size uint) {
@@ -98,9 +161,23 @@ func (c *Spec) SetStrokeSize(size float64) {
 	c.strokeSize = size
 }
 
+// SetVisibility configures whether this node is visible, hidden or
+// collapsed.
+func (c *Spec) SetVisibility(visibility VisibilityValue) {
+	isCollapsed := visibility == VisibilityCollapsed
+	if isCollapsed != (c.visibility == VisibilityCollapsed) {
+		c.InvalidateLayoutFor("isCollapsed", isCollapsed)
+	}
+	c.visibility = visibility
+}
+
+// SetVisible makes this node visible, or hidden.
 func (c *Spec) SetVisible(visible bool) {
-	// We store the opposite of the boolean because the default value is false.
-	c.isInvisible = !visible
+	if visible {
+		c.SetVisibility(VisibilityVisible)
+	} else {
+		c.SetVisibility(VisibilityHidden)
+	}
 }
 
 func (c *Spec) StrokeColor() uint {
@@ -111,8 +188,22 @@ func (c *Spec) StrokeSize() float64 {
 	return c.strokeSize
 }
 
+func (c *Spec) Visibility() VisibilityValue {
+	return c.visibility
+}
+
+// Visible returns false for nodes that are hidden or collapsed.
 func (c *Spec) Visible() bool {
-	// We return the opposite of the stored value so that the interface reads
-	// as inverted. "Visible() == true" by default.
-	return !c.isInvisible
+	return c.visibility == VisibilityVisible
+}
+
+// ZIndex orders this node among its siblings. Siblings with a greater ZIndex
+// are drawn above, and hit tested before, those with a lower one. Siblings
+// with the same ZIndex keep their order in Children.
+func (c *Spec) ZIndex() int {
+	return c.zIndex
+}
+
+func (c *Spec) SetZIndex(index int) {
+	c.zIndex = index
 }
@@ -0,0 +1,150 @@
+package spec_test
+
+import (
+	"testing"
+
+	"github.com/waybeams/assert"
+	"github.com/waybeams/waybeams/pkg/ctrl"
+	surface "github.com/waybeams/waybeams/pkg/env/fake"
+	"github.com/waybeams/waybeams/pkg/layout"
+	"github.com/waybeams/waybeams/pkg/opts"
+	"github.com/waybeams/waybeams/pkg/spec"
+)
+
+func TestVisibility(t *testing.T) {
+	t.Run("Visible by default", func(t *testing.T) {
+		box := ctrl.Box()
+		assert.True(box.Visible())
+		assert.Equal(box.Visibility(), spec.VisibilityVisible)
+	})
+
+	t.Run("Visible false is hidden", func(t *testing.T) {
+		box := ctrl.Box(opts.Visible(false))
+		assert.False(box.Visible())
+		assert.Equal(box.Visibility(), spec.VisibilityHidden)
+	})
+
+	t.Run("Collapsing invalidates layout", func(t *testing.T) {
+		root := layout.Layout(ctrl.VBox(opts.Child(ctrl.Box())), surface.NewSurface())
+		child := root.ChildAt(0)
+		child.SetVisibility(spec.VisibilityHidden)
+		assert.False(root.IsLayoutDirty())
+		child.SetVisibility(spec.VisibilityCollapsed)
+		assert.True(root.IsLayoutDirty())
+	})
+
+	t.Run("IsShown requires visible ancestors", func(t *testing.T) {
+		root := ctrl.VBox(
+			opts.Visibility(spec.VisibilityHidden),
+			opts.Child(ctrl.Box(opts.Key("child"))),
+		)
+		child := spec.FirstByKey(root, "child")
+		assert.True(child.Visible())
+		assert.False(spec.IsShown(child))
+		root.SetVisible(true)
+		assert.True(spec.IsShown(child))
+	})
+
+	t.Run("Hidden nodes are not hit tested", func(t *testing.T) {
+		root := layout.Layout(ctrl.Box(
+			opts.Width(100),
+			opts.Height(100),
+			opts.HAlign(spec.AlignLeft),
+			opts.VAlign(spec.AlignTop),
+			opts.Child(ctrl.Button(opts.Key("hidden"), opts.Width(50), opts.Height(50), opts.Visible(false))),
+			opts.Child(ctrl.Button(opts.Key("visible"), opts.Width(50), opts.Height(50))),
+		), surface.NewSurface())
+		assert.Equal(spec.CoordToControl(root, 10, 10).Key(), "visible")
+	})
+}
+
+func TestZIndex(t *testing.T) {
+	var keys = func(nodes []spec.ReadWriter) string {
+		result := ""
+		for _, node := range nodes {
+			result += node.Key()
+		}
+		return result
+	}
+
+	var create = func() spec.ReadWriter {
+		return layout.Layout(ctrl.Box(
+			opts.Width(100),
+			opts.Height(100),
+			opts.HAlign(spec.AlignLeft),
+			opts.VAlign(spec.AlignTop),
+			opts.Child(ctrl.Button(opts.Key("a"), opts.Width(50), opts.Height(50), opts.ZIndex(1))),
+			opts.Child(ctrl.Button(opts.Key("b"), opts.Width(50), opts.Height(50))),
+			opts.Child(ctrl.Button(opts.Key("c"), opts.Width(50), opts.Height(50))),
+		), surface.NewSurface())
+	}
+
+	t.Run("DrawOrder sorts by ZIndex and keeps child order", func(t *testing.T) {
+		assert.Equal(keys(spec.DrawOrder(create())), "bca")
+	})
+
+	t.Run("DrawOrder leaves out hidden children and overlays", func(t *testing.T) {
+		root := create()
+		spec.FirstByKey(root, "b").SetVisible(false)
+		spec.FirstByKey(root, "c").SetIsOverlay(true)
+		assert.Equal(keys(spec.DrawOrder(root)), "a")
+	})
+
+	t.Run("Hit tests the topmost child", func(t *testing.T) {
+		root := create()
+		assert.Equal(spec.CoordToControl(root, 10, 10).Key(), "a")
+		spec.FirstByKey(root, "a").SetZIndex(0)
+		assert.Equal(spec.CoordToControl(root, 10, 10).Key(), "c")
+	})
+}
+
+func TestOverlays(t *testing.T) {
+	var create = func() spec.ReadWriter {
+		return layout.Layout(ctrl.VBox(
+			opts.Width(100),
+			opts.Height(100),
+			opts.HAlign(spec.AlignLeft),
+			opts.Gutter(0),
+			opts.Child(ctrl.Box(
+				opts.Key("menu"),
+				opts.Width(100),
+				opts.Height(20),
+				opts.Child(ctrl.Box(
+					opts.Key("popup"),
+					opts.IsOverlay(true),
+					opts.VAlign(spec.AlignTop),
+					opts.Y(20),
+					opts.Width(60),
+					opts.Height(60),
+					opts.Child(ctrl.Button(opts.Key("item"), opts.Width(60), opts.Height(20))),
+				)),
+			)),
+			opts.Child(ctrl.Button(opts.Key("content"), opts.Width(100), opts.Height(80), opts.ZIndex(10))),
+		), surface.NewSurface())
+	}
+
+	t.Run("Are collected from the tree", func(t *testing.T) {
+		root := create()
+		overlays := spec.Overlays(root)
+		assert.Equal(len(overlays), 1)
+		assert.Equal(overlays[0].Key(), "popup")
+	})
+
+	t.Run("Do not take space in the layout", func(t *testing.T) {
+		root := create()
+		assert.Equal(spec.FirstByKey(root, "content").Y(), 20)
+	})
+
+	t.Run("Are hit tested first", func(t *testing.T) {
+		root := create()
+		assert.Equal(spec.CoordToControl(root, 10, 30).Key(), "item")
+		assert.Equal(spec.CoordToControl(root, 80, 30).Key(), "content")
+	})
+
+	t.Run("Hidden overlays are not collected", func(t *testing.T) {
+		root := create()
+		spec.FirstByKey(root, "menu").SetVisible(false)
+		assert.Equal(len(spec.Overlays(root)), 0)
+		assert.Equal(spec.CoordToControl(root, 10, 30).Key(), "content")
+	})
+}
@@ -0,0 +1,165 @@
+package spec
+
+import (
+	"errors"
+	"sort"
+)
+
+// StyleRule assigns a collection of Options to every node that matches a
+// selector.
+type StyleRule struct {
+	options  []Option
+	selector *Selector
+}
+
+// Selector returns the parsed selector for this rule.
+func (r *StyleRule) Selector() *Selector {
+	return r.selector
+}
+
+// Options returns the Options that will be applied to matching nodes.
+func (r *StyleRule) Options() []Option {
+	return r.options
+}
+
+// Rule creates a StyleRule that will apply the provided Options to every
+// node that matches the provided selector. Like QuerySelector, this function
+// will panic if the selector cannot be parsed.
+//
+// When the right-most entry of the selector includes a single state (e.g.,
+// "Button:hovered"), the Options will be registered with Spec.OnState for
+// that state rather than only being applied while the node is in it.
+func Rule(selector string, options ...Option) *StyleRule {
+	return &StyleRule{
+		options:  options,
+		selector: mustParseSelector(selector),
+	}
+}
+
+// styleEntry is a single selector alternative from a StyleRule, along with
+// the information needed to sort it against the other entries.
+type styleEntry struct {
+	matcher     *complexSelector
+	options     []Option
+	order       int
+	specificity int
+	state       string
+}
+
+// Stylesheet is an ordered collection of StyleRules that can be applied to a
+// Spec tree. Rules are applied from least to most specific, and rules of
+// equal specificity are applied in the order they were added.
+//
+// Like author styles on the web, Stylesheet rules take precedence over the
+// Options and state Options that a Spec declares for itself.
+type Stylesheet struct {
+	entries []*styleEntry
+	rules   []*StyleRule
+}
+
+// Add appends the provided rules to the Stylesheet.
+func (s *Stylesheet) Add(rules ...*StyleRule) *Stylesheet {
+	for _, rule := range rules {
+		s.rules = append(s.rules, rule)
+		for _, alternative := range rule.selector.alternatives {
+			entry := newStyleEntry(rule.selector, alternative, rule.options, len(s.entries))
+			s.entries = append(s.entries, entry)
+		}
+	}
+	return s
+}
+
+// Rules returns the rules in the order they were added.
+func (s *Stylesheet) Rules() []*StyleRule {
+	return s.rules
+}
+
+// Apply assigns the Options from every matching rule to each node in the
+// provided tree. This is expected to be called once on each newly rendered
+// tree, before it is laid out.
+func (s *Stylesheet) Apply(root ReadWriter) ReadWriter {
+	s.applyTo(root)
+	for _, child := range root.Children() {
+		s.Apply(child)
+	}
+	return root
+}
+
+func (s *Stylesheet) applyTo(rw ReadWriter) {
+	matched := []*styleEntry{}
+	for _, entry := range s.entries {
+		if entry.matcher.matches(rw) {
+			matched = append(matched, entry)
+		}
+	}
+	if len(matched) == 0 {
+		return
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		if matched[i].specificity == matched[j].specificity {
+			return matched[i].order < matched[j].order
+		}
+		return matched[i].specificity < matched[j].specificity
+	})
+
+	// Calling OnState on a Spec without states will change the current
+	// state, so we hold on to it and restore it afterward.
+	currentState := rw.State()
+	for _, entry := range matched {
+		if entry.state == "" {
+			for _, option := range entry.options {
+				option(rw)
+			}
+			// Options declared by the Spec itself for a given state would
+			// otherwise replace these whenever that state is applied.
+			for _, state := range rw.States() {
+				appendStateOptions(rw, state, entry.options)
+			}
+			continue
+		}
+		appendStateOptions(rw, entry.state, entry.options)
+	}
+	rw.SetState(currentState)
+	applyOptionsForState(rw)
+}
+
+func appendStateOptions(rw ReadWriter, state string, options []Option) {
+	stateOptions := append([]Option{}, rw.OptionsForState(state)...)
+	rw.OnState(state, append(stateOptions, options...)...)
+}
+
+// newStyleEntry creates an entry for the provided selector alternative. When
+// the right-most compound selector includes a state, the returned entry will
+// match regardless of the current state and will register its options for
+// that state instead. Like mustParseSelector, this function will panic for
+// alternatives that require more than one state at a time, as a Spec can
+// only be in one state.
+func newStyleEntry(selector *Selector, alternative *complexSelector, options []Option, order int) *styleEntry {
+	entry := &styleEntry{
+		matcher:     alternative,
+		options:     options,
+		order:       order,
+		specificity: alternative.specificity(),
+	}
+
+	last := alternative.parts[len(alternative.parts)-1]
+	switch len(last.states) {
+	case 0:
+		return entry
+	case 1:
+		stateless := *last
+		stateless.states = nil
+		parts := append([]*compoundSelector{}, alternative.parts[:len(alternative.parts)-1]...)
+		entry.matcher = &complexSelector{parts: append(parts, &stateless)}
+		entry.state = last.states[0]
+		return entry
+	default:
+		panic(errors.New("invalid style rule \"" + selector.String() + "\": more than one state"))
+	}
+}
+
+// NewStylesheet creates a Stylesheet with the provided rules.
+func NewStylesheet(rules ...*StyleRule) *Stylesheet {
+	return (&Stylesheet{}).Add(rules...)
+}
@@ -0,0 +1,110 @@
+package spec_test
+
+import (
+	"testing"
+
+	"github.com/waybeams/assert"
+	"github.com/waybeams/waybeams/pkg/ctrl"
+	"github.com/waybeams/waybeams/pkg/opts"
+	"github.com/waybeams/waybeams/pkg/spec"
+)
+
+func TestStylesheet(t *testing.T) {
+	var createTree = func() spec.ReadWriter {
+		return ctrl.VBox(
+			opts.Key("root"),
+			opts.Child(ctrl.HBox(
+				opts.Key("toolbar"),
+				opts.Child(ctrl.Button(opts.Key("one"))),
+				opts.Child(ctrl.Button(opts.Key("two"))),
+			)),
+			opts.Child(ctrl.Label(opts.Key("label"))),
+		)
+	}
+
+	t.Run("Instantiable", func(t *testing.T) {
+		sheet := spec.NewStylesheet(spec.Rule("Button", opts.Padding(10)))
+		assert.Equal(len(sheet.Rules()), 1)
+		assert.Equal(sheet.Rules()[0].Selector().String(), "Button")
+	})
+
+	t.Run("Invalid selector panics", func(t *testing.T) {
+		assert.Panic("invalid selector", func() {
+			spec.Rule("Button >", opts.Padding(10))
+		})
+	})
+
+	t.Run("More than one state panics", func(t *testing.T) {
+		assert.Panic("more than one state", func() {
+			spec.NewStylesheet(spec.Rule("Button:hovered:pressed", opts.Padding(10)))
+		})
+	})
+
+	t.Run("Applies options to matching specs", func(t *testing.T) {
+		tree := spec.NewStylesheet(
+			spec.Rule("Button", opts.FontSize(12)),
+			spec.Rule("Label", opts.FontSize(14)),
+		).Apply(createTree())
+
+		assert.Equal(spec.FirstByKey(tree, "one").FontSize(), 12)
+		assert.Equal(spec.FirstByKey(tree, "two").FontSize(), 12)
+		assert.Equal(spec.FirstByKey(tree, "label").FontSize(), 14)
+		assert.Equal(tree.FontSize(), spec.DefaultFontSize)
+	})
+
+	t.Run("More specific rules win regardless of order", func(t *testing.T) {
+		tree := spec.NewStylesheet(
+			spec.Rule("#two", opts.FontSize(30)),
+			spec.Rule("HBox > Button", opts.FontSize(20)),
+			spec.Rule("Button", opts.FontSize(10)),
+		).Apply(createTree())
+
+		assert.Equal(spec.FirstByKey(tree, "one").FontSize(), 20)
+		assert.Equal(spec.FirstByKey(tree, "two").FontSize(), 30)
+	})
+
+	t.Run("Later rules win with equal specificity", func(t *testing.T) {
+		tree := spec.NewStylesheet(
+			spec.Rule("Button", opts.FontSize(10)),
+			spec.Rule("Button", opts.FontSize(11)),
+		).Apply(createTree())
+
+		assert.Equal(spec.FirstByKey(tree, "one").FontSize(), 11)
+	})
+
+	t.Run("State rules are registered with OnState", func(t *testing.T) {
+		tree := spec.NewStylesheet(
+			spec.Rule("Button", opts.BgColor(0x111111ff)),
+			spec.Rule("Button:hovered", opts.BgColor(0x222222ff)),
+		).Apply(createTree())
+
+		one := spec.FirstByKey(tree, "one")
+		assert.Equal(one.State(), "active")
+		assert.Equal(one.BgColor(), 0x111111ff)
+
+		one.SetState("hovered")
+		spec.Apply(one)
+		assert.Equal(one.BgColor(), 0x222222ff)
+	})
+
+	t.Run("State rules do not change current state", func(t *testing.T) {
+		tree := spec.NewStylesheet(
+			spec.Rule("Label:hovered", opts.BgColor(0x222222ff)),
+		).Apply(createTree())
+
+		label := spec.FirstByKey(tree, "label")
+		assert.Equal(label.State(), "")
+		assert.True(label.HasState("hovered"))
+	})
+
+	t.Run("Rules take precedence over Spec state options", func(t *testing.T) {
+		button := ctrl.Button(opts.Key("one"))
+		spec.NewStylesheet(spec.Rule("Button", opts.BgColor(0x111111ff))).Apply(button)
+		assert.Equal(button.BgColor(), 0x111111ff)
+
+		// Button declares its own "hovered" BgColor.
+		button.SetState("hovered")
+		spec.Apply(button)
+		assert.Equal(button.BgColor(), 0x111111ff)
+	})
+}
@@ -45,6 +45,24 @@ type Surface interface {
 	// Stroke draws a stroke around the previous shape.
 	Stroke()
 
+	// Scissor limits all further drawing to the rectangle from x and y to
+	// width and height, replacing any previous scissor.
+	Scissor(x, y, width, height float64)
+
+	// ResetScissor removes the scissor so that drawing is no longer limited.
+	ResetScissor()
+
+	// IntersectScissor limits all further drawing to the intersection of the
+	// current scissor and the rectangle from x and y to width and height.
+	IntersectScissor(x, y, width, height float64)
+
+	// Save pushes the current drawing state, including the scissor, onto a
+	// stack.
+	Save()
+
+	// Restore pops the drawing state that was pushed by the matching Save.
+	Restore()
+
 	// GetOffsetSurfaceFor provides offset surface for nested controls so that
 	// they can use local coordinates for positioning.
 	// GetOffsetSurfaceFor(d Reader) Surface
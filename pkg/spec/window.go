@@ -22,6 +22,17 @@ type Window interface {
 	UpdateInput(root ReadWriter)
 }
 
+// PartialWindow is implemented by Windows that can begin a frame that only
+// redraws the provided region, and keep the rest of the window from an
+// earlier frame. Other Windows are always redrawn entirely.
+type PartialWindow interface {
+	// BufferAge returns how many frames ago the content that the next frame
+	// begins with was drawn (e.g., 1 for the previous frame), or 0 if that
+	// content is unknown and the next frame must be drawn entirely.
+	BufferAge() int
+	BeginPartialFrame(region BoundingBox)
+}
+
 type InputController interface {
 	Update(root ReadWriter)
 }
@@ -42,6 +42,19 @@ func LabelView(s spec.Surface, r spec.Reader) {
 		s.SetFontSize(r.FontSize())
 		s.SetFontFace(r.FontFace())
 		s.SetFillColor(r.FontColor())
+		if lines, ok := r.(textLinesReader); ok {
+			for index, line := range lines.TextLines() {
+				s.Text(r.TextX(), r.TextY()+float64(index)*lines.LineHeight(), line)
+			}
+			return
+		}
 		s.Text(r.TextX(), r.TextY(), r.Text())
 	}
 }
+
+// textLinesReader is implemented by Specs that draw their Text on more than
+// one line.
+type textLinesReader interface {
+	LineHeight() float64
+	TextLines() []string
+}